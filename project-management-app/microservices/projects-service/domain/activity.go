@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ACTIVITY_PROJECT_CREATED      = "project.created"
+	ACTIVITY_PROJECT_CLONED       = "project.cloned"
+	ACTIVITY_MEMBER_ADDED         = "member.added"
+	ACTIVITY_MEMBER_REMOVED       = "member.removed"
+	ACTIVITY_TEMPLATE_SAVED       = "template.saved"
+	ACTIVITY_OWNERSHIP_REQUESTED  = "ownership.requested"
+	ACTIVITY_OWNERSHIP_ACCEPTED   = "ownership.accepted"
+	ACTIVITY_OWNERSHIP_REJECTED   = "ownership.rejected"
+	ACTIVITY_OWNERSHIP_CANCELLED  = "ownership.cancelled"
+	ACTIVITY_OWNERSHIP_REASSIGNED = "ownership.reassigned"
+)
+
+// Activity is a single append-only entry of a project's audit log. Entries
+// are written by projects-service itself and by tasks-service for every
+// mutating operation.
+type Activity struct {
+	Id         primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Project    string                 `bson:"project" json:"project"`
+	Actor      string                 `bson:"actor" json:"actor"`
+	Action     string                 `bson:"action" json:"action"`
+	TargetType string                 `bson:"target_type" json:"target_type"`
+	Target     string                 `bson:"target" json:"target"`
+	Before     map[string]interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After      map[string]interface{} `bson:"after,omitempty" json:"after,omitempty"`
+	TraceId    string                 `bson:"trace_id,omitempty" json:"trace_id,omitempty"`
+	Source     string                 `bson:"source" json:"source"`
+	CreatedAt  time.Time              `bson:"created_at" json:"created_at"`
+}
+
+type Activities []*Activity
+
+// ActivityFilter narrows down the activity feed of a project.
+type ActivityFilter struct {
+	Actor  string
+	Action string
+}
+
+type ActivityPage struct {
+	Items Activities `json:"items"`
+	Total int64      `json:"total"`
+	Page  int        `json:"page"`
+	Size  int        `json:"size"`
+}
+
+func (a *Activity) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(a)
+}
+
+func (p *ActivityPage) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(p)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+const (
+	MEMBER_ADDED   = "added"
+	MEMBER_REMOVED = "removed"
+	MEMBER_INVALID = "invalid"
+	MEMBER_SKIPPED = "skipped"
+)
+
+// MemberResult is the outcome of a bulk member change for a single user.
+type MemberResult struct {
+	Username string `json:"username"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Notified bool   `json:"notified"`
+}
+
+// MemberBatch is the response of a bulk member change. Changes are applied
+// all at once: if a single user is invalid, none of them is applied.
+type MemberBatch struct {
+	Project string         `json:"project"`
+	Applied bool           `json:"applied"`
+	Results []MemberResult `json:"results"`
+}
+
+func (b *MemberBatch) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(b)
+}
+
+// ProjectAccess lists who may work on the tasks of a project. tasks-service
+// asks for it to authorize requests on tasks, and uses the name and the end
+// date for project timelines.
+type ProjectAccess struct {
+	Project string    `json:"project"`
+	Name    string    `json:"name"`
+	EndDate time.Time `json:"end_date"`
+	Manager string    `json:"manager"`
+	Members []string  `json:"members"`
+}
+
+func (a *ProjectAccess) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(a)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ProjectStats is the dashboard summary of a project, built from the task
+// statistics reported by tasks-service.
+type ProjectStats struct {
+	Project     string           `json:"project"`
+	Name        string           `json:"name"`
+	Total       int              `json:"total"`
+	ByStatus    map[string]int   `json:"by_status"`
+	Finished    int              `json:"finished"`
+	Overdue     int              `json:"overdue"`
+	Completion  float64          `json:"completion"`
+	Workload    []MemberWorkload `json:"workload"`
+	Trend       []TrendPoint     `json:"trend"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+type MemberWorkload struct {
+	Username string `json:"username"`
+	Total    int    `json:"total"`
+	Open     int    `json:"open"`
+	Overdue  int    `json:"overdue"`
+}
+
+type TrendPoint struct {
+	Date     string `json:"date"`
+	Created  int    `json:"created"`
+	Finished int    `json:"finished"`
+}
+
+func (s *ProjectStats) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(s)
+}
+
+func (s *ProjectStats) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(s)
+}
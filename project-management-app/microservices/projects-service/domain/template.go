@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Template is a reusable project skeleton: the project settings plus the
+// list of tasks every project created from it starts with.
+type Template struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name       string             `bson:"name" json:"name"`
+	Manager    User               `bson:"manager" json:"manager"`
+	MinWorkers int                `bson:"min_workers,omitempty" json:"min_workers"`
+	MaxWorkers int                `bson:"max_workers" json:"max_workers"`
+	Tasks      TemplateTasks      `bson:"tasks" json:"tasks"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type Templates []*Template
+
+type TemplateTask struct {
+	Name        string `bson:"name" json:"name"`
+	Description string `bson:"description" json:"description"`
+}
+
+type TemplateTasks []TemplateTask
+
+// TaskResult reports what happened to a single task while a project was
+// being created from a template or cloned.
+type TaskResult struct {
+	Name  string `json:"name"`
+	Id    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func (t *Template) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(t)
+}
+
+func (t *Template) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(t)
+}
+
+func (t *Templates) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(t)
+}
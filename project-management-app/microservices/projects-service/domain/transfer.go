@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TransferStatus string
+
+const (
+	TRANSFER_PENDING   TransferStatus = "PENDING"
+	TRANSFER_ACCEPTED  TransferStatus = "ACCEPTED"
+	TRANSFER_REJECTED  TransferStatus = "REJECTED"
+	TRANSFER_CANCELLED TransferStatus = "CANCELLED"
+)
+
+// OwnershipTransfer is a request to hand a project over to another manager.
+// Accepted transfers make up the ownership history of a project.
+type OwnershipTransfer struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectId  primitive.ObjectID `bson:"project_id" json:"project_id"`
+	Project    string             `bson:"project" json:"project"`
+	From       User               `bson:"from" json:"from"`
+	To         User               `bson:"to" json:"to"`
+	Status     TransferStatus     `bson:"status" json:"status"`
+	Bulk       bool               `bson:"bulk,omitempty" json:"bulk,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	ResolvedAt *time.Time         `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+}
+
+type OwnershipTransfers []*OwnershipTransfer
+
+func (t *OwnershipTransfer) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(t)
+}
+
+func (t *OwnershipTransfers) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(t)
+}
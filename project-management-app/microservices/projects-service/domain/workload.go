@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+type ProjectLoad struct {
+	Project       string  `json:"project"`
+	Name          string  `json:"name,omitempty"`
+	Open          int     `json:"open"`
+	EstimateHours float64 `json:"estimate_hours"`
+	StoryPoints   float64 `json:"story_points"`
+}
+
+// MemberCapacity is the open work of a member across all projects, as
+// reported by tasks-service, compared to the hours the member has. Names of
+// projects the caller doesn't manage are left out.
+type MemberCapacity struct {
+	Username      string        `json:"username"`
+	Open          int           `json:"open"`
+	InProgress    int           `json:"in_progress"`
+	Overdue       int           `json:"overdue"`
+	EstimateHours float64       `json:"estimate_hours"`
+	StoryPoints   float64       `json:"story_points"`
+	Projects      []ProjectLoad `json:"projects"`
+	Utilization   float64       `json:"utilization"`
+	Overloaded    bool          `json:"overloaded"`
+}
+
+// Capacity lists the members of the caller's projects, most loaded first.
+type Capacity struct {
+	CapacityHours float64           `json:"capacity_hours"`
+	Members       []*MemberCapacity `json:"members"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+}
+
+func (c *Capacity) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(c)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"strconv"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (p *ProjectHandler) GetActivity(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.GetActivity")
+	defer span.End()
+
+	id := mux.Vars(h)["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+	role := h.Context().Value(authorizationlib.RoleKey).(string)
+
+	query := h.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	size, _ := strconv.Atoi(query.Get("size"))
+	filter := domain.ActivityFilter{
+		Actor:  query.Get("actor"),
+		Action: query.Get("action"),
+	}
+
+	activities, err := p.projects.GetActivity(ctx, id, username, role, filter, page, size)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	err = activities.ToJSON(rw)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(rw, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RecordActivity receives activity entries from tasks-service. The actor is
+// taken from the body, so the route is internal and the API gateway requests
+// are rejected before they get here.
+func (p *ProjectHandler) RecordActivity(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.RecordActivity")
+	defer span.End()
+
+	activity := &domain.Activity{}
+	if err := activity.FromJSON(h.Body); err != nil {
+		http.Error(rw, "Unable to decode json", http.StatusBadRequest)
+		return
+	}
+	if activity.Action == "" || activity.Actor == "" {
+		http.Error(rw, "actor and action are required", http.StatusBadRequest)
+		return
+	}
+
+	activity.Id = primitive.NilObjectID
+	activity.Project = mux.Vars(h)["id"]
+	if activity.Source == "" {
+		activity.Source = "tasks-service"
+	}
+
+	if err := p.projects.RecordActivity(ctx, activity); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	rw.WriteHeader(http.StatusCreated)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type memberBatchRequest struct {
+	Usernames []string `json:"usernames"`
+}
+
+func (p *ProjectHandler) AddMembers(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.AddMembers")
+	defer span.End()
+
+	id := mux.Vars(h)["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+	role := h.Context().Value(authorizationlib.RoleKey).(string)
+
+	req := &memberBatchRequest{}
+	if err := readReq(req, h, rw); err != nil {
+		return
+	}
+
+	batch, err := p.projects.AddMembers(ctx, id, req.Usernames, username, role)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeMemberBatch(batch, rw)
+}
+
+func (p *ProjectHandler) RemoveMembers(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.RemoveMembers")
+	defer span.End()
+
+	id := mux.Vars(h)["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+	role := h.Context().Value(authorizationlib.RoleKey).(string)
+
+	req := &memberBatchRequest{}
+	if err := readReq(req, h, rw); err != nil {
+		return
+	}
+
+	batch, err := p.projects.RemoveMembers(ctx, id, req.Usernames, username, role)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeMemberBatch(batch, rw)
+}
+
+// writeMemberBatch answers with the per-user results, using 422 when the
+// batch was rejected because of invalid users.
+func writeMemberBatch(batch *domain.MemberBatch, rw http.ResponseWriter) {
+	if batch.Applied {
+		rw.WriteHeader(http.StatusOK)
+	} else {
+		rw.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	if err := batch.ToJSON(rw); err != nil {
+		log.Println("Unable to convert to json:", err)
+	}
+}
+
+// GetAccess is called by tasks-service to check who may work on the tasks of
+// a project.
+func (p *ProjectHandler) GetAccess(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.GetAccess")
+	defer span.End()
+
+	access, err := p.projects.GetAccess(ctx, mux.Vars(h)["id"])
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	err = access.ToJSON(rw)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(rw, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
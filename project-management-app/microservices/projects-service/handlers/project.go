@@ -32,7 +32,11 @@ func (p *ProjectHandler) Create(rw http.ResponseWriter, h *http.Request) {
 	ctx, span := p.tracer.Start(h.Context(), "OrderHandler.GetOrder")
 	defer span.End()
 	project := h.Context().Value(KeyProduct{}).(*domain.Project)
-	p.repo.Create(ctx, project)
+	if err := p.projects.Create(ctx, project); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
 	err := project.ToJSON(rw)
 	if err != nil {
 		http.Error(rw, "Unable to convert to json", http.StatusInternalServerError)
@@ -160,7 +164,7 @@ func (h ProjectHandler) AddMember(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Pozivamo ProjectService da doda korisnika u projekat
-	err = h.projects.AddMember(id, *user)
+	err = h.projects.AddMember(r.Context(), id, *user)
 	if err != nil {
 		writeErrorResp(err, w)
 		return
@@ -175,7 +179,7 @@ func (h ProjectHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 	id := vars["id"]
 	username := vars["username"]
 
-	h.projects.RemoveMember(id, username)
+	h.projects.RemoveMember(r.Context(), id, username)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -189,6 +193,18 @@ func (u *ProjectHandler) MiddlewareContentTypeSet(next http.Handler) http.Handle
 	})
 }
 
+// MiddlewareInternal keeps the routes meant for other services off the API
+// gateway, which marks every request it forwards with X-Forwarded-Prefix.
+func (p *ProjectHandler) MiddlewareInternal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, h *http.Request) {
+		if h.Header.Get("X-Forwarded-Prefix") != "" {
+			writeErrorResp(domain.ErrUnauthorized(), rw)
+			return
+		}
+		next.ServeHTTP(rw, h)
+	})
+}
+
 func (u *ProjectHandler) ProjectContextMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, h *http.Request) {
 
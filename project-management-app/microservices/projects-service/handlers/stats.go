@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (p *ProjectHandler) GetStats(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.GetStats")
+	defer span.End()
+
+	id := mux.Vars(h)["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+	role := h.Context().Value(authorizationlib.RoleKey).(string)
+	days, _ := strconv.Atoi(h.URL.Query().Get("days"))
+
+	stats, err := p.projects.GetStats(ctx, id, username, role, days)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	err = stats.ToJSON(rw)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(rw, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (p *ProjectHandler) SaveAsTemplate(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.SaveAsTemplate")
+	defer span.End()
+
+	vars := mux.Vars(h)
+	id := vars["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	req := &struct {
+		Name string `json:"name"`
+	}{}
+	if err := readReq(req, h, rw); err != nil {
+		return
+	}
+
+	template, err := p.projects.SaveAsTemplate(ctx, id, req.Name, username)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeResp(template, http.StatusCreated, rw)
+}
+
+func (p *ProjectHandler) GetTemplates(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.GetTemplates")
+	defer span.End()
+
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	templates, err := p.projects.GetTemplates(ctx, username)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	err = templates.ToJSON(rw)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(rw, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (p *ProjectHandler) DeleteTemplate(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.DeleteTemplate")
+	defer span.End()
+
+	vars := mux.Vars(h)
+	id := vars["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	if err := p.projects.DeleteTemplate(ctx, id, username); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (p *ProjectHandler) CreateFromTemplate(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.CreateFromTemplate")
+	defer span.End()
+
+	vars := mux.Vars(h)
+	id := vars["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	req := &struct {
+		Name    string    `json:"name"`
+		EndDate time.Time `json:"end_date"`
+	}{}
+	if err := readReq(req, h, rw); err != nil {
+		return
+	}
+
+	project, tasks, err := p.projects.CreateFromTemplate(ctx, id, req.Name, req.EndDate, username, h.Header.Get("Authorization"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeResp(createdProjectResponse(project, tasks), http.StatusCreated, rw)
+}
+
+func (p *ProjectHandler) Clone(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.Clone")
+	defer span.End()
+
+	vars := mux.Vars(h)
+	id := vars["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	req := &struct {
+		Name           string    `json:"name"`
+		EndDate        time.Time `json:"end_date"`
+		IncludeMembers bool      `json:"include_members"`
+	}{}
+	if err := readReq(req, h, rw); err != nil {
+		return
+	}
+
+	project, tasks, err := p.projects.Clone(ctx, id, req.Name, req.EndDate, req.IncludeMembers, username, h.Header.Get("Authorization"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeResp(createdProjectResponse(project, tasks), http.StatusCreated, rw)
+}
+
+func createdProjectResponse(project *domain.Project, tasks []domain.TaskResult) map[string]interface{} {
+	return map[string]interface{}{
+		"project": project,
+		"tasks":   tasks,
+	}
+}
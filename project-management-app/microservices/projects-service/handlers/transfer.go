@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (p *ProjectHandler) RequestTransfer(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.RequestTransfer")
+	defer span.End()
+
+	vars := mux.Vars(h)
+	id := vars["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	req := &struct {
+		Username string `json:"username"`
+	}{}
+	if err := readReq(req, h, rw); err != nil {
+		return
+	}
+
+	transfer, err := p.projects.RequestTransfer(ctx, id, username, req.Username)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeResp(transfer, http.StatusCreated, rw)
+}
+
+func (p *ProjectHandler) GetPendingTransfers(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.GetPendingTransfers")
+	defer span.End()
+
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	transfers, err := p.projects.GetPendingTransfers(ctx, username)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	err = transfers.ToJSON(rw)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(rw, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (p *ProjectHandler) GetOwnershipHistory(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.GetOwnershipHistory")
+	defer span.End()
+
+	vars := mux.Vars(h)
+	id := vars["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+	role := h.Context().Value(authorizationlib.RoleKey).(string)
+
+	transfers, err := p.projects.GetOwnershipHistory(ctx, id, username, role)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	err = transfers.ToJSON(rw)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(rw, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (p *ProjectHandler) AcceptTransfer(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.AcceptTransfer")
+	defer span.End()
+
+	id := mux.Vars(h)["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	transfer, err := p.projects.AcceptTransfer(ctx, id, username)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeResp(transfer, http.StatusOK, rw)
+}
+
+func (p *ProjectHandler) RejectTransfer(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.RejectTransfer")
+	defer span.End()
+
+	id := mux.Vars(h)["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	transfer, err := p.projects.RejectTransfer(ctx, id, username)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeResp(transfer, http.StatusOK, rw)
+}
+
+func (p *ProjectHandler) CancelTransfer(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.CancelTransfer")
+	defer span.End()
+
+	id := mux.Vars(h)["id"]
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+
+	transfer, err := p.projects.CancelTransfer(ctx, id, username)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeResp(transfer, http.StatusOK, rw)
+}
+
+// ReassignAll is called by users-service before a manager is deleted. The
+// route is internal, the API gateway doesn't forward it.
+func (p *ProjectHandler) ReassignAll(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.ReassignAll")
+	defer span.End()
+
+	username := mux.Vars(h)["username"]
+
+	req := &struct {
+		Username string `json:"username"`
+	}{}
+	if err := readReq(req, h, rw); err != nil {
+		return
+	}
+
+	transfers, err := p.projects.ReassignAll(ctx, username, req.Username)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		log.Printf("Error reassigning projects of manager %s: %v", username, err)
+		writeErrorResp(err, rw)
+		return
+	}
+
+	writeResp(transfers, http.StatusOK, rw)
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (p *ProjectHandler) GetCapacity(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "ProjectsHandler.GetCapacity")
+	defer span.End()
+
+	username := h.Context().Value(authorizationlib.UsernameKey).(string)
+	role := h.Context().Value(authorizationlib.RoleKey).(string)
+	projectId := h.URL.Query().Get("project")
+	capacityHours, _ := strconv.ParseFloat(h.URL.Query().Get("capacity"), 64)
+
+	capacity, err := p.projects.GetCapacity(ctx, projectId, username, role, capacityHours)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeErrorResp(err, rw)
+		return
+	}
+
+	err = capacity.ToJSON(rw)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(rw, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
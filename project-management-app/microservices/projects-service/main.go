@@ -55,7 +55,7 @@ func main() {
 	projectRepository, err := repositories.New(timeoutContext, storeLogger, tracer)
 	handleErr(err)
 
-	projectService := services.NewProjectService(projectRepository)
+	projectService := services.NewProjectService(projectRepository, tracer)
 	var secretKey = []byte(os.Getenv("SECRET_KEY_AUTH"))
 
 	projectHandler := handlers.NewprojectHandler(projectService, projectRepository, tracer)
@@ -69,7 +69,11 @@ func main() {
 	privateRouter := router.NewRoute().Subrouter()
 	privateRouter.Use(authHandler.MiddlewareAuth)
 	privateRouter.HandleFunc("/projects", projectHandler.GetProjectsByUser).Methods("GET")
+	privateRouter.HandleFunc("/projects/workload", projectHandler.GetCapacity).Methods("GET")
 	privateRouter.HandleFunc("/projects/{id}", projectHandler.GetByID).Methods("GET")
+	privateRouter.HandleFunc("/projects/{id}/ownership", projectHandler.GetOwnershipHistory).Methods("GET")
+	privateRouter.HandleFunc("/projects/{id}/activity", projectHandler.GetActivity).Methods("GET")
+	privateRouter.HandleFunc("/projects/{id}/stats", projectHandler.GetStats).Methods("GET")
 
 	managerRouter := router.NewRoute().Subrouter()
 	managerRouter.Use(authHandler.MiddlewareAuthManager)
@@ -77,8 +81,14 @@ func main() {
 	getRouter := router.Methods(http.MethodGet).Subrouter()
 	getRouter.HandleFunc("/allProjects", projectHandler.GetAll).Methods("GET")
 	getRouter.HandleFunc("/projects/members/{id}", projectHandler.GetMembersByID).Methods("GET")
+	getRouter.HandleFunc("/projects/access/{id}", projectHandler.GetAccess).Methods("GET")
 	getRouter.HandleFunc("/projects/manager/{username}", projectHandler.GetProjectsByManagerAndIsActive).Methods("GET")
 
+	internalRouter := router.NewRoute().Subrouter()
+	internalRouter.Use(projectHandler.MiddlewareInternal)
+	internalRouter.HandleFunc("/projects/manager/{username}/reassign", projectHandler.ReassignAll).Methods(http.MethodPost)
+	internalRouter.HandleFunc("/projects/{id}/activity", projectHandler.RecordActivity).Methods(http.MethodPost)
+
 	postRouter := managerRouter.Methods(http.MethodPost).Subrouter()
 	postRouter.HandleFunc("/projects", projectHandler.Create).Methods("POST")
 	postRouter.Use(projectHandler.ProjectContextMiddleware)
@@ -89,6 +99,24 @@ func main() {
 	deleteRouter := managerRouter.Methods(http.MethodDelete).Subrouter()
 	deleteRouter.HandleFunc("/projects/{id}/members/{username}", projectHandler.RemoveMember).Methods("DELETE")
 
+	templateRouter := managerRouter.NewRoute().Subrouter()
+	templateRouter.HandleFunc("/templates", projectHandler.GetTemplates).Methods(http.MethodGet)
+	templateRouter.HandleFunc("/templates/{id}", projectHandler.DeleteTemplate).Methods(http.MethodDelete)
+	templateRouter.HandleFunc("/templates/{id}/projects", projectHandler.CreateFromTemplate).Methods(http.MethodPost)
+	templateRouter.HandleFunc("/projects/{id}/template", projectHandler.SaveAsTemplate).Methods(http.MethodPost)
+	templateRouter.HandleFunc("/projects/{id}/clone", projectHandler.Clone).Methods(http.MethodPost)
+
+	memberRouter := managerRouter.NewRoute().Subrouter()
+	memberRouter.HandleFunc("/projects/{id}/members", projectHandler.AddMembers).Methods(http.MethodPost)
+	memberRouter.HandleFunc("/projects/{id}/members/remove", projectHandler.RemoveMembers).Methods(http.MethodPost)
+
+	transferRouter := managerRouter.NewRoute().Subrouter()
+	transferRouter.HandleFunc("/projects/{id}/transfer", projectHandler.RequestTransfer).Methods(http.MethodPost)
+	transferRouter.HandleFunc("/transfers", projectHandler.GetPendingTransfers).Methods(http.MethodGet)
+	transferRouter.HandleFunc("/transfers/{id}/accept", projectHandler.AcceptTransfer).Methods(http.MethodPatch)
+	transferRouter.HandleFunc("/transfers/{id}/reject", projectHandler.RejectTransfer).Methods(http.MethodPatch)
+	transferRouter.HandleFunc("/transfers/{id}/cancel", projectHandler.CancelTransfer).Methods(http.MethodPatch)
+
 	server := &http.Server{
 		Handler: router,
 		Addr:    address,
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *ProjectRepo) getActivityCollection() *mongo.Collection {
+	projectDatabase := pr.cli.Database("projects")
+	activitiesCollection := projectDatabase.Collection("activities")
+	return activitiesCollection
+}
+
+// InsertActivity appends an entry to the activity log. The log is append-only,
+// entries are never updated or deleted.
+func (pr *ProjectRepo) InsertActivity(ctx context.Context, activity *domain.Activity) error {
+	ctx, span := pr.tracer.Start(ctx, "ProjectsRepo.InsertActivity")
+	defer span.End()
+
+	_, err := pr.getActivityCollection().InsertOne(ctx, activity)
+	if err != nil {
+		pr.logger.Println("Error inserting activity:", err)
+		return err
+	}
+	return nil
+}
+
+// GetActivities returns one page of a project's activity, newest first.
+func (pr *ProjectRepo) GetActivities(ctx context.Context, projectId string, filter domain.ActivityFilter, page int, size int) (*domain.ActivityPage, error) {
+	ctx, span := pr.tracer.Start(ctx, "ProjectsRepo.GetActivities")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := bson.M{"project": projectId}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+
+	activitiesCollection := pr.getActivityCollection()
+
+	total, err := activitiesCollection.CountDocuments(ctx, query)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * size)).
+		SetLimit(int64(size))
+
+	activitiesCursor, err := activitiesCollection.Find(ctx, query, opts)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	activities := domain.Activities{}
+	if err = activitiesCursor.All(ctx, &activities); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	return &domain.ActivityPage{Items: activities, Total: total, Page: page, Size: size}, nil
+}
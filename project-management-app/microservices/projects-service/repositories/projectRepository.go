@@ -37,6 +37,12 @@ func New(ctx context.Context, logger *log.Logger, tracer trace.Tracer) (*Project
 		return nil, err
 	}
 
+	return NewWithClient(client, logger, tracer), nil
+}
+
+// NewWithClient wraps a client that is already connected, like the mocked
+// client of the tests.
+func NewWithClient(client *mongo.Client, logger *log.Logger, tracer trace.Tracer) *ProjectRepo {
 	cb := gobreaker.NewCircuitBreaker[interface{}](gobreaker.Settings{
 		Name:        "ProjectRepoCB",
 		MaxRequests: 1,
@@ -59,7 +65,7 @@ func New(ctx context.Context, logger *log.Logger, tracer trace.Tracer) (*Project
 		tracer: tracer,
 		cb:     cb,
 		client: httpClient,
-	}, nil
+	}
 }
 
 func (pr *ProjectRepo) Disconnect(ctx context.Context) error {
@@ -283,6 +289,31 @@ func (ur *ProjectRepo) GetMembersByProjectId(ctx context.Context,id string) (dom
 	return project.Members, nil
 }
 
+// FindById finds a project regardless of who is asking, for calls from other
+// services. It returns nil when there is no such project.
+func (pr *ProjectRepo) FindById(ctx context.Context, id string) (*domain.Project, error) {
+	ctx, span := pr.tracer.Start(ctx, "ProjectsRepo.FindById")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	var project domain.Project
+	err = pr.getCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&project)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return &project, nil
+}
+
 func (pr *ProjectRepo) Create(ctx context.Context, project *domain.Project) error {
     ctx, span := pr.tracer.Start(ctx, "ProjectsRepo.Create")
     defer span.End()
@@ -356,3 +387,56 @@ func (pr *ProjectRepo) GetProjectsByManagerAndIsActive(ctx context.Context,usern
 	}
 	return projects, nil
 }
+
+// AddMembers adds all users to a project in a single update. Nothing is
+// changed when any of them is already a member.
+func (pr *ProjectRepo) AddMembers(ctx context.Context, projectId primitive.ObjectID, users domain.Users) error {
+	ctx, span := pr.tracer.Start(ctx, "ProjectsRepo.AddMembers")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	usernames := make([]string, 0, len(users))
+	for _, user := range users {
+		usernames = append(usernames, user.Username)
+	}
+
+	result, err := pr.getCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": projectId, "members.username": bson.M{"$nin": usernames}},
+		bson.M{"$push": bson.M{"members": bson.M{"$each": users}}},
+	)
+	if err != nil {
+		pr.logger.Println("Error updating document:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("project members changed, no members were added")
+	}
+	return nil
+}
+
+// RemoveMembers removes all users from a project in a single update. Nothing
+// is changed when any of them is no longer a member.
+func (pr *ProjectRepo) RemoveMembers(ctx context.Context, projectId primitive.ObjectID, usernames []string) error {
+	ctx, span := pr.tracer.Start(ctx, "ProjectsRepo.RemoveMembers")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := pr.getCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": projectId, "members.username": bson.M{"$all": usernames}},
+		bson.M{"$pull": bson.M{"members": bson.M{"username": bson.M{"$in": usernames}}}},
+	)
+	if err != nil {
+		pr.logger.Println("Error updating document:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("project members changed, no members were removed")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (pr *ProjectRepo) getTemplateCollection() *mongo.Collection {
+	projectDatabase := pr.cli.Database("projects")
+	templatesCollection := projectDatabase.Collection("templates")
+	return templatesCollection
+}
+
+func (pr *ProjectRepo) CreateTemplate(ctx context.Context, template *domain.Template) error {
+	ctx, span := pr.tracer.Start(ctx, "ProjectsRepo.CreateTemplate")
+	defer span.End()
+
+	result, err := pr.getTemplateCollection().InsertOne(ctx, template)
+	if err != nil {
+		pr.logger.Println(err)
+		return err
+	}
+	template.Id = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (pr *ProjectRepo) GetTemplateById(ctx context.Context, id string) (*domain.Template, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template ID: %v", err)
+	}
+
+	var template domain.Template
+	err = pr.getTemplateCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&template)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("template not found")
+	} else if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (pr *ProjectRepo) GetTemplatesByManager(ctx context.Context, username string) (domain.Templates, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	templatesCursor, err := pr.getTemplateCollection().Find(ctx, bson.M{"manager.username": username})
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	templates := domain.Templates{}
+	if err = templatesCursor.All(ctx, &templates); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (pr *ProjectRepo) DeleteTemplate(ctx context.Context, id string, username string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid template ID: %v", err)
+	}
+
+	result, err := pr.getTemplateCollection().DeleteOne(ctx, bson.M{"_id": objID, "manager.username": username})
+	if err != nil {
+		pr.logger.Println(err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("template not found")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *ProjectRepo) getTransferCollection() *mongo.Collection {
+	projectDatabase := pr.cli.Database("projects")
+	transfersCollection := projectDatabase.Collection("transfers")
+	return transfersCollection
+}
+
+func (pr *ProjectRepo) CreateTransfer(ctx context.Context, transfer *domain.OwnershipTransfer) error {
+	ctx, span := pr.tracer.Start(ctx, "ProjectsRepo.CreateTransfer")
+	defer span.End()
+
+	result, err := pr.getTransferCollection().InsertOne(ctx, transfer)
+	if err != nil {
+		pr.logger.Println(err)
+		return err
+	}
+	transfer.Id = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (pr *ProjectRepo) GetTransferById(ctx context.Context, id string) (*domain.OwnershipTransfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transfer ID: %v", err)
+	}
+
+	var transfer domain.OwnershipTransfer
+	err = pr.getTransferCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&transfer)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("transfer not found")
+	} else if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (pr *ProjectRepo) HasPendingTransfer(ctx context.Context, projectId primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := pr.getTransferCollection().CountDocuments(ctx, bson.M{
+		"project_id": projectId,
+		"status":     domain.TRANSFER_PENDING,
+	})
+	if err != nil {
+		pr.logger.Println(err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetPendingTransfers returns transfers waiting for the given manager to
+// accept or reject them.
+func (pr *ProjectRepo) GetPendingTransfers(ctx context.Context, username string) (domain.OwnershipTransfers, error) {
+	return pr.findTransfers(ctx, bson.M{"to.username": username, "status": domain.TRANSFER_PENDING})
+}
+
+// GetOwnershipHistory returns all transfers of a project, oldest first.
+func (pr *ProjectRepo) GetOwnershipHistory(ctx context.Context, projectId primitive.ObjectID) (domain.OwnershipTransfers, error) {
+	return pr.findTransfers(ctx, bson.M{"project_id": projectId})
+}
+
+func (pr *ProjectRepo) findTransfers(ctx context.Context, filter bson.M) (domain.OwnershipTransfers, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	transfersCursor, err := pr.getTransferCollection().Find(ctx, filter, opts)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	transfers := domain.OwnershipTransfers{}
+	if err = transfersCursor.All(ctx, &transfers); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// ResolveTransfer moves a pending transfer into its final status. It fails if
+// the transfer has already been resolved in the meantime.
+func (pr *ProjectRepo) ResolveTransfer(ctx context.Context, id primitive.ObjectID, status domain.TransferStatus) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := pr.getTransferCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": domain.TRANSFER_PENDING},
+		bson.M{"$set": bson.M{"status": status, "resolved_at": time.Now()}},
+	)
+	if err != nil {
+		pr.logger.Println("Error updating transfer:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("transfer is no longer pending")
+	}
+	return nil
+}
+
+// ChangeManager sets a new manager on the project, but only while it is still
+// owned by the expected previous manager.
+func (pr *ProjectRepo) ChangeManager(ctx context.Context, projectId primitive.ObjectID, from string, to domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := pr.getCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": projectId, "manager.username": from},
+		bson.M{"$set": bson.M{"manager": to}},
+	)
+	if err != nil {
+		pr.logger.Println("Error updating document:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("project not found or manager has changed")
+	}
+	return nil
+}
+
+// CancelTransfersInvolving cancels every pending transfer sent by or
+// addressed to the given manager.
+func (pr *ProjectRepo) CancelTransfersInvolving(ctx context.Context, username string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getTransferCollection().UpdateMany(
+		ctx,
+		bson.M{
+			"status": domain.TRANSFER_PENDING,
+			"$or": bson.A{
+				bson.M{"from.username": username},
+				bson.M{"to.username": username},
+			},
+		},
+		bson.M{"$set": bson.M{"status": domain.TRANSFER_CANCELLED, "resolved_at": time.Now()}},
+	)
+	if err != nil {
+		pr.logger.Println("Error updating transfers:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultActivityPageSize = 20
+	maxActivityPageSize     = 100
+)
+
+// RecordActivity appends an entry to the activity log of its project.
+func (s *ProjectService) RecordActivity(ctx context.Context, activity *domain.Activity) error {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.RecordActivity")
+	defer span.End()
+
+	if activity.TraceId == "" {
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+			activity.TraceId = spanContext.TraceID().String()
+		}
+	}
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
+
+	if err := s.projects.InsertActivity(ctx, activity); err != nil {
+		return err
+	}
+	if activity.Source == "tasks-service" {
+		s.stats.invalidate(activity.Project)
+	}
+	return nil
+}
+
+// recordActivity writes an activity of projects-service on behalf of the user
+// making the request. Failing to record never fails the operation itself.
+func (s *ProjectService) recordActivity(ctx context.Context, project string, action string, targetType string, target string, before map[string]interface{}, after map[string]interface{}) {
+	activity := &domain.Activity{
+		Project:    project,
+		Actor:      actorFromContext(ctx),
+		Action:     action,
+		TargetType: targetType,
+		Target:     target,
+		Before:     before,
+		After:      after,
+		Source:     "projects-service",
+	}
+	if err := s.RecordActivity(ctx, activity); err != nil {
+		log.Printf("Error recording activity %s for project %s: %v\n", action, project, err)
+	}
+}
+
+// GetActivity returns a page of the activity feed of a project the caller is
+// a member or manager of.
+func (s *ProjectService) GetActivity(ctx context.Context, projectId string, username string, role string, filter domain.ActivityFilter, page int, size int) (*domain.ActivityPage, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.GetActivity")
+	defer span.End()
+
+	if _, err := s.projects.GetById(projectId, username, role); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultActivityPageSize
+	} else if size > maxActivityPageSize {
+		size = maxActivityPageSize
+	}
+
+	return s.projects.GetActivities(ctx, projectId, filter, page, size)
+}
+
+func actorFromContext(ctx context.Context) string {
+	if username, ok := ctx.Value(authorizationlib.UsernameKey).(string); ok {
+		return username
+	}
+	return "system"
+}
+
+func userActivity(user domain.User) map[string]interface{} {
+	return map[string]interface{}{
+		"username": user.Username,
+		"name":     user.Name,
+		"surname":  user.Surname,
+	}
+}
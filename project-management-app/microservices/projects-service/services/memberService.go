@@ -0,0 +1,304 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"github.com/eapache/go-resiliency/retrier"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// AddMembers adds several users to a project of the caller at once. All users
+// are validated with a single users-service call and added in one update, so
+// either every user is added or none is.
+func (s *ProjectService) AddMembers(ctx context.Context, projectId string, usernames []string, manager string, role string) (*domain.MemberBatch, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.AddMembers")
+	defer span.End()
+
+	project, usernames, err := s.memberBatchProject(projectId, usernames, manager, role)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := s.getAvailableMembers(ctx, projectId, project.Members)
+	if err != nil {
+		return nil, err
+	}
+	availableByUsername := map[string]domain.User{}
+	for _, user := range available {
+		availableByUsername[user.Username] = user
+	}
+	members := map[string]bool{}
+	for _, member := range project.Members {
+		members[member.Username] = true
+	}
+
+	batch := &domain.MemberBatch{Project: projectId, Results: []domain.MemberResult{}}
+	users := domain.Users{}
+	for _, username := range usernames {
+		user, ok := availableByUsername[username]
+		switch {
+		case members[username]:
+			batch.Results = append(batch.Results, domain.MemberResult{Username: username, Status: domain.MEMBER_INVALID, Error: "user already a member"})
+		case !ok:
+			batch.Results = append(batch.Results, domain.MemberResult{Username: username, Status: domain.MEMBER_INVALID, Error: "user not available"})
+		default:
+			users = append(users, &user)
+			batch.Results = append(batch.Results, domain.MemberResult{Username: username, Status: domain.MEMBER_ADDED})
+		}
+	}
+	if project.MaxWorkers > 0 && len(project.Members)+len(users) > project.MaxWorkers {
+		for i := range batch.Results {
+			if batch.Results[i].Status == domain.MEMBER_ADDED {
+				batch.Results[i].Status = domain.MEMBER_INVALID
+				batch.Results[i].Error = fmt.Sprintf("project allows at most %d members", project.MaxWorkers)
+			}
+		}
+	}
+	if !batchValid(batch) {
+		return batch, nil
+	}
+
+	if err := s.projects.AddMembers(ctx, project.Id, users); err != nil {
+		return nil, err
+	}
+	batch.Applied = true
+
+	for i, user := range users {
+		s.recordActivity(ctx, projectId, domain.ACTIVITY_MEMBER_ADDED, "member", user.Username, nil, userActivity(*user))
+		batch.Results[i].Notified = s.notifyMember(user.Username, fmt.Sprintf("You are added to project %s", project.Name))
+	}
+	s.stats.invalidate(projectId)
+
+	return batch, nil
+}
+
+// RemoveMembers removes several members from a project of the caller at once.
+// Either every user is removed or none is.
+func (s *ProjectService) RemoveMembers(ctx context.Context, projectId string, usernames []string, manager string, role string) (*domain.MemberBatch, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.RemoveMembers")
+	defer span.End()
+
+	project, usernames, err := s.memberBatchProject(projectId, usernames, manager, role)
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[string]bool{}
+	for _, member := range project.Members {
+		members[member.Username] = true
+	}
+
+	batch := &domain.MemberBatch{Project: projectId, Results: []domain.MemberResult{}}
+	for _, username := range usernames {
+		if members[username] {
+			batch.Results = append(batch.Results, domain.MemberResult{Username: username, Status: domain.MEMBER_REMOVED})
+		} else {
+			batch.Results = append(batch.Results, domain.MemberResult{Username: username, Status: domain.MEMBER_INVALID, Error: "user is not a member"})
+		}
+	}
+	if !batchValid(batch) {
+		return batch, nil
+	}
+
+	if err := s.projects.RemoveMembers(ctx, project.Id, usernames); err != nil {
+		return nil, err
+	}
+	batch.Applied = true
+
+	for i, username := range usernames {
+		s.recordActivity(ctx, projectId, domain.ACTIVITY_MEMBER_REMOVED, "member", username, map[string]interface{}{"username": username}, nil)
+		batch.Results[i].Notified = s.notifyMember(username, fmt.Sprintf("You are deleted from project %s", project.Name))
+	}
+	s.unassignFromTasks(ctx, projectId, usernames)
+	s.stats.invalidate(projectId)
+
+	return batch, nil
+}
+
+// memberBatchProject loads the project managed by the caller and removes
+// duplicates and empty names from the requested usernames.
+func (s *ProjectService) memberBatchProject(projectId string, usernames []string, manager string, role string) (*domain.Project, []string, error) {
+	project, err := s.projects.GetById(projectId, manager, role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, username := range usernames {
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		unique = append(unique, username)
+	}
+	if len(unique) == 0 {
+		return nil, nil, fmt.Errorf("at least one username is required")
+	}
+
+	return project, unique, nil
+}
+
+// batchValid reports whether every user of the batch can be changed. When one
+// of them can't, the others are marked as skipped.
+func batchValid(batch *domain.MemberBatch) bool {
+	valid := true
+	for _, result := range batch.Results {
+		if result.Status == domain.MEMBER_INVALID {
+			valid = false
+		}
+	}
+	if !valid {
+		for i := range batch.Results {
+			if batch.Results[i].Status != domain.MEMBER_INVALID {
+				batch.Results[i].Status = domain.MEMBER_SKIPPED
+			}
+		}
+	}
+	return valid
+}
+
+// notifyMember sends the single notification a user gets for a bulk change.
+// A failed notification doesn't undo the change that was already applied.
+func (s *ProjectService) notifyMember(username string, message string) bool {
+	if err := s.sendNotification(username, message); err != nil {
+		log.Printf("Error sending notification to %s: %v\n", username, err)
+		return false
+	}
+	return true
+}
+
+// getAvailableMembers returns the active project members that can still join
+// the project, in one call to users-service.
+func (s *ProjectService) getAvailableMembers(ctx context.Context, projectId string, members domain.Users) ([]domain.User, error) {
+	url := fmt.Sprintf("http://users-service:8000/projects/%s/availableMembers", projectId)
+
+	if members == nil {
+		members = domain.Users{}
+	}
+	reqBody, err := json.Marshal(map[string]interface{}{"members": members})
+	if err != nil {
+		log.Println("Error marshalling request body:", err)
+		return nil, fmt.Errorf("failed to get available members: %v", err)
+	}
+
+	r := retrier.New(retrier.ConstantBackoff(3, 100*time.Millisecond), nil)
+
+	var available []domain.User
+	err = r.Run(func() error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
+		if err != nil {
+			log.Println("Error creating request:", err)
+			return fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			log.Println("Error making request to user service:", err)
+			return fmt.Errorf("failed to get available members: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			log.Println("Unexpected status code:", resp.StatusCode)
+			return fmt.Errorf("failed to get available members: unexpected status code %d", resp.StatusCode)
+		}
+
+		available = []domain.User{}
+		if err := json.NewDecoder(resp.Body).Decode(&available); err != nil && err != io.EOF {
+			log.Println("Error decoding response from user service:", err)
+			return fmt.Errorf("failed to get available members: %v", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return available, nil
+}
+
+// unassignFromTasks asks tasks-service to take removed members off the open
+// tasks of the project. The members are already removed when this runs, so
+// a failure is only logged.
+func (s *ProjectService) unassignFromTasks(ctx context.Context, projectId string, usernames []string) {
+	url := fmt.Sprintf("http://tasks-service:8000/tasks/project/%s/unassign", projectId)
+
+	reqBody, err := json.Marshal(map[string]interface{}{"usernames": usernames})
+	if err != nil {
+		log.Println("Error marshalling request body:", err)
+		return
+	}
+
+	r := retrier.New(retrier.ConstantBackoff(3, 100*time.Millisecond), nil)
+
+	err = r.Run(func() error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
+		if err != nil {
+			log.Println("Error creating request:", err)
+			return fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			log.Println("Error making request to tasks service:", err)
+			return fmt.Errorf("failed to unassign members from tasks: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			log.Println("Unexpected status code:", resp.StatusCode)
+			return fmt.Errorf("failed to unassign members from tasks: unexpected status code %d", resp.StatusCode)
+		}
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("Members %v removed from project %s are still assigned to its tasks: %v\n", usernames, projectId, err)
+	}
+}
+
+// GetAccess returns the manager and the members of a project.
+func (s *ProjectService) GetAccess(ctx context.Context, projectId string) (*domain.ProjectAccess, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.GetAccess")
+	defer span.End()
+
+	project, err := s.projects.FindById(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found")
+	}
+
+	access := &domain.ProjectAccess{
+		Project: projectId,
+		Name:    project.Name,
+		EndDate: project.EndDate,
+		Manager: project.Manager.Username,
+		Members: make([]string, 0, len(project.Members)),
+	}
+	for _, member := range project.Members {
+		access.Members = append(access.Members, member.Username)
+	}
+	return access, nil
+}
@@ -23,9 +23,10 @@ type ProjectService struct {
 	cb       *gobreaker.CircuitBreaker[interface{}]
 	client   *http.Client
 	tracer trace.Tracer
+	stats    *statsCache
 }
 
-func NewProjectService(p *repositories.ProjectRepo) *ProjectService {
+func NewProjectService(p *repositories.ProjectRepo, tracer trace.Tracer) *ProjectService {
 	cb := gobreaker.NewCircuitBreaker[interface{}](gobreaker.Settings{
 		Name:        "ProjectServiceCB",
 		MaxRequests: 1,
@@ -42,10 +43,32 @@ func NewProjectService(p *repositories.ProjectRepo) *ProjectService {
 		Timeout: 5 * time.Second, // Globalni timeout
 	}
 
-	return &ProjectService{projects: p, cb: cb, client: client}
+	return &ProjectService{projects: p, cb: cb, client: client, tracer: tracer, stats: newStatsCache()}
 }
 
-func (s ProjectService) AddMember(projectId string, user domain.User) error {
+// Create stores a new project and opens its activity log.
+func (s ProjectService) Create(ctx context.Context, project *domain.Project) error {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.Create")
+	defer span.End()
+
+	if project.Id.IsZero() {
+		project.Id = primitive.NewObjectID()
+	}
+	if err := s.projects.Create(ctx, project); err != nil {
+		return err
+	}
+
+	s.recordActivity(ctx, project.Id.Hex(), domain.ACTIVITY_PROJECT_CREATED, "project", project.Id.Hex(), nil, map[string]interface{}{
+		"name":        project.Name,
+		"manager":     project.Manager.Username,
+		"end_date":    project.EndDate,
+		"min_workers": project.MinWorkers,
+		"max_workers": project.MaxWorkers,
+	})
+	return nil
+}
+
+func (s ProjectService) AddMember(ctx context.Context, projectId string, user domain.User) error {
 	objID, err := primitive.ObjectIDFromHex(projectId)
 	if err != nil {
 		return fmt.Errorf("invalid project ID: %v", err)
@@ -56,10 +79,16 @@ func (s ProjectService) AddMember(projectId string, user domain.User) error {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
-	return s.projects.AddMember(objID, user)
+	if err := s.projects.AddMember(objID, user); err != nil {
+		return err
+	}
+
+	s.recordActivity(ctx, projectId, domain.ACTIVITY_MEMBER_ADDED, "member", user.Username, nil, userActivity(user))
+	s.stats.invalidate(projectId)
+	return nil
 }
 
-func (s ProjectService) RemoveMember(projectId string, username string) error {
+func (s ProjectService) RemoveMember(ctx context.Context, projectId string, username string) error {
 	objID, err := primitive.ObjectIDFromHex(projectId)
 	if err != nil {
 		return fmt.Errorf("invalid project ID: %v", err)
@@ -70,7 +99,14 @@ func (s ProjectService) RemoveMember(projectId string, username string) error {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
-	return s.projects.RemoveMember(objID, username)
+	if err := s.projects.RemoveMember(objID, username); err != nil {
+		return err
+	}
+
+	s.recordActivity(ctx, projectId, domain.ACTIVITY_MEMBER_REMOVED, "member", username, map[string]interface{}{"username": username}, nil)
+	s.unassignFromTasks(ctx, projectId, []string{username})
+	s.stats.invalidate(projectId)
+	return nil
 }
 
 func (s *ProjectService) GetUser(username string) (domain.User, error) {
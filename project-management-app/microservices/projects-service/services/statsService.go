@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eapache/go-resiliency/retrier"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const statsCacheTTL = 30 * time.Second
+
+// statsCache keeps computed dashboards for a short while. Entries of a project
+// are dropped as soon as tasks-service reports a change on one of its tasks
+// or the members of the project change.
+type statsCache struct {
+	mu      sync.Mutex
+	entries map[string]statsCacheEntry
+}
+
+type statsCacheEntry struct {
+	stats     *domain.ProjectStats
+	expiresAt time.Time
+}
+
+func newStatsCache() *statsCache {
+	return &statsCache{entries: map[string]statsCacheEntry{}}
+}
+
+func statsCacheKey(projectId string, days int) string {
+	return fmt.Sprintf("%s:%d", projectId, days)
+}
+
+func (c *statsCache) get(projectId string, days int) (*domain.ProjectStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[statsCacheKey(projectId, days)]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+func (c *statsCache) put(projectId string, days int, stats *domain.ProjectStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[statsCacheKey(projectId, days)] = statsCacheEntry{stats: stats, expiresAt: time.Now().Add(statsCacheTTL)}
+}
+
+func (c *statsCache) invalidate(projectId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix := projectId + ":"
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// GetStats returns the dashboard statistics of a project the caller is a
+// member or manager of. Every project member is listed in the workload, also
+// those without any tasks.
+func (s *ProjectService) GetStats(ctx context.Context, projectId string, username string, role string, days int) (*domain.ProjectStats, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.GetStats")
+	defer span.End()
+
+	project, err := s.projects.GetById(projectId, username, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats, ok := s.stats.get(projectId, days); ok {
+		return stats, nil
+	}
+
+	stats, err := s.getTaskStats(ctx, projectId, days)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Project = projectId
+	stats.Name = project.Name
+	if stats.Total > 0 {
+		stats.Completion = math.Round(float64(stats.Finished)/float64(stats.Total)*10000) / 100
+	}
+
+	known := map[string]bool{}
+	for _, workload := range stats.Workload {
+		known[workload.Username] = true
+	}
+	for _, member := range project.Members {
+		if !known[member.Username] {
+			stats.Workload = append(stats.Workload, domain.MemberWorkload{Username: member.Username})
+		}
+	}
+	stats.GeneratedAt = time.Now()
+
+	s.stats.put(projectId, days, stats)
+	return stats, nil
+}
+
+func (s *ProjectService) getTaskStats(ctx context.Context, projectId string, days int) (*domain.ProjectStats, error) {
+	url := fmt.Sprintf("http://tasks-service:8000/tasks/%s/stats", projectId)
+	if days > 0 {
+		url = fmt.Sprintf("%s?days=%d", url, days)
+	}
+
+	r := retrier.New(retrier.ConstantBackoff(3, 100*time.Millisecond), nil)
+
+	var stats *domain.ProjectStats
+	err := r.Run(func() error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			log.Println("Error creating request:", err)
+			return fmt.Errorf("failed to create request: %v", err)
+		}
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			log.Println("Error making request to tasks service:", err)
+			return fmt.Errorf("failed to fetch task statistics: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			log.Printf("Unexpected status code: %d\n", resp.StatusCode)
+			return fmt.Errorf("failed to fetch task statistics: unexpected status code %d", resp.StatusCode)
+		}
+
+		stats = &domain.ProjectStats{}
+		if err := stats.FromJSON(resp.Body); err != nil {
+			log.Println("Failed to decode task statistics:", err)
+			return fmt.Errorf("failed to decode task statistics: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"github.com/eapache/go-resiliency/retrier"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// SaveAsTemplate stores the settings of a project owned by the manager
+// together with its current task list as a new template.
+func (s *ProjectService) SaveAsTemplate(ctx context.Context, projectId string, name string, username string) (*domain.Template, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.SaveAsTemplate")
+	defer span.End()
+
+	project, err := s.projects.GetById(projectId, username, "PROJECT_MANAGER")
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.getProjectTasks(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = project.Name
+	}
+
+	template := &domain.Template{
+		Name:       name,
+		Manager:    project.Manager,
+		MinWorkers: project.MinWorkers,
+		MaxWorkers: project.MaxWorkers,
+		Tasks:      tasks,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.projects.CreateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	s.recordActivity(ctx, projectId, domain.ACTIVITY_TEMPLATE_SAVED, "template", template.Id.Hex(), nil, map[string]interface{}{
+		"name":  template.Name,
+		"tasks": len(template.Tasks),
+	})
+	return template, nil
+}
+
+func (s *ProjectService) GetTemplates(ctx context.Context, username string) (domain.Templates, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.GetTemplates")
+	defer span.End()
+	return s.projects.GetTemplatesByManager(ctx, username)
+}
+
+func (s *ProjectService) DeleteTemplate(ctx context.Context, id string, username string) error {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.DeleteTemplate")
+	defer span.End()
+	return s.projects.DeleteTemplate(ctx, id, username)
+}
+
+// CreateFromTemplate creates a new project for the manager using the template
+// settings and recreates every template task in tasks-service.
+func (s *ProjectService) CreateFromTemplate(ctx context.Context, templateId string, name string, endDate time.Time, username string, authorization string) (*domain.Project, []domain.TaskResult, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.CreateFromTemplate")
+	defer span.End()
+
+	template, err := s.projects.GetTemplateById(ctx, templateId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if template.Manager.Username != username {
+		return nil, nil, domain.ErrUnauthorized()
+	}
+
+	project := &domain.Project{
+		Id:         primitive.NewObjectID(),
+		Manager:    template.Manager,
+		Name:       name,
+		EndDate:    endDate,
+		MinWorkers: template.MinWorkers,
+		MaxWorkers: template.MaxWorkers,
+	}
+	if err := s.createProject(ctx, project); err != nil {
+		return nil, nil, err
+	}
+
+	results := s.createProjectTasks(ctx, project.Id.Hex(), template.Tasks, authorization)
+	return project, results, nil
+}
+
+// Clone copies an existing project of the manager under a new name. Members
+// are carried over only when includeMembers is set.
+func (s *ProjectService) Clone(ctx context.Context, projectId string, name string, endDate time.Time, includeMembers bool, username string, authorization string) (*domain.Project, []domain.TaskResult, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.Clone")
+	defer span.End()
+
+	source, err := s.projects.GetById(projectId, username, "PROJECT_MANAGER")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tasks, err := s.getProjectTasks(ctx, projectId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+	if endDate.IsZero() {
+		endDate = source.EndDate
+	}
+
+	project := &domain.Project{
+		Id:         primitive.NewObjectID(),
+		Manager:    source.Manager,
+		Name:       name,
+		EndDate:    endDate,
+		MinWorkers: source.MinWorkers,
+		MaxWorkers: source.MaxWorkers,
+	}
+	if includeMembers {
+		project.Members = source.Members
+	}
+	if err := s.createProject(ctx, project); err != nil {
+		return nil, nil, err
+	}
+
+	s.recordActivity(ctx, project.Id.Hex(), domain.ACTIVITY_PROJECT_CLONED, "project", project.Id.Hex(),
+		map[string]interface{}{"project": projectId},
+		map[string]interface{}{"project": project.Id.Hex(), "include_members": includeMembers})
+
+	results := s.createProjectTasks(ctx, project.Id.Hex(), tasks, authorization)
+	if includeMembers {
+		for _, member := range project.Members {
+			if err := s.sendNotification(member.Username, "You are added to project "+project.Name); err != nil {
+				log.Printf("Error sending notification: %v\n", err)
+			}
+		}
+	}
+	return project, results, nil
+}
+
+func (s *ProjectService) createProject(ctx context.Context, project *domain.Project) error {
+	if project.Name == "" {
+		return errors.New("project name is required")
+	}
+	if project.MaxWorkers > 0 && project.MinWorkers > project.MaxWorkers {
+		return errors.New("min workers cannot exceed max workers")
+	}
+	return s.Create(ctx, project)
+}
+
+func (s *ProjectService) createProjectTasks(ctx context.Context, projectId string, tasks domain.TemplateTasks, authorization string) []domain.TaskResult {
+	results := make([]domain.TaskResult, 0, len(tasks))
+	for _, task := range tasks {
+		result := domain.TaskResult{Name: task.Name}
+		id, err := s.createTask(ctx, projectId, task, authorization)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Id = id
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// getProjectTasks fetches the tasks of a project from tasks-service and
+// reduces them to the fields a template keeps.
+func (s *ProjectService) getProjectTasks(ctx context.Context, projectId string) (domain.TemplateTasks, error) {
+	url := fmt.Sprintf("http://tasks-service:8000/tasks/%s", projectId)
+
+	r := retrier.New(retrier.ConstantBackoff(3, 100*time.Millisecond), nil)
+
+	var tasks domain.TemplateTasks
+	err := r.Run(func() error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			log.Println("Error creating request:", err)
+			return fmt.Errorf("failed to create request: %v", err)
+		}
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			log.Println("Error making request to tasks service:", err)
+			return fmt.Errorf("failed to fetch project tasks: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			log.Printf("Unexpected status code: %d\n", resp.StatusCode)
+			return fmt.Errorf("failed to fetch project tasks: unexpected status code %d", resp.StatusCode)
+		}
+
+		tasks = domain.TemplateTasks{}
+		// tasks-service answers with an empty body when the project has no tasks
+		if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil && err != io.EOF {
+			log.Println("Failed to decode project tasks:", err)
+			return fmt.Errorf("failed to decode project tasks: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// createTask creates a task through the public tasks-service endpoint on
+// behalf of the caller, whose authorization header is forwarded as is.
+func (s *ProjectService) createTask(ctx context.Context, projectId string, task domain.TemplateTask, authorization string) (string, error) {
+	url := "http://tasks-service:8000/tasks"
+
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"name":        task.Name,
+		"description": task.Description,
+		"project":     projectId,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create task: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", authorization)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Println("Error making request to tasks service:", err)
+		return "", fmt.Errorf("failed to create task: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to create task: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	created := struct {
+		Id string `json:"id"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("failed to decode created task: %v", err)
+	}
+	return created.Id, nil
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"github.com/eapache/go-resiliency/retrier"
+)
+
+// RequestTransfer asks another manager to take over a project. The project
+// changes hands only once the receiving manager accepts.
+func (s *ProjectService) RequestTransfer(ctx context.Context, projectId string, from string, to string) (*domain.OwnershipTransfer, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.RequestTransfer")
+	defer span.End()
+
+	if from == to {
+		return nil, errors.New("project is already owned by this manager")
+	}
+
+	project, err := s.projects.GetById(projectId, from, "PROJECT_MANAGER")
+	if err != nil {
+		return nil, err
+	}
+
+	receiver, err := s.getManager(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.projects.HasPendingTransfer(ctx, project.Id)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New("project already has a pending ownership transfer")
+	}
+
+	transfer := &domain.OwnershipTransfer{
+		ProjectId: project.Id,
+		Project:   project.Name,
+		From:      project.Manager,
+		To:        receiver,
+		Status:    domain.TRANSFER_PENDING,
+		CreatedAt: time.Now(),
+	}
+	if err := s.projects.CreateTransfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	s.recordActivity(ctx, project.Id.Hex(), domain.ACTIVITY_OWNERSHIP_REQUESTED, "transfer", transfer.Id.Hex(),
+		userActivity(transfer.From), userActivity(transfer.To))
+
+	if err := s.sendNotification(to, from+" wants to transfer project "+project.Name+" to you"); err != nil {
+		log.Printf("Error sending notification: %v\n", err)
+	}
+	return transfer, nil
+}
+
+// AcceptTransfer makes the receiving manager the owner of the project.
+func (s *ProjectService) AcceptTransfer(ctx context.Context, id string, username string) (*domain.OwnershipTransfer, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.AcceptTransfer")
+	defer span.End()
+
+	transfer, err := s.projects.GetTransferById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.To.Username != username {
+		return nil, domain.ErrUnauthorized()
+	}
+	if transfer.Status != domain.TRANSFER_PENDING {
+		return nil, errors.New("transfer is no longer pending")
+	}
+
+	if err := s.projects.ChangeManager(ctx, transfer.ProjectId, transfer.From.Username, transfer.To); err != nil {
+		return nil, err
+	}
+	if err := s.projects.ResolveTransfer(ctx, transfer.Id, domain.TRANSFER_ACCEPTED); err != nil {
+		// The transfer was cancelled while we were switching managers, undo it.
+		if revertErr := s.projects.ChangeManager(ctx, transfer.ProjectId, transfer.To.Username, transfer.From); revertErr != nil {
+			log.Printf("Error reverting manager of project %s: %v\n", transfer.ProjectId.Hex(), revertErr)
+		}
+		return nil, err
+	}
+
+	transfer.Status = domain.TRANSFER_ACCEPTED
+	s.recordActivity(ctx, transfer.ProjectId.Hex(), domain.ACTIVITY_OWNERSHIP_ACCEPTED, "project", transfer.ProjectId.Hex(),
+		map[string]interface{}{"manager": userActivity(transfer.From)},
+		map[string]interface{}{"manager": userActivity(transfer.To)})
+
+	if err := s.sendNotification(transfer.From.Username, username+" accepted ownership of project "+transfer.Project); err != nil {
+		log.Printf("Error sending notification: %v\n", err)
+	}
+	return transfer, nil
+}
+
+// RejectTransfer declines a transfer addressed to the manager.
+func (s *ProjectService) RejectTransfer(ctx context.Context, id string, username string) (*domain.OwnershipTransfer, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.RejectTransfer")
+	defer span.End()
+
+	transfer, err := s.projects.GetTransferById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.To.Username != username {
+		return nil, domain.ErrUnauthorized()
+	}
+	if err := s.projects.ResolveTransfer(ctx, transfer.Id, domain.TRANSFER_REJECTED); err != nil {
+		return nil, err
+	}
+
+	transfer.Status = domain.TRANSFER_REJECTED
+	s.recordActivity(ctx, transfer.ProjectId.Hex(), domain.ACTIVITY_OWNERSHIP_REJECTED, "transfer", transfer.Id.Hex(), nil, nil)
+
+	if err := s.sendNotification(transfer.From.Username, username+" rejected ownership of project "+transfer.Project); err != nil {
+		log.Printf("Error sending notification: %v\n", err)
+	}
+	return transfer, nil
+}
+
+// CancelTransfer withdraws a transfer the manager has sent.
+func (s *ProjectService) CancelTransfer(ctx context.Context, id string, username string) (*domain.OwnershipTransfer, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.CancelTransfer")
+	defer span.End()
+
+	transfer, err := s.projects.GetTransferById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.From.Username != username {
+		return nil, domain.ErrUnauthorized()
+	}
+	if err := s.projects.ResolveTransfer(ctx, transfer.Id, domain.TRANSFER_CANCELLED); err != nil {
+		return nil, err
+	}
+
+	transfer.Status = domain.TRANSFER_CANCELLED
+	s.recordActivity(ctx, transfer.ProjectId.Hex(), domain.ACTIVITY_OWNERSHIP_CANCELLED, "transfer", transfer.Id.Hex(), nil, nil)
+
+	return transfer, nil
+}
+
+func (s *ProjectService) GetPendingTransfers(ctx context.Context, username string) (domain.OwnershipTransfers, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.GetPendingTransfers")
+	defer span.End()
+	return s.projects.GetPendingTransfers(ctx, username)
+}
+
+// GetOwnershipHistory lists all transfers of a project the caller can see.
+func (s *ProjectService) GetOwnershipHistory(ctx context.Context, projectId string, username string, role string) (domain.OwnershipTransfers, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.GetOwnershipHistory")
+	defer span.End()
+
+	project, err := s.projects.GetById(projectId, username, role)
+	if err != nil {
+		return nil, err
+	}
+	return s.projects.GetOwnershipHistory(ctx, project.Id)
+}
+
+// ReassignAll immediately hands every project of a manager over to another
+// manager. It is meant for users-service, which has to empty a manager's
+// project list before the manager can be deleted.
+func (s *ProjectService) ReassignAll(ctx context.Context, from string, to string) (domain.OwnershipTransfers, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.ReassignAll")
+	defer span.End()
+
+	if from == to {
+		return nil, errors.New("cannot reassign projects to the same manager")
+	}
+
+	receiver, err := s.getManager(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.projects.GetProjectsByManager(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.projects.CancelTransfersInvolving(ctx, from); err != nil {
+		return nil, err
+	}
+
+	transfers := domain.OwnershipTransfers{}
+	for _, project := range projects {
+		if err := s.projects.ChangeManager(ctx, project.Id, from, receiver); err != nil {
+			return transfers, fmt.Errorf("failed to reassign project %s: %w", project.Name, err)
+		}
+
+		now := time.Now()
+		transfer := &domain.OwnershipTransfer{
+			ProjectId:  project.Id,
+			Project:    project.Name,
+			From:       project.Manager,
+			To:         receiver,
+			Status:     domain.TRANSFER_ACCEPTED,
+			Bulk:       true,
+			CreatedAt:  now,
+			ResolvedAt: &now,
+		}
+		if err := s.projects.CreateTransfer(ctx, transfer); err != nil {
+			log.Printf("Error recording transfer of project %s: %v\n", project.Id.Hex(), err)
+		}
+		s.recordActivity(ctx, project.Id.Hex(), domain.ACTIVITY_OWNERSHIP_REASSIGNED, "project", project.Id.Hex(),
+			map[string]interface{}{"manager": userActivity(project.Manager)},
+			map[string]interface{}{"manager": userActivity(receiver)})
+		transfers = append(transfers, transfer)
+	}
+
+	if len(transfers) > 0 {
+		message := fmt.Sprintf("%d project(s) of %s were reassigned to you", len(transfers), from)
+		if err := s.sendNotification(to, message); err != nil {
+			log.Printf("Error sending notification: %v\n", err)
+		}
+	}
+	return transfers, nil
+}
+
+// getManager looks the user up in users-service and makes sure it is an
+// active project manager.
+func (s *ProjectService) getManager(ctx context.Context, username string) (domain.User, error) {
+	url := fmt.Sprintf("http://users-service:8000/users/%s", username)
+
+	r := retrier.New(retrier.ConstantBackoff(3, 100*time.Millisecond), nil)
+
+	var user struct {
+		Username string `json:"username"`
+		Name     string `json:"name"`
+		Surname  string `json:"surname"`
+		Role     string `json:"role"`
+		IsActive bool   `json:"isActive"`
+	}
+	err := r.Run(func() error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			log.Println("Error creating request:", err)
+			return fmt.Errorf("failed to create request: %v", err)
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			log.Println("Error making request to user service:", err)
+			return fmt.Errorf("failed to get user: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&user); err != nil && err != io.EOF {
+			return fmt.Errorf("failed to decode user: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if user.Username == "" {
+		return domain.User{}, domain.ErrUserNotFound()
+	}
+	if user.Role != "PROJECT_MANAGER" || !user.IsActive {
+		return domain.User{}, errors.New("user is not an active project manager")
+	}
+
+	return domain.User{Username: user.Username, Name: user.Name, Surname: user.Surname}, nil
+}
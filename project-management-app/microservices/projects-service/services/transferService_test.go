@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"project-management-app/microservices/projects-service/repositories"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// notified collects the notifications sent to each user.
+type notified map[string][]string
+
+func (n notified) RoundTrip(r *http.Request) (*http.Response, error) {
+	var payload map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	n[payload["user_id"]] = append(n[payload["user_id"]], payload["message"])
+	return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(strings.NewReader("")), Request: r}, nil
+}
+
+func newTransferService(mt *mtest.T) (*ProjectService, notified) {
+	tracer := noop.NewTracerProvider().Tracer("")
+	s := NewProjectService(repositories.NewWithClient(mt.Client, log.New(io.Discard, "", 0), tracer), tracer)
+	sent := notified{}
+	s.client = &http.Client{Transport: sent}
+	return s, sent
+}
+
+// pendingTransfer is the transfer of project Apollo from mila to boss, as
+// the database returns it.
+func pendingTransfer(mt *mtest.T) (*domain.OwnershipTransfer, bson.D) {
+	transfer := &domain.OwnershipTransfer{
+		Id:        primitive.NewObjectID(),
+		ProjectId: primitive.NewObjectID(),
+		Project:   "Apollo",
+		From:      domain.User{Username: "mila", Name: "Mila"},
+		To:        domain.User{Username: "boss", Name: "Boss"},
+		Status:    domain.TRANSFER_PENDING,
+		CreatedAt: time.Now(),
+	}
+	data, err := bson.Marshal(transfer)
+	if err != nil {
+		mt.Fatal(err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		mt.Fatal(err)
+	}
+	return transfer, mtest.CreateCursorResponse(0, "projects.transfers", mtest.FirstBatch, doc)
+}
+
+func matched(n int) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+}
+
+// writes returns the updates that were sent, in order, as the collection
+// they went to and their only statement.
+func writes(mt *mtest.T) (collections []string, statements []bson.Raw) {
+	for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+		if event.CommandName != "update" {
+			continue
+		}
+		collections = append(collections, event.Command.Lookup("update").StringValue())
+		statements = append(statements, event.Command.Lookup("updates").Array().Index(0).Value().Document())
+	}
+	return collections, statements
+}
+
+func TestAcceptTransfer(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("hands the project over", func(mt *mtest.T) {
+		s, sent := newTransferService(mt)
+		transfer, found := pendingTransfer(mt)
+		mt.AddMockResponses(found, matched(1), matched(1), mtest.CreateSuccessResponse())
+
+		accepted, err := s.AcceptTransfer(context.Background(), transfer.Id.Hex(), "boss")
+		if err != nil {
+			mt.Fatalf("AcceptTransfer: %v", err)
+		}
+		if accepted.Status != domain.TRANSFER_ACCEPTED {
+			mt.Errorf("transfer is %s", accepted.Status)
+		}
+
+		collections, statements := writes(mt)
+		if strings.Join(collections, ",") != "projects,transfers" {
+			mt.Fatalf("updated %v, want the project and then the transfer", collections)
+		}
+		change := statements[0]
+		if from := change.Lookup("q", "manager.username").StringValue(); from != "mila" {
+			mt.Errorf("changed the manager of the project owned by %q", from)
+		}
+		if to := change.Lookup("u", "$set", "manager", "username").StringValue(); to != "boss" {
+			mt.Errorf("made %q the manager", to)
+		}
+		if status := statements[1].Lookup("q", "status").StringValue(); status != string(domain.TRANSFER_PENDING) {
+			mt.Errorf("resolved the transfer in status %q, want only a pending one", status)
+		}
+		if len(sent["mila"]) != 1 || sent["mila"][0] != "boss accepted ownership of project Apollo" {
+			mt.Errorf("mila was sent %q", sent["mila"])
+		}
+	})
+
+	mt.Run("only by the receiving manager", func(mt *mtest.T) {
+		s, sent := newTransferService(mt)
+		transfer, found := pendingTransfer(mt)
+		mt.AddMockResponses(found)
+
+		if _, err := s.AcceptTransfer(context.Background(), transfer.Id.Hex(), "mila"); !errors.Is(err, domain.ErrUnauthorized()) {
+			mt.Fatalf("AcceptTransfer by the sender = %v, want ErrUnauthorized", err)
+		}
+		if collections, _ := writes(mt); len(collections) > 0 || len(sent) > 0 {
+			mt.Errorf("updated %v and notified %v", collections, sent)
+		}
+	})
+
+	mt.Run("gives the project back when the transfer was cancelled meanwhile", func(mt *mtest.T) {
+		s, sent := newTransferService(mt)
+		transfer, found := pendingTransfer(mt)
+		mt.AddMockResponses(found, matched(1), matched(0), matched(1))
+
+		if _, err := s.AcceptTransfer(context.Background(), transfer.Id.Hex(), "boss"); err == nil {
+			mt.Fatal("AcceptTransfer of a cancelled transfer succeeded")
+		}
+
+		collections, statements := writes(mt)
+		if strings.Join(collections, ",") != "projects,transfers,projects" {
+			mt.Fatalf("updated %v, want the manager changed back", collections)
+		}
+		revert := statements[2]
+		if from, to := revert.Lookup("q", "manager.username").StringValue(), revert.Lookup("u", "$set", "manager", "username").StringValue(); from != "boss" || to != "mila" {
+			mt.Errorf("changed the manager from %q to %q, want back from boss to mila", from, to)
+		}
+		if len(sent) > 0 {
+			mt.Errorf("notified %v", sent)
+		}
+	})
+}
+
+func TestRejectTransfer(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("keeps the project with its manager", func(mt *mtest.T) {
+		s, sent := newTransferService(mt)
+		transfer, found := pendingTransfer(mt)
+		mt.AddMockResponses(found, matched(1), mtest.CreateSuccessResponse())
+
+		rejected, err := s.RejectTransfer(context.Background(), transfer.Id.Hex(), "boss")
+		if err != nil {
+			mt.Fatalf("RejectTransfer: %v", err)
+		}
+		if rejected.Status != domain.TRANSFER_REJECTED {
+			mt.Errorf("transfer is %s", rejected.Status)
+		}
+		collections, statements := writes(mt)
+		if len(collections) != 1 || collections[0] != "transfers" {
+			mt.Fatalf("updated %v, want only the transfer", collections)
+		}
+		if status := statements[0].Lookup("u", "$set", "status").StringValue(); status != string(domain.TRANSFER_REJECTED) {
+			mt.Errorf("transfer set to %q", status)
+		}
+		if len(sent["mila"]) != 1 || sent["mila"][0] != "boss rejected ownership of project Apollo" {
+			mt.Errorf("mila was sent %q", sent["mila"])
+		}
+	})
+
+	mt.Run("of a transfer already resolved", func(mt *mtest.T) {
+		s, sent := newTransferService(mt)
+		transfer, found := pendingTransfer(mt)
+		mt.AddMockResponses(found, matched(0))
+
+		if _, err := s.RejectTransfer(context.Background(), transfer.Id.Hex(), "boss"); err == nil || err.Error() != "transfer is no longer pending" {
+			mt.Fatalf("RejectTransfer = %v, want the transfer no longer pending", err)
+		}
+		if len(sent) > 0 {
+			mt.Errorf("notified %v", sent)
+		}
+	})
+
+	mt.Run("only by the receiving manager", func(mt *mtest.T) {
+		s, _ := newTransferService(mt)
+		transfer, found := pendingTransfer(mt)
+		mt.AddMockResponses(found)
+
+		if _, err := s.RejectTransfer(context.Background(), transfer.Id.Hex(), "ivan"); !errors.Is(err, domain.ErrUnauthorized()) {
+			mt.Fatalf("RejectTransfer by someone else = %v, want ErrUnauthorized", err)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"project-management-app/microservices/projects-service/domain"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eapache/go-resiliency/retrier"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// defaultCapacityHours is the open estimated work a member can carry before
+// being reported as overloaded.
+const defaultCapacityHours = 40
+
+// GetCapacity reports the workload of the members of a project, or of all
+// projects of the calling manager when no project is given, across every
+// project they work on.
+func (s *ProjectService) GetCapacity(ctx context.Context, projectId string, username string, role string, capacityHours float64) (*domain.Capacity, error) {
+	ctx, span := s.tracer.Start(ctx, "ProjectService.GetCapacity")
+	defer span.End()
+
+	if capacityHours <= 0 {
+		capacityHours = defaultCapacityHours
+	}
+
+	var projects domain.Projects
+	if projectId != "" {
+		project, err := s.projects.GetById(projectId, username, role)
+		if err != nil {
+			return nil, err
+		}
+		projects = domain.Projects{project}
+	} else {
+		if role != "PROJECT_MANAGER" {
+			return nil, domain.ErrUnauthorized()
+		}
+		managed, err := s.projects.GetProjectsByManager(username)
+		if err != nil {
+			return nil, err
+		}
+		projects = managed
+	}
+
+	names := map[string]string{}
+	usernames := []string{}
+	seen := map[string]bool{}
+	for _, project := range projects {
+		if project.Manager.Username == username {
+			names[project.Id.Hex()] = project.Name
+		}
+		for _, member := range project.Members {
+			if !seen[member.Username] {
+				seen[member.Username] = true
+				usernames = append(usernames, member.Username)
+			}
+		}
+	}
+
+	capacity := &domain.Capacity{CapacityHours: capacityHours, Members: []*domain.MemberCapacity{}, GeneratedAt: time.Now()}
+	if len(usernames) == 0 {
+		return capacity, nil
+	}
+
+	members, err := s.getWorkload(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		for i := range member.Projects {
+			member.Projects[i].Name = names[member.Projects[i].Project]
+		}
+		member.Utilization = math.Round(member.EstimateHours/capacityHours*10000) / 100
+		member.Overloaded = member.EstimateHours > capacityHours
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		if members[i].EstimateHours != members[j].EstimateHours {
+			return members[i].EstimateHours > members[j].EstimateHours
+		}
+		return members[i].Open > members[j].Open
+	})
+	capacity.Members = members
+	return capacity, nil
+}
+
+func (s *ProjectService) getWorkload(ctx context.Context, usernames []string) ([]*domain.MemberCapacity, error) {
+	url := "http://tasks-service:8000/workload?users=" + url.QueryEscape(strings.Join(usernames, ","))
+
+	r := retrier.New(retrier.ConstantBackoff(3, 100*time.Millisecond), nil)
+
+	var members []*domain.MemberCapacity
+	err := r.Run(func() error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			log.Println("Error creating request:", err)
+			return fmt.Errorf("failed to create request: %v", err)
+		}
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			log.Println("Error making request to tasks service:", err)
+			return fmt.Errorf("failed to fetch workload: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			log.Printf("Unexpected status code: %d\n", resp.StatusCode)
+			return fmt.Errorf("failed to fetch workload: unexpected status code %d", resp.StatusCode)
+		}
+
+		members = []*domain.MemberCapacity{}
+		if err := json.NewDecoder(resp.Body).Decode(&members); err != nil {
+			log.Println("Failed to decode workload:", err)
+			return fmt.Errorf("failed to decode workload: %v", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -1,12 +1,33 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
 	Address                 string
 	JaegerAddress           string
 	UsersServiceAddress string
 	TasksServiceAddress string
+	Storage             StorageConfig
+	TrashRetention      time.Duration
+}
+
+// StorageConfig selects where task attachments are kept: "local" stores them
+// under Path, "s3" in Bucket of an S3-compatible server such as MinIO.
+type StorageConfig struct {
+	Driver       string
+	Path         string
+	Endpoint     string
+	Region       string
+	Bucket       string
+	AccessKey    string
+	SecretKey    string
+	MaxSize      int64
+	AllowedTypes []string
 }
 
 func GetConfig() Config {
@@ -15,6 +36,33 @@ func GetConfig() Config {
 		JaegerAddress:           os.Getenv("JAEGER_ADDRESS"),
 		UsersServiceAddress: os.Getenv("USERS_SERVICE_ADDRESS"),
 		TasksServiceAddress: os.Getenv("TASKS_SERVICE_ADDRESS"),
+		Storage: StorageConfig{
+			Driver:       getEnv("STORAGE_DRIVER", "local"),
+			Path:         getEnv("STORAGE_PATH", "/data/attachments"),
+			Endpoint:     os.Getenv("S3_ENDPOINT"),
+			Region:       getEnv("S3_REGION", "us-east-1"),
+			Bucket:       getEnv("S3_BUCKET", "attachments"),
+			AccessKey:    os.Getenv("S3_ACCESS_KEY"),
+			SecretKey:    os.Getenv("S3_SECRET_KEY"),
+			MaxSize:      int64(getEnvInt("ATTACHMENT_MAX_SIZE_MB", 20)) << 20,
+			AllowedTypes: strings.Split(getEnv("ATTACHMENT_ALLOWED_TYPES", "image/,text/,application/pdf,application/zip,application/json"), ","),
+		},
+		TrashRetention: time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+
+	}
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
 
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
 	}
+	return value
 }
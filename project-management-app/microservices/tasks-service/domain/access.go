@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// Kinds of resources a route can refer to by id. Each of them belongs to a
+// project, which decides who may access it.
+const (
+	RESOURCE_PROJECT    = "project"
+	RESOURCE_TASK       = "task"
+	RESOURCE_SPRINT     = "sprint"
+	RESOURCE_LABEL      = "label"
+	RESOURCE_COMMENT    = "comment"
+	RESOURCE_WORKLOG    = "worklog"
+	RESOURCE_ATTACHMENT = "attachment"
+	RESOURCE_IMPORT     = "import"
+)
+
+// ProjectAccess lists who may work on the tasks of a project, as reported by
+// projects-service together with the name and the end date of the project.
+type ProjectAccess struct {
+	Project string    `json:"project"`
+	Name    string    `json:"name"`
+	EndDate time.Time `json:"end_date"`
+	Manager string    `json:"manager"`
+	Members []string  `json:"members"`
+}
+
+// Allows tells whether a user with a role may access the project. Managers
+// only have access to the projects they manage, members to the projects they
+// were added to.
+func (a *ProjectAccess) Allows(username string, role string) bool {
+	switch role {
+	case "PROJECT_MANAGER":
+		return a.Manager == username
+	case "PROJECT_MEMBER":
+		for _, member := range a.Members {
+			if member == username {
+				return true
+			}
+		}
+	}
+	return false
+}
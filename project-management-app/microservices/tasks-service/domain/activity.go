@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+const (
+	ACTIVITY_TASK_CREATED        = "task.created"
+	ACTIVITY_TASK_UPDATED        = "task.updated"
+	ACTIVITY_TASK_STATUS_CHANGED = "task.status_changed"
+	ACTIVITY_TASK_REVERTED       = "task.reverted"
+	ACTIVITY_TASK_MEMBER_ADDED   = "task.member_added"
+	ACTIVITY_TASK_MEMBER_REMOVED = "task.member_removed"
+	ACTIVITY_WORKFLOW_UPDATED    = "workflow.updated"
+	ACTIVITY_DEPENDENCY_ADDED    = "task.dependency_added"
+	ACTIVITY_DEPENDENCY_REMOVED  = "task.dependency_removed"
+	ACTIVITY_CHECKLIST_UPDATED   = "task.checklist_updated"
+	ACTIVITY_TIME_LOGGED         = "task.time_logged"
+	ACTIVITY_COMMENT_ADDED       = "task.comment_added"
+	ACTIVITY_ATTACHMENT_ADDED    = "task.attachment_added"
+	ACTIVITY_ATTACHMENT_REMOVED  = "task.attachment_removed"
+	ACTIVITY_TASK_DELETED        = "task.deleted"
+	ACTIVITY_TASK_RESTORED       = "task.restored"
+	ACTIVITY_TASK_PURGED         = "task.purged"
+	ACTIVITY_LABEL_ADDED         = "task.label_added"
+	ACTIVITY_LABEL_REMOVED       = "task.label_removed"
+	ACTIVITY_LABEL_CREATED       = "label.created"
+	ACTIVITY_LABEL_UPDATED       = "label.updated"
+	ACTIVITY_LABEL_DELETED       = "label.deleted"
+	ACTIVITY_RECURRENCE_UPDATED  = "task.recurrence_updated"
+	ACTIVITY_TASK_SPRINT_CHANGED = "task.sprint_changed"
+	ACTIVITY_SPRINT_CREATED      = "sprint.created"
+	ACTIVITY_SPRINT_STARTED      = "sprint.started"
+	ACTIVITY_SPRINT_CLOSED       = "sprint.closed"
+	ACTIVITY_SPRINT_DELETED      = "sprint.deleted"
+	ACTIVITY_TASKS_IMPORTED      = "tasks.imported"
+)
+
+// Activity is an entry of a project's activity log. Task activity is kept by
+// projects-service, tasks-service only reports it.
+type Activity struct {
+	Actor      string                 `json:"actor"`
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type"`
+	Target     string                 `json:"target"`
+	Before     map[string]interface{} `json:"before,omitempty"`
+	After      map[string]interface{} `json:"after,omitempty"`
+	TraceId    string                 `json:"trace_id,omitempty"`
+	Source     string                 `json:"source"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Attachment describes a file attached to a task. The content itself lives in
+// the blob store under StorageKey.
+type Attachment struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Task        string             `bson:"task" json:"task"`
+	Project     string             `bson:"project" json:"project"`
+	FileName    string             `bson:"file_name" json:"file_name"`
+	ContentType string             `bson:"content_type" json:"content_type"`
+	Size        int64              `bson:"size" json:"size"`
+	Checksum    string             `bson:"checksum" json:"checksum"`
+	StorageKey  string             `bson:"storage_key" json:"-"`
+	UploadedBy  string             `bson:"uploaded_by" json:"uploaded_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type Attachments []*Attachment
+
+func (a *Attachments) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(a)
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// rankDigits are the digits of ranks. Ranks are base-36 fractions written
+// without the leading "0.", so they compare like strings and a new rank can
+// always be found between two others without touching any other task.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Board is the kanban board of a project, one column per workflow state.
+type Board struct {
+	Project string         `json:"project"`
+	Columns []*BoardColumn `json:"columns"`
+}
+
+type BoardColumn struct {
+	State    string `json:"state"`
+	Category string `json:"category"`
+	Tasks    Tasks  `json:"tasks"`
+}
+
+// Move places a task in a column of the board, right after the task After
+// or right before the task Before. Without either it goes to the bottom.
+type Move struct {
+	State  string `json:"state"`
+	After  string `json:"after,omitempty"`
+	Before string `json:"before,omitempty"`
+}
+
+// BulkMove moves several tasks to the bottom of a column, in the given order.
+// Versions optionally holds the version each task must still be at, like an
+// If-Match header for every task.
+type BulkMove struct {
+	Tasks    []string         `json:"tasks"`
+	State    string           `json:"state"`
+	Versions map[string]int64 `json:"versions,omitempty"`
+}
+
+func (b *Board) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(b)
+}
+
+// Column returns the column of a state.
+func (b *Board) Column(state string) (*BoardColumn, bool) {
+	for _, column := range b.Columns {
+		if column.State == state {
+			return column, true
+		}
+	}
+	return nil, false
+}
+
+// SortByRank orders tasks by rank. Tasks that were never moved on the board
+// have no rank and come last, oldest first.
+func SortByRank(tasks Tasks) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		switch {
+		case a.Rank == "" && b.Rank == "":
+			return a.CreatedAt.Before(b.CreatedAt)
+		case a.Rank == "" || b.Rank == "":
+			return b.Rank == ""
+		default:
+			return a.Rank < b.Rank
+		}
+	})
+}
+
+// RankBetween returns a rank that sorts after prev and before next. An empty
+// prev is the top of the column, an empty next the bottom.
+func RankBetween(prev string, next string) (string, error) {
+	if next != "" && prev >= next {
+		return "", fmt.Errorf("rank %q is not before %q", prev, next)
+	}
+	if strings.HasSuffix(prev, "0") || strings.HasSuffix(next, "0") {
+		return "", fmt.Errorf("invalid rank %q %q", prev, next)
+	}
+	return midpoint(prev, next), nil
+}
+
+func midpoint(a string, b string) string {
+	if b != "" {
+		// Keep the common prefix and look for a rank after it.
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	digitA := strings.IndexByte(rankDigits, digitAt(a, 0))
+	digitB := len(rankDigits)
+	if b != "" {
+		digitB = strings.IndexByte(rankDigits, b[0])
+	}
+	if digitB-digitA > 1 {
+		return string(rankDigits[(digitA+digitB+1)/2])
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(rankDigits[digitA]) + midpoint(suffix(a, 1), "")
+}
+
+// SpreadRanks returns n evenly spaced ranks of equal length, used to rank a
+// column for the first time.
+func SpreadRanks(n int) []string {
+	width, capacity := 1, len(rankDigits)
+	for capacity <= n {
+		width++
+		capacity *= len(rankDigits)
+	}
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		value := (i + 1) * capacity / (n + 1)
+		digits := make([]byte, width)
+		for d := width - 1; d >= 0; d-- {
+			digits[d] = rankDigits[value%len(rankDigits)]
+			value /= len(rankDigits)
+		}
+		ranks[i] = strings.TrimRight(string(digits), "0")
+	}
+	return ranks
+}
+
+func digitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
+
+func suffix(rank string, n int) string {
+	if n < len(rank) {
+		return rank[n:]
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// checkRank fails when rank isn't a valid rank strictly between prev and
+// next, where an empty prev or next is an open end.
+func checkRank(t *testing.T, prev string, next string, rank string) {
+	t.Helper()
+	if rank == "" || strings.HasSuffix(rank, "0") {
+		t.Fatalf("RankBetween(%q, %q) = %q, not a valid rank", prev, next, rank)
+	}
+	if strings.Trim(rank, rankDigits) != "" {
+		t.Fatalf("RankBetween(%q, %q) = %q, has digits outside base 36", prev, next, rank)
+	}
+	if rank <= prev || (next != "" && rank >= next) {
+		t.Fatalf("RankBetween(%q, %q) = %q, not between them", prev, next, rank)
+	}
+}
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+	}{
+		{"empty column", "", ""},
+		{"top of the column", "", "i"},
+		{"bottom of the column", "i", ""},
+		{"bottom after the last digit", "z", ""},
+		{"bottom after a run of last digits", "zzz", ""},
+		{"top before the first digit", "", "1"},
+		{"top before a long rank", "", "01"},
+		{"wide gap", "1", "y"},
+		{"adjacent digits", "a", "b"},
+		{"adjacent digits with a longer next", "a", "b5"},
+		{"next extends prev", "a", "a1"},
+		{"common prefix", "abc", "abd"},
+		{"deep common prefix", "a00001", "a00002"},
+		{"prev longer than next", "az5", "b"},
+		{"adjacent last digits", "y", "z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, err := RankBetween(tt.prev, tt.next)
+			if err != nil {
+				t.Fatalf("RankBetween(%q, %q): %v", tt.prev, tt.next, err)
+			}
+			checkRank(t, tt.prev, tt.next, rank)
+		})
+	}
+}
+
+func TestRankBetweenRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+	}{
+		{"same rank", "a", "a"},
+		{"reversed", "b", "a"},
+		{"prefix after its extension", "a1", "a"},
+		{"trailing zero in prev", "a0", "b"},
+		{"trailing zero in next", "a", "b0"},
+		{"trailing zero at the bottom", "i0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rank, err := RankBetween(tt.prev, tt.next); err == nil {
+				t.Fatalf("RankBetween(%q, %q) = %q, want an error", tt.prev, tt.next, rank)
+			}
+		})
+	}
+}
+
+func TestRankBetweenRepeatedMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves int
+		place func(ranks []string) (prev string, next string, at int)
+	}{
+		{"always to the top", 200, func(ranks []string) (string, string, int) {
+			if len(ranks) == 0 {
+				return "", "", 0
+			}
+			return "", ranks[0], 0
+		}},
+		{"always to the bottom", 200, func(ranks []string) (string, string, int) {
+			if len(ranks) == 0 {
+				return "", "", 0
+			}
+			return ranks[len(ranks)-1], "", len(ranks)
+		}},
+		{"always after the first", 200, func(ranks []string) (string, string, int) {
+			switch len(ranks) {
+			case 0:
+				return "", "", 0
+			case 1:
+				return ranks[0], "", 1
+			}
+			return ranks[0], ranks[1], 1
+		}},
+		{"always in the middle", 200, func(ranks []string) (string, string, int) {
+			switch len(ranks) {
+			case 0:
+				return "", "", 0
+			case 1:
+				return "", ranks[0], 0
+			}
+			at := len(ranks) / 2
+			return ranks[at-1], ranks[at], at
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranks := []string{}
+			for i := 0; i < tt.moves; i++ {
+				prev, next, at := tt.place(ranks)
+				rank, err := RankBetween(prev, next)
+				if err != nil {
+					t.Fatalf("move %d: RankBetween(%q, %q): %v", i, prev, next, err)
+				}
+				checkRank(t, prev, next, rank)
+				ranks = append(ranks[:at], append([]string{rank}, ranks[at:]...)...)
+			}
+			for i := 1; i < len(ranks); i++ {
+				if ranks[i-1] >= ranks[i] {
+					t.Fatalf("ranks %q and %q are out of order", ranks[i-1], ranks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSpreadRanks(t *testing.T) {
+	tests := []struct {
+		n     int
+		width int
+	}{
+		{0, 0},
+		{1, 1},
+		{2, 1},
+		{35, 1},
+		{36, 2},
+		{500, 2},
+		{1295, 2},
+		{1296, 3},
+	}
+
+	for _, tt := range tests {
+		ranks := SpreadRanks(tt.n)
+		if len(ranks) != tt.n {
+			t.Fatalf("SpreadRanks(%d) returned %d ranks", tt.n, len(ranks))
+		}
+		for i, rank := range ranks {
+			if rank == "" || strings.HasSuffix(rank, "0") || len(rank) > tt.width {
+				t.Fatalf("SpreadRanks(%d)[%d] = %q, want a rank of at most %d digits", tt.n, i, rank, tt.width)
+			}
+			if i > 0 && ranks[i-1] >= rank {
+				t.Fatalf("SpreadRanks(%d) has %q before %q", tt.n, ranks[i-1], rank)
+			}
+		}
+		// There is room before the first and after the last rank.
+		if tt.n > 0 {
+			if _, err := RankBetween("", ranks[0]); err != nil {
+				t.Errorf("no rank before SpreadRanks(%d)[0]: %v", tt.n, err)
+			}
+			if _, err := RankBetween(ranks[tt.n-1], ""); err != nil {
+				t.Errorf("no rank after the last of SpreadRanks(%d): %v", tt.n, err)
+			}
+		}
+	}
+}
+
+func TestSortByRank(t *testing.T) {
+	now := time.Now()
+	tasks := Tasks{
+		{Name: "unranked new", CreatedAt: now},
+		{Name: "second", Rank: "i", CreatedAt: now.Add(-time.Hour)},
+		{Name: "unranked old", CreatedAt: now.Add(-2 * time.Hour)},
+		{Name: "first", Rank: "5", CreatedAt: now},
+		{Name: "third", Rank: "i5", CreatedAt: now.Add(-3 * time.Hour)},
+	}
+
+	SortByRank(tasks)
+
+	want := []string{"first", "second", "third", "unranked old", "unranked new"}
+	for i, task := range tasks {
+		if task.Name != want[i] {
+			t.Fatalf("task %d is %q, want %q", i, task.Name, want[i])
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// CalendarFeed is the secret a user's calendar subscription URL is built
+// from. Calendar apps can't log in, so anyone with the URL can read the feed
+// until the user resets it.
+type CalendarFeed struct {
+	Username  string    `bson:"_id" json:"username"`
+	Token     string    `bson:"token" json:"token"`
+	URL       string    `bson:"-" json:"url"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+func (f *CalendarFeed) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(f)
+}
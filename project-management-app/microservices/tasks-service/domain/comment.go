@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]+)`)
+
+// Comment is a message on a task. Replies point to the top-level comment of
+// their thread through Parent.
+type Comment struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Task      string             `bson:"task" json:"task"`
+	Project   string             `bson:"project" json:"project"`
+	Parent    string             `bson:"parent,omitempty" json:"parent,omitempty"`
+	Author    string             `bson:"author" json:"author"`
+	Body      string             `bson:"body" json:"body"`
+	Mentions  []string           `bson:"mentions,omitempty" json:"mentions,omitempty"`
+	Deleted   bool               `bson:"deleted,omitempty" json:"deleted,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	EditedAt  *time.Time         `bson:"edited_at,omitempty" json:"edited_at,omitempty"`
+}
+
+type Comments []*Comment
+
+// CommentThread is a top-level comment with its replies, oldest first.
+type CommentThread struct {
+	*Comment
+	Replies Comments `json:"replies"`
+}
+
+type CommentPage struct {
+	Items []*CommentThread `json:"items"`
+	Total int64            `json:"total"`
+	Page  int              `json:"page"`
+	Size  int              `json:"size"`
+}
+
+// ParseMentions returns the distinct usernames mentioned with @username in a
+// text, in order of appearance.
+func ParseMentions(text string) []string {
+	seen := map[string]bool{}
+	mentions := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		username := match[1]
+		for len(username) > 0 && (username[len(username)-1] == '.' || username[len(username)-1] == '-') {
+			username = username[:len(username)-1]
+		}
+		if username != "" && !seen[username] {
+			seen[username] = true
+			mentions = append(mentions, username)
+		}
+	}
+	return mentions
+}
+
+func (c *Comment) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(c)
+}
+
+func (p *CommentPage) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(p)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// DependencyGraph holds the tasks of a project and the "blocks" relations
+// between them. An edge goes from the blocking task to the blocked one.
+type DependencyGraph struct {
+	Project string           `json:"project"`
+	Nodes   []DependencyNode `json:"nodes"`
+	Edges   []DependencyEdge `json:"edges"`
+}
+
+type DependencyNode struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	State   string `json:"state"`
+	Blocked bool   `json:"blocked"`
+}
+
+type DependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TaskDependencies lists the tasks blocking a task and the tasks it blocks.
+type TaskDependencies struct {
+	Task      string `json:"task"`
+	BlockedBy Tasks  `json:"blocked_by"`
+	Blocks    Tasks  `json:"blocks"`
+}
+
+func (g *DependencyGraph) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(g)
+}
+
+func (d *TaskDependencies) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(d)
+}
@@ -11,6 +11,31 @@ var (
 	errInvalidCredentials      error = errors.New("incorrect username or password")
 	errInvalidToken            error = errors.New("token invalid")
 	errUnauthorized            error = errors.New("unauthorized")
+	errInvalidWorkflow         error = errors.New("invalid workflow")
+	errTransitionNotAllowed    error = errors.New("transition not allowed")
+	errTransitionForbidden     error = errors.New("role not allowed to take this transition")
+	errDependencyCycle         error = errors.New("dependency would create a cycle")
+	errTaskBlocked             error = errors.New("task is blocked by open tasks")
+	errTimerRunning            error = errors.New("a timer is already running")
+	errInvalidMention          error = errors.New("mentioned users are not project members")
+	errInvalidAttachment       error = errors.New("invalid attachment")
+	errAttachmentTooLarge      error = errors.New("attachment is too large")
+	errRestoreConflict         error = errors.New("task can't be restored")
+	errInvalidMove             error = errors.New("invalid move")
+	errInvalidLabel            error = errors.New("invalid label")
+	errLabelExists             error = errors.New("label already exists")
+	errInvalidFilter           error = errors.New("invalid filter")
+	errFilterExists            error = errors.New("filter already exists")
+	errVersionConflict         error = errors.New("task was changed by someone else")
+	errInvalidRevision         error = errors.New("invalid revision")
+	errInvalidRecurrence       error = errors.New("invalid recurrence rule")
+	errInvalidSprint           error = errors.New("invalid sprint")
+	errSprintState             error = errors.New("sprint is not in the right state")
+	errInvalidReport           error = errors.New("invalid report")
+	errTaskNameExists          error = errors.New("a task with this name already exists in the project")
+	errInvalidTaskKey          error = errors.New("invalid task key")
+	errInvalidImport           error = errors.New("invalid import")
+	errInvalidInput            error = errors.New("invalid input")
 )
 
 func ErrConnectionNotFound() error {
@@ -44,3 +69,103 @@ func ErrInvalidToken() error {
 func ErrUnauthorized() error {
 	return errUnauthorized
 }
+
+func ErrInvalidWorkflow() error {
+	return errInvalidWorkflow
+}
+
+func ErrTransitionNotAllowed() error {
+	return errTransitionNotAllowed
+}
+
+func ErrTransitionForbidden() error {
+	return errTransitionForbidden
+}
+
+func ErrDependencyCycle() error {
+	return errDependencyCycle
+}
+
+func ErrTaskBlocked() error {
+	return errTaskBlocked
+}
+
+func ErrTimerRunning() error {
+	return errTimerRunning
+}
+
+func ErrInvalidMention() error {
+	return errInvalidMention
+}
+
+func ErrInvalidAttachment() error {
+	return errInvalidAttachment
+}
+
+func ErrAttachmentTooLarge() error {
+	return errAttachmentTooLarge
+}
+
+func ErrRestoreConflict() error {
+	return errRestoreConflict
+}
+
+func ErrInvalidMove() error {
+	return errInvalidMove
+}
+
+func ErrInvalidLabel() error {
+	return errInvalidLabel
+}
+
+func ErrLabelExists() error {
+	return errLabelExists
+}
+
+func ErrInvalidFilter() error {
+	return errInvalidFilter
+}
+
+func ErrFilterExists() error {
+	return errFilterExists
+}
+
+func ErrVersionConflict() error {
+	return errVersionConflict
+}
+
+func ErrInvalidRevision() error {
+	return errInvalidRevision
+}
+
+func ErrInvalidRecurrence() error {
+	return errInvalidRecurrence
+}
+
+func ErrInvalidSprint() error {
+	return errInvalidSprint
+}
+
+func ErrSprintState() error {
+	return errSprintState
+}
+
+func ErrInvalidReport() error {
+	return errInvalidReport
+}
+
+func ErrTaskNameExists() error {
+	return errTaskNameExists
+}
+
+func ErrInvalidTaskKey() error {
+	return errInvalidTaskKey
+}
+
+func ErrInvalidImport() error {
+	return errInvalidImport
+}
+
+func ErrInvalidInput() error {
+	return errInvalidInput
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskQuery selects tasks. Every set field narrows the result, list fields
+// match tasks with any of the values.
+type TaskQuery struct {
+	Project     string     `bson:"project,omitempty" json:"project,omitempty"`
+	Labels      []string   `bson:"labels,omitempty" json:"labels,omitempty"`
+	Statuses    []Status   `bson:"statuses,omitempty" json:"statuses,omitempty"`
+	States      []string   `bson:"states,omitempty" json:"states,omitempty"`
+	Assignee    string     `bson:"assignee,omitempty" json:"assignee,omitempty"`
+	Sprint      string     `bson:"sprint,omitempty" json:"sprint,omitempty"`
+	Text        string     `bson:"text,omitempty" json:"text,omitempty"`
+	DueFrom     *time.Time `bson:"due_from,omitempty" json:"due_from,omitempty"`
+	DueTo       *time.Time `bson:"due_to,omitempty" json:"due_to,omitempty"`
+	CreatedFrom *time.Time `bson:"created_from,omitempty" json:"created_from,omitempty"`
+	CreatedTo   *time.Time `bson:"created_to,omitempty" json:"created_to,omitempty"`
+}
+
+// SavedFilter is a named task query of a user.
+type SavedFilter struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Owner     string             `bson:"owner" json:"owner"`
+	Name      string             `bson:"name" json:"name"`
+	Query     TaskQuery          `bson:"query" json:"query"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type SavedFilters []*SavedFilter
+
+// Empty reports whether the query doesn't restrict anything.
+func (q TaskQuery) Empty() bool {
+	return q.Project == "" && len(q.Labels) == 0 && len(q.Statuses) == 0 && len(q.States) == 0 &&
+		q.Assignee == "" && q.Sprint == "" && q.Text == "" && q.DueFrom == nil && q.DueTo == nil &&
+		q.CreatedFrom == nil && q.CreatedTo == nil
+}
+
+// Validate checks that the statuses exist and the date ranges are ordered.
+func (q TaskQuery) Validate() error {
+	for _, status := range q.Statuses {
+		if status < PENDING || status > FINISHED {
+			return fmt.Errorf("%w: unknown status %d", ErrInvalidFilter(), status)
+		}
+	}
+	if q.DueFrom != nil && q.DueTo != nil && !q.DueFrom.Before(*q.DueTo) {
+		return fmt.Errorf("%w: due_from must be before due_to", ErrInvalidFilter())
+	}
+	if q.CreatedFrom != nil && q.CreatedTo != nil && !q.CreatedFrom.Before(*q.CreatedTo) {
+		return fmt.Errorf("%w: created_from must be before created_to", ErrInvalidFilter())
+	}
+	return nil
+}
+
+// Validate checks the name and the query of a filter.
+func (f *SavedFilter) Validate() error {
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Name == "" || len(f.Name) > 100 {
+		return fmt.Errorf("%w: name must have 1 to 100 characters", ErrInvalidFilter())
+	}
+	return f.Query.Validate()
+}
+
+func (f *SavedFilter) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(f)
+}
+
+func (f *SavedFilter) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(f)
+}
+
+func (f *SavedFilters) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(f)
+}
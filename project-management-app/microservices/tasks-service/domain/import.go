@@ -0,0 +1,541 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sources tasks can be imported from.
+const (
+	IMPORT_CSV    = "csv"
+	IMPORT_JIRA   = "jira"
+	IMPORT_TRELLO = "trello"
+)
+
+// States of an import job.
+const (
+	IMPORT_QUEUED  = "QUEUED"
+	IMPORT_RUNNING = "RUNNING"
+	IMPORT_DONE    = "DONE"
+	IMPORT_FAILED  = "FAILED"
+)
+
+// Outcomes of an imported row. A dry run only tells whether a row is valid.
+const (
+	ROW_VALID    = "VALID"
+	ROW_IMPORTED = "IMPORTED"
+	ROW_FAILED   = "FAILED"
+)
+
+// MAX_IMPORT_ROWS bounds the number of tasks a single import may create.
+const MAX_IMPORT_ROWS = 2000
+
+// Fields of a task that columns of a CSV file can be mapped to.
+const (
+	FIELD_NAME           = "name"
+	FIELD_DESCRIPTION    = "description"
+	FIELD_STATE          = "state"
+	FIELD_DUE_DATE       = "due_date"
+	FIELD_PRIORITY       = "priority"
+	FIELD_STORY_POINTS   = "story_points"
+	FIELD_ESTIMATE_HOURS = "estimate_hours"
+	FIELD_LABELS         = "labels"
+	FIELD_MEMBERS        = "members"
+)
+
+// csvColumns recognizes the usual headers of exported backlogs when no
+// mapping is given for a column.
+var csvColumns = map[string]string{
+	"name":           FIELD_NAME,
+	"summary":        FIELD_NAME,
+	"title":          FIELD_NAME,
+	"task":           FIELD_NAME,
+	"description":    FIELD_DESCRIPTION,
+	"desc":           FIELD_DESCRIPTION,
+	"state":          FIELD_STATE,
+	"status":         FIELD_STATE,
+	"due_date":       FIELD_DUE_DATE,
+	"due date":       FIELD_DUE_DATE,
+	"due":            FIELD_DUE_DATE,
+	"priority":       FIELD_PRIORITY,
+	"story_points":   FIELD_STORY_POINTS,
+	"story points":   FIELD_STORY_POINTS,
+	"points":         FIELD_STORY_POINTS,
+	"estimate_hours": FIELD_ESTIMATE_HOURS,
+	"estimate hours": FIELD_ESTIMATE_HOURS,
+	"estimate":       FIELD_ESTIMATE_HOURS,
+	"labels":         FIELD_LABELS,
+	"label":          FIELD_LABELS,
+	"tags":           FIELD_LABELS,
+	"members":        FIELD_MEMBERS,
+	"assignee":       FIELD_MEMBERS,
+	"assignees":      FIELD_MEMBERS,
+}
+
+// ImportMapping tells how to read an import. Columns maps CSV headers to task
+// fields, Members maps the users of the other tracker to usernames.
+type ImportMapping struct {
+	Columns map[string]string `json:"columns"`
+	Members map[string]string `json:"members"`
+}
+
+// ImportRow is a task read from an import file. Row is its line in a CSV file
+// or its position in a JSON export. Errors are the values that couldn't be
+// read.
+type ImportRow struct {
+	Row           int
+	Name          string
+	Description   string
+	State         string
+	Status        Status
+	DueDate       *time.Time
+	Priority      Priority
+	StoryPoints   float64
+	EstimateHours float64
+	Labels        []string
+	Members       []string
+	Errors        []string
+}
+
+// ImportRowResult reports what happened to a row of an import.
+type ImportRowResult struct {
+	Row      int      `bson:"row" json:"row"`
+	Name     string   `bson:"name" json:"name"`
+	Outcome  string   `bson:"outcome" json:"outcome"`
+	Task     string   `bson:"task,omitempty" json:"task,omitempty"`
+	Key      string   `bson:"key,omitempty" json:"key,omitempty"`
+	Errors   []string `bson:"errors,omitempty" json:"errors,omitempty"`
+	Warnings []string `bson:"warnings,omitempty" json:"warnings,omitempty"`
+}
+
+// ImportJob imports the rows of a file into a project in the background. A
+// dry run checks every row without creating anything.
+type ImportJob struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Project    string             `bson:"project" json:"project"`
+	Source     string             `bson:"source" json:"source"`
+	FileName   string             `bson:"file_name" json:"file_name"`
+	DryRun     bool               `bson:"dry_run" json:"dry_run"`
+	State      string             `bson:"state" json:"state"`
+	Total      int                `bson:"total" json:"total"`
+	Processed  int                `bson:"processed" json:"processed"`
+	Imported   int                `bson:"imported" json:"imported"`
+	Failed     int                `bson:"failed" json:"failed"`
+	Results    []*ImportRowResult `bson:"results" json:"results"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedBy  string             `bson:"created_by" json:"created_by"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt  *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
+
+type ImportJobs []*ImportJob
+
+func (j *ImportJob) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(j)
+}
+
+func (j *ImportJobs) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(j)
+}
+
+// ParseImport reads the rows of an import file of the given source.
+func ParseImport(source string, r io.Reader, mapping ImportMapping) ([]*ImportRow, error) {
+	var rows []*ImportRow
+	var err error
+	switch source {
+	case IMPORT_CSV:
+		rows, err = parseCSVImport(r, mapping.Columns)
+	case IMPORT_JIRA:
+		rows, err = parseJiraImport(r)
+	case IMPORT_TRELLO:
+		rows, err = parseTrelloImport(r)
+	default:
+		return nil, fmt.Errorf("%w: unknown source %q", ErrInvalidImport(), source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no tasks found", ErrInvalidImport())
+	}
+	if len(rows) > MAX_IMPORT_ROWS {
+		return nil, fmt.Errorf("%w: at most %d tasks can be imported at once", ErrInvalidImport(), MAX_IMPORT_ROWS)
+	}
+	for _, row := range rows {
+		for i, member := range row.Members {
+			if username, ok := mapping.Members[member]; ok {
+				row.Members[i] = username
+			}
+		}
+	}
+	return rows, nil
+}
+
+// parseCSVImport reads a CSV file with a header row. Columns are mapped by
+// the given mapping first, then by their usual names. Unmapped columns are
+// ignored. Labels and members are separated by commas or semicolons.
+func parseCSVImport(r io.Reader, columns map[string]string) ([]*ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't read header: %v", ErrInvalidImport(), err)
+	}
+	fields := make([]string, len(header))
+	named := false
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		field, ok := columns[column]
+		if !ok {
+			field = csvColumns[strings.ToLower(column)]
+		}
+		if field != "" && !validImportField(field) {
+			return nil, fmt.Errorf("%w: column %q is mapped to unknown field %q", ErrInvalidImport(), column, field)
+		}
+		fields[i] = field
+		named = named || field == FIELD_NAME
+	}
+	if !named {
+		return nil, fmt.Errorf("%w: no column is mapped to the name", ErrInvalidImport())
+	}
+
+	rows := []*ImportRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport(), err)
+		}
+		if blankRecord(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		row := &ImportRow{Row: line}
+		for i, value := range record {
+			if i < len(fields) {
+				row.set(fields[i], strings.TrimSpace(value))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func validImportField(field string) bool {
+	switch field {
+	case FIELD_NAME, FIELD_DESCRIPTION, FIELD_STATE, FIELD_DUE_DATE, FIELD_PRIORITY,
+		FIELD_STORY_POINTS, FIELD_ESTIMATE_HOURS, FIELD_LABELS, FIELD_MEMBERS:
+		return true
+	default:
+		return false
+	}
+}
+
+func blankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (row *ImportRow) set(field string, value string) {
+	if value == "" {
+		return
+	}
+	switch field {
+	case FIELD_NAME:
+		row.Name = value
+	case FIELD_DESCRIPTION:
+		row.Description = value
+	case FIELD_STATE:
+		row.State = value
+	case FIELD_DUE_DATE:
+		dueDate, err := parseImportDate(value)
+		if err != nil {
+			row.Errors = append(row.Errors, fmt.Sprintf("invalid due date %q", value))
+			return
+		}
+		row.DueDate = dueDate
+	case FIELD_PRIORITY:
+		priority, ok := ParseImportPriority(value)
+		if !ok {
+			row.Errors = append(row.Errors, fmt.Sprintf("invalid priority %q", value))
+			return
+		}
+		row.Priority = priority
+	case FIELD_STORY_POINTS:
+		points, err := strconv.ParseFloat(value, 64)
+		if err != nil || points < 0 {
+			row.Errors = append(row.Errors, fmt.Sprintf("invalid story points %q", value))
+			return
+		}
+		row.StoryPoints = points
+	case FIELD_ESTIMATE_HOURS:
+		hours, err := strconv.ParseFloat(value, 64)
+		if err != nil || hours < 0 {
+			row.Errors = append(row.Errors, fmt.Sprintf("invalid estimate %q", value))
+			return
+		}
+		row.EstimateHours = hours
+	case FIELD_LABELS:
+		row.Labels = append(row.Labels, splitImportList(value)...)
+	case FIELD_MEMBERS:
+		row.Members = append(row.Members, splitImportList(value)...)
+	}
+}
+
+func splitImportList(value string) []string {
+	items := []string{}
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+var importDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02",
+	"02.01.2006",
+	"02.01.2006.",
+}
+
+func parseImportDate(value string) (*time.Time, error) {
+	for _, layout := range importDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return &date, nil
+		}
+	}
+	return nil, errors.New("invalid date")
+}
+
+// ParseImportPriority reads a priority, also as it is named by Jira.
+func ParseImportPriority(value string) (Priority, bool) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "CRITICAL", "HIGHEST", "BLOCKER":
+		return PRIORITY_CRITICAL, true
+	case "HIGH", "MAJOR":
+		return PRIORITY_HIGH, true
+	case "MEDIUM", "NORMAL":
+		return PRIORITY_MEDIUM, true
+	case "LOW", "LOWEST", "MINOR", "TRIVIAL":
+		return PRIORITY_LOW, true
+	default:
+		return "", false
+	}
+}
+
+// ImportStatus guesses the status behind the name of a state in another
+// tracker, 0 when it can't tell.
+func ImportStatus(state string) Status {
+	switch strings.ToLower(strings.TrimSpace(state)) {
+	case "pending", "to do", "todo", "open", "new", "backlog", "selected for development":
+		return PENDING
+	case "in_progress", "in progress", "doing", "in review", "started":
+		return IN_PROGRESS
+	case "finished", "done", "closed", "resolved", "complete", "completed":
+		return FINISHED
+	default:
+		return 0
+	}
+}
+
+// jiraExport is the result of a Jira issue search, as saved by its JSON
+// export.
+type jiraExport struct {
+	Issues []struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary     string `json:"summary"`
+			Description any    `json:"description"`
+			Status      *struct {
+				Name           string `json:"name"`
+				StatusCategory *struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+			Priority *struct {
+				Name string `json:"name"`
+			} `json:"priority"`
+			DueDate  string   `json:"duedate"`
+			Labels   []string `json:"labels"`
+			Assignee *struct {
+				Name         string `json:"name"`
+				EmailAddress string `json:"emailAddress"`
+				AccountId    string `json:"accountId"`
+			} `json:"assignee"`
+			OriginalEstimate *float64 `json:"timeoriginalestimate"`
+			StoryPoints      *float64 `json:"customfield_10016"`
+		} `json:"fields"`
+	} `json:"issues"`
+}
+
+// parseJiraImport reads the issues of a Jira JSON export. Assignees are
+// identified by their name, their email address or their account id, in that
+// order, for the member mapping.
+func parseJiraImport(r io.Reader) ([]*ImportRow, error) {
+	var export jiraExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport(), err)
+	}
+
+	rows := []*ImportRow{}
+	for i, issue := range export.Issues {
+		fields := issue.Fields
+		row := &ImportRow{Row: i + 1, Name: strings.TrimSpace(fields.Summary), Labels: fields.Labels}
+		if row.Name == "" {
+			row.Name = issue.Key
+		}
+		switch description := fields.Description.(type) {
+		case string:
+			row.Description = description
+		case map[string]any:
+			// Jira Cloud exports descriptions as rich text documents.
+			row.Description = strings.TrimSpace(jiraText(description))
+		}
+		if fields.Status != nil {
+			row.State = fields.Status.Name
+			if fields.Status.StatusCategory != nil {
+				switch fields.Status.StatusCategory.Key {
+				case "new":
+					row.Status = PENDING
+				case "indeterminate":
+					row.Status = IN_PROGRESS
+				case "done":
+					row.Status = FINISHED
+				}
+			}
+		}
+		if fields.Priority != nil {
+			row.set(FIELD_PRIORITY, fields.Priority.Name)
+		}
+		row.set(FIELD_DUE_DATE, fields.DueDate)
+		if assignee := fields.Assignee; assignee != nil {
+			for _, id := range []string{assignee.Name, assignee.EmailAddress, assignee.AccountId} {
+				if id != "" {
+					row.Members = []string{id}
+					break
+				}
+			}
+		}
+		if fields.OriginalEstimate != nil {
+			row.EstimateHours = *fields.OriginalEstimate / 3600
+		}
+		if fields.StoryPoints != nil {
+			row.StoryPoints = *fields.StoryPoints
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// jiraText collects the text of a rich text document, a paragraph per line.
+func jiraText(node map[string]any) string {
+	if text, ok := node["text"].(string); ok {
+		return text
+	}
+	var text strings.Builder
+	content, _ := node["content"].([]any)
+	for _, child := range content {
+		if child, ok := child.(map[string]any); ok {
+			text.WriteString(jiraText(child))
+		}
+	}
+	if node["type"] == "paragraph" {
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+// trelloExport is the JSON export of a Trello board.
+type trelloExport struct {
+	Lists []struct {
+		Id     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Members []struct {
+		Id       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+	Cards []struct {
+		Name      string   `json:"name"`
+		Desc      string   `json:"desc"`
+		Due       string   `json:"due"`
+		Closed    bool     `json:"closed"`
+		IdList    string   `json:"idList"`
+		IdMembers []string `json:"idMembers"`
+		Labels    []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+}
+
+// parseTrelloImport reads the cards of a Trello board export. The list of a
+// card becomes its state and members are identified by their Trello
+// username. Archived cards and the cards of archived lists are skipped.
+func parseTrelloImport(r io.Reader) ([]*ImportRow, error) {
+	var export trelloExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport(), err)
+	}
+
+	lists := map[string]string{}
+	closedLists := map[string]bool{}
+	for _, list := range export.Lists {
+		lists[list.Id] = list.Name
+		closedLists[list.Id] = list.Closed
+	}
+	members := map[string]string{}
+	for _, member := range export.Members {
+		members[member.Id] = member.Username
+	}
+
+	rows := []*ImportRow{}
+	for i, card := range export.Cards {
+		if card.Closed || closedLists[card.IdList] {
+			continue
+		}
+		row := &ImportRow{Row: i + 1, Name: strings.TrimSpace(card.Name), Description: card.Desc, State: lists[card.IdList]}
+		row.set(FIELD_DUE_DATE, card.Due)
+		for _, id := range card.IdMembers {
+			if username, ok := members[id]; ok {
+				row.Members = append(row.Members, username)
+			} else {
+				row.Members = append(row.Members, id)
+			}
+		}
+		for _, label := range card.Labels {
+			name := label.Name
+			if name == "" {
+				// Trello labels may only have a color.
+				name = label.Color
+			}
+			if name != "" {
+				row.Labels = append(row.Labels, name)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
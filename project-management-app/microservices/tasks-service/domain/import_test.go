@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) *time.Time {
+	t := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// checkImportRows compares rows field by field, due dates by the instant
+// they stand for.
+func checkImportRows(t *testing.T, got []*ImportRow, want []*ImportRow) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := *got[i], *want[i]
+		if (g.DueDate == nil) != (w.DueDate == nil) || (g.DueDate != nil && !g.DueDate.Equal(*w.DueDate)) {
+			t.Errorf("row %d: due date = %v, want %v", w.Row, g.DueDate, w.DueDate)
+		}
+		g.DueDate, w.DueDate = nil, nil
+		if !reflect.DeepEqual(g, w) {
+			t.Errorf("row %d:\n got %+v\nwant %+v", w.Row, g, w)
+		}
+	}
+}
+
+func TestParseImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		file    string
+		mapping ImportMapping
+		want    []*ImportRow
+	}{
+		{
+			name:   "csv with usual headers",
+			source: IMPORT_CSV,
+			file: "\ufeffSummary,Description,Status,Due Date,Priority,Points,Estimate,Tags,Assignee,Ignored\n" +
+				"Write docs,Explain setup,In Progress,2024-05-01,High,3,1.5,\"docs; backend\",jdoe,x\n" +
+				"Fix bug,,Done,01.02.2024.,major,,,,\"jdoe, asmith\",\n" +
+				",,,,,,,,,\n" +
+				"Bad row,,,someday,urgent,-1,abc,,,\n",
+			mapping: ImportMapping{Members: map[string]string{"jdoe": "john"}},
+			want: []*ImportRow{
+				{
+					Row: 2, Name: "Write docs", Description: "Explain setup", State: "In Progress", DueDate: day(2024, 5, 1),
+					Priority: PRIORITY_HIGH, StoryPoints: 3, EstimateHours: 1.5, Labels: []string{"docs", "backend"}, Members: []string{"john"},
+				},
+				{Row: 3, Name: "Fix bug", State: "Done", DueDate: day(2024, 2, 1), Priority: PRIORITY_HIGH, Members: []string{"john", "asmith"}},
+				{Row: 5, Name: "Bad row", Errors: []string{
+					`invalid due date "someday"`, `invalid priority "urgent"`, `invalid story points "-1"`, `invalid estimate "abc"`,
+				}},
+			},
+		},
+		{
+			name:   "csv mapping before usual headers",
+			source: IMPORT_CSV,
+			file:   "Naziv,Rok,Title\nPrvi zadatak,15.03.2024,ignored\n",
+			mapping: ImportMapping{Columns: map[string]string{
+				"Naziv": FIELD_NAME,
+				"Rok":   FIELD_DUE_DATE,
+				"Title": "",
+			}},
+			want: []*ImportRow{{Row: 2, Name: "Prvi zadatak", DueDate: day(2024, 3, 15)}},
+		},
+		{
+			name:   "jira",
+			source: IMPORT_JIRA,
+			file: `{"issues": [
+				{"key": "PRJ-1", "fields": {
+					"summary": " Login page ", "description": "Plain text",
+					"status": {"name": "Selected for Development", "statusCategory": {"key": "new"}},
+					"priority": {"name": "Highest"}, "duedate": "2024-06-30", "labels": ["auth"],
+					"assignee": {"emailAddress": "jdoe@example.com", "accountId": "abc"},
+					"timeoriginalestimate": 7200, "customfield_10016": 5}},
+				{"key": "PRJ-2", "fields": {
+					"summary": "",
+					"description": {"type": "doc", "content": [
+						{"type": "paragraph", "content": [{"type": "text", "text": "First"}]},
+						{"type": "paragraph", "content": [{"type": "text", "text": "Second"}]}]},
+					"status": {"name": "Code Review", "statusCategory": {"key": "indeterminate"}},
+					"priority": {"name": "Urgent"},
+					"assignee": {"accountId": "abc"}}}
+			]}`,
+			mapping: ImportMapping{Members: map[string]string{"jdoe@example.com": "jdoe"}},
+			want: []*ImportRow{
+				{
+					Row: 1, Name: "Login page", Description: "Plain text", State: "Selected for Development", Status: PENDING,
+					DueDate: day(2024, 6, 30), Priority: PRIORITY_CRITICAL, StoryPoints: 5, EstimateHours: 2,
+					Labels: []string{"auth"}, Members: []string{"jdoe"},
+				},
+				{
+					Row: 2, Name: "PRJ-2", Description: "First\nSecond", State: "Code Review", Status: IN_PROGRESS,
+					Members: []string{"abc"}, Errors: []string{`invalid priority "Urgent"`},
+				},
+			},
+		},
+		{
+			name:   "trello",
+			source: IMPORT_TRELLO,
+			file: `{
+				"lists": [{"id": "l1", "name": "To Do"}, {"id": "l2", "name": "Old", "closed": true}],
+				"members": [{"id": "m1", "username": "jdoe"}],
+				"cards": [
+					{"name": "Card A", "desc": "Details", "due": "2024-07-01T10:00:00.000Z", "idList": "l1",
+					 "idMembers": ["m1", "m9"], "labels": [{"name": "ui", "color": "green"}, {"name": "", "color": "red"}]},
+					{"name": "Archived card", "idList": "l1", "closed": true},
+					{"name": "Card of an archived list", "idList": "l2"},
+					{"name": " Card D ", "idList": "l1"}
+				]}`,
+			mapping: ImportMapping{Members: map[string]string{"m9": "asmith"}},
+			want: []*ImportRow{
+				{
+					Row: 1, Name: "Card A", Description: "Details", State: "To Do",
+					DueDate: func() *time.Time { t := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC); return &t }(),
+					Labels:  []string{"ui", "red"}, Members: []string{"jdoe", "asmith"},
+				},
+				{Row: 4, Name: "Card D", State: "To Do"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseImport(tt.source, strings.NewReader(tt.file), tt.mapping)
+			if err != nil {
+				t.Fatalf("ParseImport: %v", err)
+			}
+			checkImportRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestParseImportRejects(t *testing.T) {
+	tooMany := "name\n" + strings.Repeat("task\n", MAX_IMPORT_ROWS+1)
+
+	tests := []struct {
+		name    string
+		source  string
+		file    string
+		mapping ImportMapping
+	}{
+		{"unknown source", "asana", "name\ntask\n", ImportMapping{}},
+		{"no source", "", "name\ntask\n", ImportMapping{}},
+		{"empty csv", IMPORT_CSV, "", ImportMapping{}},
+		{"csv without a name column", IMPORT_CSV, "description,status\nsomething,Done\n", ImportMapping{}},
+		{"csv column mapped to an unknown field", IMPORT_CSV, "name,owner\ntask,jdoe\n", ImportMapping{Columns: map[string]string{"owner": "owner"}}},
+		{"csv with only a header", IMPORT_CSV, "name,description\n", ImportMapping{}},
+		{"csv with only blank rows", IMPORT_CSV, "name,description\n,\n , \n", ImportMapping{}},
+		{"malformed csv", IMPORT_CSV, "name\n\"unclosed\n", ImportMapping{}},
+		{"too many rows", IMPORT_CSV, tooMany, ImportMapping{}},
+		{"malformed jira", IMPORT_JIRA, `{"issues": [`, ImportMapping{}},
+		{"jira without issues", IMPORT_JIRA, `{"issues": []}`, ImportMapping{}},
+		{"malformed trello", IMPORT_TRELLO, `[]`, ImportMapping{}},
+		{"trello with only archived cards", IMPORT_TRELLO, `{"cards": [{"name": "a", "closed": true}]}`, ImportMapping{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseImport(tt.source, strings.NewReader(tt.file), tt.mapping)
+			if !errors.Is(err, ErrInvalidImport()) {
+				t.Fatalf("ParseImport = %d rows, %v, want ErrInvalidImport", len(rows), err)
+			}
+		})
+	}
+}
+
+func TestImportStatus(t *testing.T) {
+	tests := []struct {
+		state string
+		want  Status
+	}{
+		{"To Do", PENDING},
+		{" backlog ", PENDING},
+		{"In Progress", IN_PROGRESS},
+		{"IN_PROGRESS", IN_PROGRESS},
+		{"Resolved", FINISHED},
+		{"Code Review", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ImportStatus(tt.state); got != tt.want {
+			t.Errorf("ImportStatus(%q) = %s, want %s", tt.state, got, tt.want)
+		}
+	}
+}
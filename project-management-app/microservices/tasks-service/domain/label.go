@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Label categorizes tasks of a project. Tasks refer to labels by id.
+type Label struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Project   string             `bson:"project" json:"project"`
+	Name      string             `bson:"name" json:"name"`
+	Color     string             `bson:"color" json:"color"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type Labels []*Label
+
+// Validate checks the name and the color, written as #rrggbb.
+func (l *Label) Validate() error {
+	l.Name = strings.TrimSpace(l.Name)
+	if l.Name == "" || len(l.Name) > 50 {
+		return fmt.Errorf("%w: name must have 1 to 50 characters", ErrInvalidLabel())
+	}
+	if !colorPattern.MatchString(l.Color) {
+		return fmt.Errorf("%w: color must be written as #rrggbb", ErrInvalidLabel())
+	}
+	l.Color = strings.ToLower(l.Color)
+	return nil
+}
+
+func (l *Label) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(l)
+}
+
+func (l *Label) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(l)
+}
+
+func (l *Labels) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(l)
+}
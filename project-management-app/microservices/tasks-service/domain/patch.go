@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// TaskPatch holds the fields a PATCH request changes. Fields missing from
+// the request are nil and keep their current value, a null due_date clears
+// the due date.
+type TaskPatch struct {
+	Id            string
+	Name          *string
+	Description   *string
+	Status        *Status
+	State         *string
+	DueDate       *time.Time
+	ClearDueDate  bool
+	Priority      *Priority
+	StoryPoints   *float64
+	EstimateHours *float64
+}
+
+// FromJSON reads a patch, noting which fields the request contains. The
+// status may be given by number or by name.
+func (p *TaskPatch) FromJSON(r io.Reader) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.NewDecoder(r).Decode(&fields); err != nil {
+		return err
+	}
+
+	for key, raw := range fields {
+		var err error
+		switch key {
+		case "id":
+			err = json.Unmarshal(raw, &p.Id)
+		case "name":
+			err = json.Unmarshal(raw, &p.Name)
+		case "description":
+			err = json.Unmarshal(raw, &p.Description)
+		case "status":
+			p.Status, err = decodeStatus(raw)
+		case "state":
+			err = json.Unmarshal(raw, &p.State)
+		case "due_date":
+			p.ClearDueDate = string(raw) == "null"
+			err = json.Unmarshal(raw, &p.DueDate)
+		case "priority":
+			err = json.Unmarshal(raw, &p.Priority)
+		case "story_points":
+			err = json.Unmarshal(raw, &p.StoryPoints)
+		case "estimate_hours":
+			err = json.Unmarshal(raw, &p.EstimateHours)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the values of the fields the patch sets.
+func (p *TaskPatch) Validate() error {
+	if p.Name != nil && strings.TrimSpace(*p.Name) == "" {
+		return fmt.Errorf("%w: name can't be empty", ErrInvalidInput())
+	}
+	if p.Priority != nil && !p.Priority.Valid() {
+		return fmt.Errorf("%w: invalid priority", ErrInvalidInput())
+	}
+	if (p.StoryPoints != nil && *p.StoryPoints < 0) || (p.EstimateHours != nil && *p.EstimateHours < 0) {
+		return fmt.Errorf("%w: estimates can't be negative", ErrInvalidInput())
+	}
+	return nil
+}
+
+// Apply copies the fields the patch sets to a task, except the status and
+// state which go through the workflow.
+func (p *TaskPatch) Apply(t *Task) {
+	if p.Name != nil {
+		t.Name = *p.Name
+	}
+	if p.Description != nil {
+		t.Description = *p.Description
+	}
+	if p.DueDate != nil || p.ClearDueDate {
+		t.DueDate = p.DueDate
+	}
+	if p.Priority != nil {
+		t.Priority = *p.Priority
+	}
+	if p.StoryPoints != nil {
+		t.StoryPoints = *p.StoryPoints
+	}
+	if p.EstimateHours != nil {
+		t.EstimateHours = *p.EstimateHours
+	}
+}
+
+func decodeStatus(raw json.RawMessage) (*Status, error) {
+	if string(raw) == "null" {
+		return nil, nil
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		status, err := StatusFromString(strings.ToUpper(name))
+		return &status, err
+	}
+	var status Status
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
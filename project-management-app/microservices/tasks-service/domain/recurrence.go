@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RECUR_DAILY   = "DAILY"
+	RECUR_WEEKLY  = "WEEKLY"
+	RECUR_MONTHLY = "MONTHLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Recurrence makes a task repeat. Every instance of a series carries the
+// rule; the latest one creates the next instance when it is finished or when
+// NextAt is reached, whichever comes first.
+type Recurrence struct {
+	Frequency      string     `bson:"frequency" json:"frequency"`
+	Interval       int        `bson:"interval" json:"interval"`
+	ByDay          []string   `bson:"by_day,omitempty" json:"by_day,omitempty"`
+	ByMonthDay     int        `bson:"by_month_day,omitempty" json:"by_month_day,omitempty"`
+	Count          int        `bson:"count,omitempty" json:"count,omitempty"`
+	Until          *time.Time `bson:"until,omitempty" json:"until,omitempty"`
+	CarryAssignees bool       `bson:"carry_assignees" json:"carry_assignees"`
+	Series         string     `bson:"series" json:"series"`
+	SeriesName     string     `bson:"series_name" json:"series_name"`
+	Start          time.Time  `bson:"start" json:"start"`
+	Occurrence     int        `bson:"occurrence" json:"occurrence"`
+	NextAt         *time.Time `bson:"next_at,omitempty" json:"next_at,omitempty"`
+	Spawned        bool       `bson:"spawned,omitempty" json:"spawned,omitempty"`
+}
+
+// ParseRRule reads a rule written like an iCalendar RRULE, for example
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10". FREQ, INTERVAL, BYDAY,
+// BYMONTHDAY, COUNT and UNTIL are supported.
+func ParseRRule(rule string) (*Recurrence, error) {
+	r := &Recurrence{Interval: 1}
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRecurrence(), part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Frequency = strings.ToUpper(value)
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+		case "BYDAY":
+			r.ByDay = strings.Split(strings.ToUpper(value), ",")
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = strconv.Atoi(value)
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+		case "UNTIL":
+			var until time.Time
+			if until, err = parseRRuleTime(value); err == nil {
+				r.Until = &until
+			}
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence(), err)
+		}
+	}
+	return r, r.Validate()
+}
+
+func parseRRuleTime(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	return time.Parse("20060102", value)
+}
+
+// RRule writes the rule back in RRULE form.
+func (r *Recurrence) RRule() string {
+	parts := []string{"FREQ=" + r.Frequency, "INTERVAL=" + strconv.Itoa(r.Interval)}
+	if len(r.ByDay) > 0 {
+		parts = append(parts, "BYDAY="+strings.Join(r.ByDay, ","))
+	}
+	if r.ByMonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+func (r *Recurrence) Validate() error {
+	switch r.Frequency {
+	case RECUR_DAILY, RECUR_WEEKLY, RECUR_MONTHLY:
+	default:
+		return fmt.Errorf("%w: FREQ must be DAILY, WEEKLY or MONTHLY", ErrInvalidRecurrence())
+	}
+	if r.Interval < 1 || r.Interval > 365 {
+		return fmt.Errorf("%w: INTERVAL must be between 1 and 365", ErrInvalidRecurrence())
+	}
+	for _, day := range r.ByDay {
+		if _, ok := weekdays[day]; !ok {
+			return fmt.Errorf("%w: unknown day %s", ErrInvalidRecurrence(), day)
+		}
+	}
+	if len(r.ByDay) > 0 && r.Frequency != RECUR_WEEKLY {
+		return fmt.Errorf("%w: BYDAY is only supported for WEEKLY rules", ErrInvalidRecurrence())
+	}
+	if r.ByMonthDay != 0 && (r.Frequency != RECUR_MONTHLY || r.ByMonthDay < 1 || r.ByMonthDay > 31) {
+		return fmt.Errorf("%w: BYMONTHDAY must be between 1 and 31 on MONTHLY rules", ErrInvalidRecurrence())
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("%w: COUNT can't be negative", ErrInvalidRecurrence())
+	}
+	return nil
+}
+
+// Next returns the first occurrence of the rule after the given one, keeping
+// its time of day. Monthly rules fall in every Interval-th month counted from
+// Start and, as in RRULE, skip months that don't have the day. A rule whose
+// day never falls in its months has no next occurrence and Next returns the
+// zero time.
+func (r *Recurrence) Next(after time.Time) time.Time {
+	switch r.Frequency {
+	case RECUR_WEEKLY:
+		if len(r.ByDay) == 0 {
+			return after.AddDate(0, 0, 7*r.Interval)
+		}
+		for day := after.AddDate(0, 0, 1); ; day = day.AddDate(0, 0, 1) {
+			if r.onDay(day.Weekday()) && weeksBetween(r.Start, day)%r.Interval == 0 {
+				return day
+			}
+		}
+	case RECUR_MONTHLY:
+		day := r.ByMonthDay
+		if day == 0 {
+			day = r.Start.Day()
+		}
+		n := 0
+		if months := (after.Year()-r.Start.Year())*12 + int(after.Month()-r.Start.Month()); months > 0 {
+			n = months / r.Interval
+		}
+		// The months of a rule come round within four years, or eight when
+		// one of them is a February of a century that isn't a leap year.
+		for tries := 0; tries < 96; tries, n = tries+1, n+1 {
+			first := time.Date(r.Start.Year(), r.Start.Month()+time.Month(n*r.Interval), 1, after.Hour(), after.Minute(), after.Second(), 0, after.Location())
+			next := first.AddDate(0, 0, day-1)
+			if next.Month() == first.Month() && next.After(after) {
+				return next
+			}
+		}
+		return time.Time{}
+	default:
+		return after.AddDate(0, 0, r.Interval)
+	}
+}
+
+// Ended reports whether an occurrence at the given time would be past the
+// end of the series. The zero time Next returns when there is no next
+// occurrence always is.
+func (r *Recurrence) Ended(occurrence int, at time.Time) bool {
+	return at.IsZero() || (r.Count > 0 && occurrence > r.Count) || (r.Until != nil && at.After(*r.Until))
+}
+
+func (r *Recurrence) onDay(weekday time.Weekday) bool {
+	for _, day := range r.ByDay {
+		if weekdays[day] == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// weeksBetween counts the weeks, starting on Monday, from a to b.
+func weeksBetween(a time.Time, b time.Time) int {
+	monday := func(t time.Time) time.Time {
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+	}
+	return int(monday(b).Sub(monday(a)).Hours()/24) / 7
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestParseRRule(t *testing.T) {
+	until := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+	untilNoon := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule string
+		want Recurrence
+	}{
+		{"FREQ=DAILY", Recurrence{Frequency: RECUR_DAILY, Interval: 1}},
+		{"RRULE:freq=daily;interval=3", Recurrence{Frequency: RECUR_DAILY, Interval: 3}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=mo,TH;COUNT=10", Recurrence{Frequency: RECUR_WEEKLY, Interval: 2, ByDay: []string{"MO", "TH"}, Count: 10}},
+		{"FREQ=MONTHLY;BYMONTHDAY=31;UNTIL=20241231", Recurrence{Frequency: RECUR_MONTHLY, Interval: 1, ByMonthDay: 31, Until: &until}},
+		{" FREQ=DAILY;UNTIL=20240101T120000Z; ", Recurrence{Frequency: RECUR_DAILY, Interval: 1, Until: &untilNoon}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRRule: %v", err)
+			}
+			if !reflect.DeepEqual(*r, tt.want) {
+				t.Fatalf("ParseRRule = %+v, want %+v", *r, tt.want)
+			}
+
+			// The rule written back reads as the same rule.
+			again, err := ParseRRule(r.RRule())
+			if err != nil || !reflect.DeepEqual(again, r) {
+				t.Fatalf("ParseRRule(%q) = %+v, %v, want %+v", r.RRule(), again, err, r)
+			}
+		})
+	}
+}
+
+func TestParseRRuleRejects(t *testing.T) {
+	tests := []string{
+		"",
+		"FREQ",
+		"FREQ=YEARLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=366",
+		"FREQ=DAILY;INTERVAL=two",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=-1",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;BYHOUR=9",
+	}
+
+	for _, rule := range tests {
+		t.Run(rule, func(t *testing.T) {
+			if r, err := ParseRRule(rule); !errors.Is(err, ErrInvalidRecurrence()) {
+				t.Fatalf("ParseRRule(%q) = %+v, %v, want ErrInvalidRecurrence", rule, r, err)
+			}
+		})
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		want  time.Time
+	}{
+		{"daily", "FREQ=DAILY", date(2024, 1, 10), date(2024, 1, 10), date(2024, 1, 11)},
+		{"every third day", "FREQ=DAILY;INTERVAL=3", date(2024, 1, 10), date(2024, 1, 10), date(2024, 1, 13)},
+		{"daily over the end of a year", "FREQ=DAILY", date(2024, 12, 31), date(2024, 12, 31), date(2025, 1, 1)},
+		{"weekly", "FREQ=WEEKLY", date(2024, 1, 10), date(2024, 1, 10), date(2024, 1, 17)},
+		{"every other week", "FREQ=WEEKLY;INTERVAL=2", date(2024, 1, 10), date(2024, 1, 10), date(2024, 1, 24)},
+		{"monday to thursday", "FREQ=WEEKLY;BYDAY=MO,TH", date(2024, 1, 8), date(2024, 1, 8), date(2024, 1, 11)},
+		{"thursday to next monday", "FREQ=WEEKLY;BYDAY=MO,TH", date(2024, 1, 8), date(2024, 1, 11), date(2024, 1, 15)},
+		{"mondays of every other week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", date(2024, 1, 8), date(2024, 1, 8), date(2024, 1, 22)},
+		{"friday skips the off week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", date(2024, 1, 8), date(2024, 1, 12), date(2024, 1, 22)},
+		{"sunday ends the week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU", date(2024, 1, 8), date(2024, 1, 8), date(2024, 1, 14)},
+		{"monthly on the start day", "FREQ=MONTHLY", date(2024, 1, 15), date(2024, 1, 15), date(2024, 2, 15)},
+		{"monthly on a given day", "FREQ=MONTHLY;BYMONTHDAY=5", date(2024, 1, 15), date(2024, 1, 15), date(2024, 2, 5)},
+		{"quarterly over the end of a year", "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=5", date(2024, 11, 5), date(2024, 11, 5), date(2025, 2, 5)},
+		{"later day of the start month", "FREQ=MONTHLY;BYMONTHDAY=20", date(2024, 1, 5), date(2024, 1, 5), date(2024, 1, 20)},
+		{"earlier day of the start month", "FREQ=MONTHLY;BYMONTHDAY=20", date(2024, 1, 25), date(2024, 1, 25), date(2024, 2, 20)},
+		{"interval counted from the start", "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=20", date(2024, 1, 5), date(2024, 1, 20), date(2024, 3, 20)},
+		{"month outside the interval", "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=20", date(2024, 1, 5), date(2024, 2, 25), date(2024, 3, 20)},
+		{"29th in a leap february", "FREQ=MONTHLY;BYMONTHDAY=29", date(2024, 1, 29), date(2024, 1, 29), date(2024, 2, 29)},
+		{"29th skips a short february", "FREQ=MONTHLY;BYMONTHDAY=29", date(2023, 1, 29), date(2023, 1, 29), date(2023, 3, 29)},
+		{"31st skips february", "FREQ=MONTHLY;BYMONTHDAY=31", date(2024, 1, 31), date(2024, 1, 31), date(2024, 3, 31)},
+		{"31st skips a 30 day month", "FREQ=MONTHLY;BYMONTHDAY=31", date(2024, 1, 31), date(2024, 3, 31), date(2024, 5, 31)},
+		{"start day skips february", "FREQ=MONTHLY", date(2024, 1, 30), date(2024, 1, 30), date(2024, 3, 30)},
+		{"start day kept after a skipped month", "FREQ=MONTHLY", date(2024, 1, 31), date(2024, 5, 31), date(2024, 7, 31)},
+		{"31st of april never comes", "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=31", date(2024, 4, 10), date(2024, 4, 10), time.Time{}},
+		{"yearly 29th of february over a century", "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=29", date(2096, 2, 29), date(2096, 2, 29), date(2104, 2, 29)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Start = tt.start
+			if got := r.Next(tt.after); !got.Equal(tt.want) {
+				t.Fatalf("Next(%s) = %s, want %s", tt.after.Format(time.DateTime), got.Format(time.DateTime), tt.want.Format(time.DateTime))
+			}
+		})
+	}
+}
+
+func TestRecurrenceEnded(t *testing.T) {
+	until := date(2024, 3, 1)
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		occurrence int
+		at         time.Time
+		ended      bool
+	}{
+		{"no end", Recurrence{}, 1000, date(2030, 1, 1), false},
+		{"last counted occurrence", Recurrence{Count: 3}, 3, date(2024, 1, 1), false},
+		{"past the count", Recurrence{Count: 3}, 4, date(2024, 1, 1), true},
+		{"on the until date", Recurrence{Until: &until}, 1, until, false},
+		{"after the until date", Recurrence{Until: &until}, 1, until.Add(time.Minute), true},
+		{"count reached before until", Recurrence{Count: 2, Until: &until}, 3, date(2024, 1, 1), true},
+		{"no next occurrence", Recurrence{}, 2, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.recurrence.Ended(tt.occurrence, tt.at); got != tt.ended {
+				t.Fatalf("Ended(%d, %s) = %v, want %v", tt.occurrence, tt.at.Format(time.DateTime), got, tt.ended)
+			}
+		})
+	}
+}
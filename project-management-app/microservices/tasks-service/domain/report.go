@@ -0,0 +1,321 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	EVENT_CREATED  = "created"
+	EVENT_CHANGED  = "changed"
+	EVENT_DELETED  = "deleted"
+	EVENT_RESTORED = "restored"
+
+	PERIOD_WEEK   = "week"
+	PERIOD_MONTH  = "month"
+	PERIOD_SPRINT = "sprint"
+)
+
+// StatusEvent is an entry of the status change log. It records the state of
+// a task from At on, so the state of every task at any moment can be
+// rebuilt from the log. Deleted tasks stop counting until they are restored.
+type StatusEvent struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Task        string             `bson:"task" json:"task"`
+	Project     string             `bson:"project" json:"project"`
+	Kind        string             `bson:"kind" json:"kind"`
+	FromStatus  Status             `bson:"from_status,omitempty" json:"from_status,omitempty"`
+	FromState   string             `bson:"from_state,omitempty" json:"from_state,omitempty"`
+	Status      Status             `bson:"status" json:"status"`
+	State       string             `bson:"state" json:"state"`
+	StoryPoints float64            `bson:"story_points" json:"story_points"`
+	Actor       string             `bson:"actor" json:"actor"`
+	At          time.Time          `bson:"at" json:"at"`
+}
+
+type StatusEvents []*StatusEvent
+
+// Snapshot counts the tasks of a project at the end of a day, by status
+// category and by workflow state.
+type Snapshot struct {
+	Date           time.Time      `json:"date"`
+	Open           int            `json:"open"`
+	Finished       int            `json:"finished"`
+	OpenPoints     float64        `json:"open_points"`
+	FinishedPoints float64        `json:"finished_points"`
+	States         map[string]int `json:"states"`
+}
+
+// Snapshots is the cumulative flow of a project, one snapshot per day.
+// States lists the workflow states in workflow order.
+type Snapshots struct {
+	Project string      `json:"project"`
+	From    time.Time   `json:"from"`
+	To      time.Time   `json:"to"`
+	States  []string    `json:"states"`
+	Days    []*Snapshot `json:"days"`
+}
+
+type BurndownPoint struct {
+	Date            time.Time `json:"date"`
+	Remaining       int       `json:"remaining"`
+	RemainingPoints float64   `json:"remaining_points"`
+	Completed       int       `json:"completed"`
+	Ideal           float64   `json:"ideal"`
+}
+
+// Burndown shows the work left each day of a period. The ideal line burns
+// the work open on the first day down to zero evenly, in story points, or in
+// tasks when nothing is estimated.
+type Burndown struct {
+	Project string           `json:"project"`
+	Sprint  string           `json:"sprint,omitempty"`
+	From    time.Time        `json:"from"`
+	To      time.Time        `json:"to"`
+	Unit    string           `json:"unit"`
+	Points  []*BurndownPoint `json:"points"`
+}
+
+type VelocityPeriod struct {
+	Name        string    `json:"name"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Tasks       int       `json:"tasks"`
+	StoryPoints float64   `json:"story_points"`
+}
+
+// Velocity is the work completed in past periods, which are sprints, weeks
+// or months.
+type Velocity struct {
+	Project       string            `json:"project"`
+	By            string            `json:"by"`
+	Periods       []*VelocityPeriod `json:"periods"`
+	AverageTasks  float64           `json:"average_tasks"`
+	AveragePoints float64           `json:"average_points"`
+}
+
+// TaskTimeline is the status log of one task, oldest event first.
+type TaskTimeline StatusEvents
+
+// At returns the event in effect at a moment, nil when the task didn't exist
+// or was deleted.
+func (t TaskTimeline) At(moment time.Time) *StatusEvent {
+	var current *StatusEvent
+	for _, event := range t {
+		if !event.At.Before(moment) {
+			break
+		}
+		current = event
+	}
+	if current == nil || current.Kind == EVENT_DELETED {
+		return nil
+	}
+	return current
+}
+
+// Timelines groups the status log of a project by task. Tasks created
+// before the log existed get their creation, and their finish when they have
+// no logged changes, from the task itself.
+func Timelines(tasks Tasks, events StatusEvents) map[string]TaskTimeline {
+	timelines := map[string]TaskTimeline{}
+	for _, event := range events {
+		timelines[event.Task] = append(timelines[event.Task], event)
+	}
+	for _, timeline := range timelines {
+		sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].At.Before(timeline[j].At) })
+	}
+
+	for _, task := range tasks {
+		id := task.Id.Hex()
+		logged := timelines[id]
+		if len(logged) > 0 && logged[0].Kind == EVENT_CREATED {
+			continue
+		}
+
+		created := &StatusEvent{Task: id, Project: task.Project, Kind: EVENT_CREATED, StoryPoints: task.StoryPoints, At: task.CreatedAt}
+		switch {
+		case len(logged) > 0:
+			created.Status, created.State = logged[0].FromStatus, logged[0].FromState
+			timelines[id] = append(TaskTimeline{created}, logged...)
+		case task.Status == FINISHED && task.FinishedAt != nil:
+			created.Status, created.State = PENDING, PENDING.String()
+			finished := &StatusEvent{Task: id, Project: task.Project, Kind: EVENT_CHANGED, Status: task.Status, State: task.CurrentState(), StoryPoints: task.StoryPoints, At: *task.FinishedAt}
+			timelines[id] = TaskTimeline{created, finished}
+		default:
+			created.Status, created.State = task.Status, task.CurrentState()
+			timelines[id] = TaskTimeline{created}
+		}
+	}
+	return timelines
+}
+
+// BuildSnapshots counts the tasks at the end of every day from from to to.
+func BuildSnapshots(timelines map[string]TaskTimeline, from time.Time, to time.Time) []*Snapshot {
+	days := []*Snapshot{}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		snapshot := &Snapshot{Date: day, States: map[string]int{}}
+		end := day.AddDate(0, 0, 1)
+		for _, timeline := range timelines {
+			event := timeline.At(end)
+			if event == nil {
+				continue
+			}
+			snapshot.States[event.State]++
+			if event.Status == FINISHED {
+				snapshot.Finished++
+				snapshot.FinishedPoints += event.StoryPoints
+			} else {
+				snapshot.Open++
+				snapshot.OpenPoints += event.StoryPoints
+			}
+		}
+		days = append(days, snapshot)
+	}
+	return days
+}
+
+// NewBurndown turns daily snapshots into a burndown.
+func NewBurndown(projectId string, from time.Time, to time.Time, days []*Snapshot) *Burndown {
+	burndown := &Burndown{Project: projectId, From: from, To: to, Unit: "story_points", Points: []*BurndownPoint{}}
+	if len(days) == 0 {
+		return burndown
+	}
+
+	start := days[0].OpenPoints
+	if start == 0 {
+		burndown.Unit = "tasks"
+		start = float64(days[0].Open)
+	}
+	for i, day := range days {
+		ideal := start
+		if len(days) > 1 {
+			ideal = start * float64(len(days)-1-i) / float64(len(days)-1)
+		}
+		burndown.Points = append(burndown.Points, &BurndownPoint{
+			Date:            day.Date,
+			Remaining:       day.Open,
+			RemainingPoints: day.OpenPoints,
+			Completed:       day.Finished,
+			Ideal:           math.Round(ideal*100) / 100,
+		})
+	}
+	return burndown
+}
+
+// Completed adds up the tasks that weren't finished at the start of a period
+// and were finished at its end.
+func (p *VelocityPeriod) Completed(timelines map[string]TaskTimeline) {
+	for _, timeline := range timelines {
+		before, after := timeline.At(p.Start), timeline.At(p.End)
+		if after == nil || after.Status != FINISHED || (before != nil && before.Status == FINISHED) {
+			continue
+		}
+		p.Tasks++
+		p.StoryPoints += after.StoryPoints
+	}
+}
+
+// Average fills in the average velocity of the periods.
+func (v *Velocity) Average() {
+	if len(v.Periods) == 0 {
+		return
+	}
+	tasks, points := 0, 0.0
+	for _, period := range v.Periods {
+		tasks += period.Tasks
+		points += period.StoryPoints
+	}
+	v.AverageTasks = math.Round(float64(tasks)/float64(len(v.Periods))*100) / 100
+	v.AveragePoints = math.Round(points/float64(len(v.Periods))*100) / 100
+}
+
+func (s *Snapshots) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(s)
+}
+
+// ToCSV writes one row per day with a column per workflow state.
+func (s *Snapshots) ToCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"date", "open", "finished", "open_points", "finished_points"}
+	if err := writer.Write(append(header, s.States...)); err != nil {
+		return err
+	}
+	for _, day := range s.Days {
+		record := []string{
+			day.Date.Format("2006-01-02"),
+			strconv.Itoa(day.Open),
+			strconv.Itoa(day.Finished),
+			fmt.Sprint(day.OpenPoints),
+			fmt.Sprint(day.FinishedPoints),
+		}
+		for _, state := range s.States {
+			record = append(record, strconv.Itoa(day.States[state]))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (b *Burndown) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(b)
+}
+
+func (b *Burndown) ToCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "remaining", "remaining_points", "completed", "ideal"}); err != nil {
+		return err
+	}
+	for _, point := range b.Points {
+		record := []string{
+			point.Date.Format("2006-01-02"),
+			strconv.Itoa(point.Remaining),
+			fmt.Sprint(point.RemainingPoints),
+			strconv.Itoa(point.Completed),
+			fmt.Sprint(point.Ideal),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (v *Velocity) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(v)
+}
+
+func (v *Velocity) ToCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"period", "start", "end", "tasks", "story_points"}); err != nil {
+		return err
+	}
+	for _, period := range v.Periods {
+		record := []string{
+			period.Name,
+			period.Start.Format("2006-01-02"),
+			period.End.Format("2006-01-02"),
+			strconv.Itoa(period.Tasks),
+			fmt.Sprint(period.StoryPoints),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
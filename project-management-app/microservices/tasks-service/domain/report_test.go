@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// march returns a moment in March 2024. The 4th is a Monday.
+func march(day int, hour int) time.Time {
+	return time.Date(2024, time.March, day, hour, 0, 0, 0, time.UTC)
+}
+
+// reportProject is a small project whose week of work, Monday 4th to Friday
+// 8th of March, the report tests look at:
+//
+//   - a (3 points) is finished on Tuesday, the log started after a was created
+//   - b (2 points) is deleted on Thursday
+//   - c (5 points) is added on Wednesday and finished on Friday
+//   - d is opened on Monday and never estimated nor finished
+//   - e (1 point) was finished before the log existed
+//   - f is finished on Tuesday and reopened on Wednesday
+func reportProject() (Tasks, StatusEvents) {
+	task := func(name string, points float64, created time.Time) *Task {
+		return &Task{Id: primitive.NewObjectID(), Project: "p1", Name: name, Status: PENDING, State: "To Do", StoryPoints: points, CreatedAt: created}
+	}
+	a := task("a", 3, march(1, 9))
+	b := task("b", 2, march(1, 9))
+	c := task("c", 5, march(6, 9))
+	d := task("d", 0, march(4, 8))
+	e := task("e", 1, time.Date(2024, time.February, 20, 9, 0, 0, 0, time.UTC))
+	f := task("f", 0, march(1, 9))
+	finishedAt := time.Date(2024, time.March, 2, 15, 0, 0, 0, time.UTC)
+	e.Status, e.State, e.FinishedAt = FINISHED, "Done", &finishedAt
+
+	event := func(task *Task, kind string, status Status, state string, at time.Time) *StatusEvent {
+		return &StatusEvent{Task: task.Id.Hex(), Project: "p1", Kind: kind, Status: status, State: state, StoryPoints: task.StoryPoints, At: at}
+	}
+	finishA := event(a, EVENT_CHANGED, FINISHED, "Done", march(5, 10))
+	finishA.FromStatus, finishA.FromState = PENDING, "To Do"
+
+	// The log isn't in order, Timelines sorts it.
+	events := StatusEvents{
+		event(c, EVENT_CHANGED, FINISHED, "Done", march(8, 17)),
+		finishA,
+		event(b, EVENT_CREATED, PENDING, "To Do", march(1, 9)),
+		event(b, EVENT_DELETED, PENDING, "To Do", march(7, 12)),
+		event(c, EVENT_CREATED, PENDING, "To Do", march(6, 9)),
+		event(c, EVENT_CHANGED, IN_PROGRESS, "Doing", march(7, 9)),
+		event(d, EVENT_CREATED, PENDING, "To Do", march(4, 8)),
+		event(f, EVENT_CREATED, PENDING, "To Do", march(1, 9)),
+		event(f, EVENT_CHANGED, FINISHED, "Done", march(5, 11)),
+		event(f, EVENT_CHANGED, IN_PROGRESS, "Doing", march(6, 11)),
+	}
+	return Tasks{a, b, c, d, e, f}, events
+}
+
+func TestTaskTimelineAt(t *testing.T) {
+	created := &StatusEvent{Kind: EVENT_CREATED, Status: PENDING, At: march(4, 9)}
+	deleted := &StatusEvent{Kind: EVENT_DELETED, Status: PENDING, At: march(5, 9)}
+	restored := &StatusEvent{Kind: EVENT_RESTORED, Status: PENDING, At: march(6, 9)}
+	timeline := TaskTimeline{created, deleted, restored}
+
+	if got := timeline.At(march(4, 9)); got != nil {
+		t.Errorf("At the moment of creation = %+v, want nil, the event only counts after it", got)
+	}
+	if got := timeline.At(march(4, 10)); got != created {
+		t.Errorf("At after creation = %+v, want the created event", got)
+	}
+	if got := timeline.At(march(5, 10)); got != nil {
+		t.Errorf("At after deletion = %+v, want nil", got)
+	}
+	if got := timeline.At(march(30, 0)); got != restored {
+		t.Errorf("At after restoring = %+v, want the restored event", got)
+	}
+}
+
+func TestTimelinesFillInTasksCreatedBeforeTheLog(t *testing.T) {
+	tasks, events := reportProject()
+	timelines := Timelines(tasks, events)
+
+	a := timelines[tasks[0].Id.Hex()]
+	if len(a) != 2 || a[0].Kind != EVENT_CREATED || a[0].Status != PENDING || a[0].State != "To Do" || !a[0].At.Equal(tasks[0].CreatedAt) {
+		t.Errorf("a starts with %+v, want a creation in its state before the first logged change", a[0])
+	}
+
+	e := timelines[tasks[4].Id.Hex()]
+	if len(e) != 2 || e[0].Status != PENDING || e[1].Status != FINISHED || !e[1].At.Equal(*tasks[4].FinishedAt) {
+		t.Errorf("e has %d events, want a creation and a finish at its finish date", len(e))
+	}
+
+	c := timelines[tasks[2].Id.Hex()]
+	for i := 1; i < len(c); i++ {
+		if c[i].At.Before(c[i-1].At) {
+			t.Fatalf("timeline of c is out of order at %d", i)
+		}
+	}
+}
+
+func TestBurndown(t *testing.T) {
+	tasks, events := reportProject()
+	days := BuildSnapshots(Timelines(tasks, events), march(4, 0), march(9, 0))
+
+	want := []struct {
+		open, finished             int
+		openPoints, finishedPoints float64
+		ideal                      float64
+	}{
+		{4, 1, 5, 1, 5},
+		{2, 3, 2, 4, 3.75},
+		{4, 2, 7, 4, 2.5},
+		{3, 2, 5, 4, 1.25},
+		{2, 3, 0, 9, 0},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i, w := range want {
+		day := days[i]
+		if day.Open != w.open || day.Finished != w.finished || day.OpenPoints != w.openPoints || day.FinishedPoints != w.finishedPoints {
+			t.Errorf("%s: open %d (%.0f points), finished %d (%.0f points), want %d (%.0f), %d (%.0f)",
+				day.Date.Format(time.DateOnly), day.Open, day.OpenPoints, day.Finished, day.FinishedPoints,
+				w.open, w.openPoints, w.finished, w.finishedPoints)
+		}
+	}
+	if thursday := days[3].States; thursday["To Do"] != 1 || thursday["Doing"] != 2 || thursday["Done"] != 2 {
+		t.Errorf("states on Thursday = %v, want 1 To Do, 2 Doing and 2 Done", thursday)
+	}
+
+	burndown := NewBurndown("p1", march(4, 0), march(9, 0), days)
+	if burndown.Unit != "story_points" {
+		t.Errorf("unit = %s, want story_points", burndown.Unit)
+	}
+	for i, point := range burndown.Points {
+		if point.Ideal != want[i].ideal || point.Remaining != want[i].open || point.RemainingPoints != want[i].openPoints {
+			t.Errorf("%s: remaining %d (%.0f points), ideal %.2f, want %d (%.0f), %.2f",
+				point.Date.Format(time.DateOnly), point.Remaining, point.RemainingPoints, point.Ideal,
+				want[i].open, want[i].openPoints, want[i].ideal)
+		}
+	}
+}
+
+func TestBurndownCountsTasksWithoutEstimates(t *testing.T) {
+	days := []*Snapshot{
+		{Date: march(4, 0), Open: 3},
+		{Date: march(5, 0), Open: 2, Finished: 1},
+		{Date: march(6, 0), Open: 2, Finished: 1},
+		{Date: march(7, 0), Open: 0, Finished: 3},
+	}
+	burndown := NewBurndown("p1", march(4, 0), march(8, 0), days)
+
+	if burndown.Unit != "tasks" {
+		t.Fatalf("unit = %s, want tasks", burndown.Unit)
+	}
+	for i, ideal := range []float64{3, 2, 1, 0} {
+		if burndown.Points[i].Ideal != ideal {
+			t.Errorf("ideal on day %d = %.2f, want %.2f", i, burndown.Points[i].Ideal, ideal)
+		}
+	}
+
+	if empty := NewBurndown("p1", march(4, 0), march(4, 0), nil); len(empty.Points) != 0 {
+		t.Errorf("burndown of no days has %d points", len(empty.Points))
+	}
+	if single := NewBurndown("p1", march(4, 0), march(5, 0), days[:1]); single.Points[0].Ideal != 3 {
+		t.Errorf("ideal of a single day = %.2f, want all the work", single.Points[0].Ideal)
+	}
+}
+
+func TestVelocity(t *testing.T) {
+	tasks, events := reportProject()
+	timelines := Timelines(tasks, events)
+
+	velocity := &Velocity{Project: "p1", By: PERIOD_WEEK, Periods: []*VelocityPeriod{
+		{Name: "2024-W09", Start: time.Date(2024, time.February, 26, 0, 0, 0, 0, time.UTC), End: march(4, 0)},
+		{Name: "2024-W10", Start: march(4, 0), End: march(11, 0)},
+		{Name: "2024-W11", Start: march(11, 0), End: march(18, 0)},
+	}}
+	for _, period := range velocity.Periods {
+		period.Completed(timelines)
+	}
+	velocity.Average()
+
+	// e was finished in the first week. In the second a and c were, f was
+	// reopened and b deleted, so neither of them counts.
+	got := []VelocityPeriod{*velocity.Periods[0], *velocity.Periods[1], *velocity.Periods[2]}
+	if got[0].Tasks != 1 || got[0].StoryPoints != 1 {
+		t.Errorf("first week completed %d tasks, %.0f points, want 1 and 1", got[0].Tasks, got[0].StoryPoints)
+	}
+	if got[1].Tasks != 2 || got[1].StoryPoints != 8 {
+		t.Errorf("second week completed %d tasks, %.0f points, want 2 and 8", got[1].Tasks, got[1].StoryPoints)
+	}
+	if got[2].Tasks != 0 || got[2].StoryPoints != 0 {
+		t.Errorf("a week without work completed %d tasks, %.0f points", got[2].Tasks, got[2].StoryPoints)
+	}
+	if velocity.AverageTasks != 1 || velocity.AveragePoints != 3 {
+		t.Errorf("average %.2f tasks, %.2f points, want 1 and 3", velocity.AverageTasks, velocity.AveragePoints)
+	}
+
+	uneven := &Velocity{Periods: []*VelocityPeriod{{Tasks: 1, StoryPoints: 2}, {Tasks: 0}, {Tasks: 1, StoryPoints: 0.5}}}
+	uneven.Average()
+	if uneven.AverageTasks != 0.67 || uneven.AveragePoints != 0.83 {
+		t.Errorf("average %.4f tasks, %.4f points, want them rounded to 0.67 and 0.83", uneven.AverageTasks, uneven.AveragePoints)
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldChange is the old and new value of one field of a task.
+type FieldChange struct {
+	Field string      `bson:"field" json:"field"`
+	Old   interface{} `bson:"old" json:"old"`
+	New   interface{} `bson:"new" json:"new"`
+}
+
+// Revision records a change of a task. Version is the version of the task
+// after the change, so the values of a task at a version can be rebuilt by
+// undoing all later revisions.
+type Revision struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Task       string             `bson:"task" json:"task"`
+	Project    string             `bson:"project" json:"project"`
+	Version    int64              `bson:"version" json:"version"`
+	Actor      string             `bson:"actor" json:"actor"`
+	Changes    []FieldChange      `bson:"changes" json:"changes"`
+	RevertedTo *int64             `bson:"reverted_to,omitempty" json:"reverted_to,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type Revisions []*Revision
+
+type RevisionPage struct {
+	Items Revisions `json:"items"`
+	Total int64     `json:"total"`
+	Page  int       `json:"page"`
+	Size  int       `json:"size"`
+}
+
+func (p *RevisionPage) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(p)
+}
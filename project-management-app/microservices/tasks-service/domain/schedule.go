@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type Priority string
+
+const (
+	PRIORITY_LOW      Priority = "LOW"
+	PRIORITY_MEDIUM   Priority = "MEDIUM"
+	PRIORITY_HIGH     Priority = "HIGH"
+	PRIORITY_CRITICAL Priority = "CRITICAL"
+)
+
+func (p Priority) Valid() bool {
+	switch p {
+	case PRIORITY_LOW, PRIORITY_MEDIUM, PRIORITY_HIGH, PRIORITY_CRITICAL:
+		return true
+	default:
+		return false
+	}
+}
+
+// Schedule holds the planning data of a task as it is sent by clients.
+type Schedule struct {
+	DueDate       *time.Time `json:"due_date"`
+	Priority      Priority   `json:"priority"`
+	StoryPoints   float64    `json:"story_points"`
+	EstimateHours float64    `json:"estimate_hours"`
+}
+
+// Validate checks the schedule. An empty priority is allowed and means the
+// priority is left unchanged, or MEDIUM for new tasks.
+func (s Schedule) Validate() error {
+	if s.Priority != "" && !s.Priority.Valid() {
+		return fmt.Errorf("%w: invalid priority", ErrInvalidInput())
+	}
+	if s.StoryPoints < 0 || s.EstimateHours < 0 {
+		return fmt.Errorf("%w: estimates can't be negative", ErrInvalidInput())
+	}
+	return nil
+}
+
+// ApplySchedule copies the planning data of a schedule to the task.
+func (t *Task) ApplySchedule(s Schedule) {
+	t.DueDate = s.DueDate
+	if s.Priority != "" {
+		t.Priority = s.Priority
+	} else if t.Priority == "" {
+		t.Priority = PRIORITY_MEDIUM
+	}
+	t.StoryPoints = s.StoryPoints
+	t.EstimateHours = s.EstimateHours
+}
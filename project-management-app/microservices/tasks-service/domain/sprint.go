@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	SPRINT_PLANNED = "PLANNED"
+	SPRINT_ACTIVE  = "ACTIVE"
+	SPRINT_CLOSED  = "CLOSED"
+
+	// SPRINT_BACKLOG is where the unfinished tasks of a closed sprint go when
+	// they aren't moved to another sprint.
+	SPRINT_BACKLOG = "backlog"
+	SPRINT_NEXT    = "next"
+)
+
+// Sprint is a time-boxed iteration of a project. Tasks refer to their sprint
+// by id, tasks without a sprint are in the project's backlog.
+type Sprint struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Project   string             `bson:"project" json:"project"`
+	Name      string             `bson:"name" json:"name"`
+	Goal      string             `bson:"goal,omitempty" json:"goal,omitempty"`
+	Start     time.Time          `bson:"start" json:"start"`
+	End       time.Time          `bson:"end" json:"end"`
+	State     string             `bson:"state" json:"state"`
+	Committed []string           `bson:"committed,omitempty" json:"committed,omitempty"`
+	Planned   SprintWork         `bson:"planned" json:"planned"`
+	Report    *SprintReport      `bson:"report,omitempty" json:"report,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	ClosedAt  *time.Time         `bson:"closed_at,omitempty" json:"closed_at,omitempty"`
+}
+
+type Sprints []*Sprint
+
+// SprintWork sums up a set of tasks.
+type SprintWork struct {
+	Tasks         int     `bson:"tasks" json:"tasks"`
+	StoryPoints   float64 `bson:"story_points" json:"story_points"`
+	EstimateHours float64 `bson:"estimate_hours" json:"estimate_hours"`
+}
+
+// SprintReport compares the work planned when a sprint started with the work
+// completed in it. Planned is what was committed at the start, Added what
+// was put into the sprint afterwards. The report of a closed sprint is kept
+// as it was when the sprint closed.
+type SprintReport struct {
+	Sprint     string     `bson:"sprint" json:"sprint"`
+	Name       string     `bson:"name" json:"name"`
+	State      string     `bson:"state" json:"state"`
+	Planned    SprintWork `bson:"planned" json:"planned"`
+	Added      SprintWork `bson:"added" json:"added"`
+	Completed  SprintWork `bson:"completed" json:"completed"`
+	Remaining  SprintWork `bson:"remaining" json:"remaining"`
+	Completion float64    `bson:"completion" json:"completion"`
+	CarriedTo  string     `bson:"carried_to,omitempty" json:"carried_to,omitempty"`
+}
+
+// Add counts a task into the work.
+func (w *SprintWork) Add(task *Task) {
+	w.Tasks++
+	w.StoryPoints += task.StoryPoints
+	w.EstimateHours += task.EstimateHours
+}
+
+// SprintWorkOf sums up tasks.
+func SprintWorkOf(tasks Tasks) SprintWork {
+	work := SprintWork{}
+	for _, task := range tasks {
+		work.Add(task)
+	}
+	return work
+}
+
+// NewSprintReport reports on the tasks of a sprint. Completion is the
+// percentage of story points done, or of tasks when nothing is estimated.
+func NewSprintReport(sprint *Sprint, tasks Tasks) *SprintReport {
+	committed := map[string]bool{}
+	for _, id := range sprint.Committed {
+		committed[id] = true
+	}
+
+	report := &SprintReport{Sprint: sprint.Id.Hex(), Name: sprint.Name, State: sprint.State, Planned: sprint.Planned}
+	for _, task := range tasks {
+		if sprint.State != SPRINT_PLANNED && !committed[task.Id.Hex()] {
+			report.Added.Add(task)
+		}
+		if task.Status == FINISHED {
+			report.Completed.Add(task)
+		} else {
+			report.Remaining.Add(task)
+		}
+	}
+	if sprint.State == SPRINT_PLANNED {
+		report.Planned = SprintWorkOf(tasks)
+	}
+
+	done, total := report.Completed.StoryPoints, report.Completed.StoryPoints+report.Remaining.StoryPoints
+	if total == 0 {
+		done, total = float64(report.Completed.Tasks), float64(report.Completed.Tasks+report.Remaining.Tasks)
+	}
+	if total > 0 {
+		report.Completion = math.Round(done/total*10000) / 100
+	}
+	return report
+}
+
+// Validate checks the name and the dates of a sprint.
+func (s *Sprint) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" || len(s.Name) > 100 {
+		return fmt.Errorf("%w: name must have 1 to 100 characters", ErrInvalidSprint())
+	}
+	if len(s.Goal) > 1000 {
+		return fmt.Errorf("%w: goal can have at most 1000 characters", ErrInvalidSprint())
+	}
+	if s.Start.IsZero() || s.End.IsZero() || !s.Start.Before(s.End) {
+		return fmt.Errorf("%w: start must be before end", ErrInvalidSprint())
+	}
+	return nil
+}
+
+func (s *Sprint) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(s)
+}
+
+func (s *Sprint) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(s)
+}
+
+func (s *Sprints) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(s)
+}
+
+func (r *SprintReport) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(r)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// TaskStats summarizes the tasks of a single project.
+type TaskStats struct {
+	Project  string           `json:"project"`
+	Total    int              `json:"total"`
+	ByStatus map[string]int   `json:"by_status"`
+	Finished int              `json:"finished"`
+	Overdue  int              `json:"overdue"`
+	Workload []MemberWorkload `json:"workload"`
+	Trend    []TrendPoint     `json:"trend"`
+}
+
+type MemberWorkload struct {
+	Username string `bson:"_id" json:"username"`
+	Total    int    `bson:"total" json:"total"`
+	Open     int    `bson:"open" json:"open"`
+	Overdue  int    `bson:"overdue" json:"overdue"`
+}
+
+// TrendPoint holds the number of tasks created and finished on one day.
+type TrendPoint struct {
+	Date     string `json:"date"`
+	Created  int    `json:"created"`
+	Finished int    `json:"finished"`
+}
+
+func (s *TaskStats) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(s)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChecklistItem is a lightweight step inside a task. Items keep the order in
+// which they are stored.
+type ChecklistItem struct {
+	Id        primitive.ObjectID `bson:"id" json:"id"`
+	Text      string             `bson:"text" json:"text"`
+	Done      bool               `bson:"done" json:"done"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Progress rolls up the completion of a task from its subtasks and checklist.
+type Progress struct {
+	Subtasks         int     `json:"subtasks"`
+	SubtasksFinished int     `json:"subtasks_finished"`
+	ChecklistItems   int     `json:"checklist_items"`
+	ChecklistDone    int     `json:"checklist_done"`
+	Completion       float64 `json:"completion"`
+}
+
+// TaskTree is a task with its subtasks, recursively.
+type TaskTree struct {
+	Task     *Task       `json:"task"`
+	Progress Progress    `json:"progress"`
+	Subtasks []*TaskTree `json:"subtasks"`
+}
+
+func (t *TaskTree) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(t)
+}
@@ -4,17 +4,48 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"strconv"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Task struct {
-	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Project     string             `bson:"project" json:"project"`
-	Name        string             `bson:"name" json:"name"`
-	Description string             `bson:"description" json:"description"`
-	Status      Status             `bson:"status" json:"status"`
-	Members     Users              `bson:"members,omitempty" json:"members"`
+	Id            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Version       int64              `bson:"version" json:"version"`
+	Project       string             `bson:"project" json:"project"`
+	Number        int64              `bson:"number,omitempty" json:"number,omitempty"`
+	Key           string             `bson:"key,omitempty" json:"key,omitempty"`
+	Parent        string             `bson:"parent,omitempty" json:"parent,omitempty"`
+	Name          string             `bson:"name" json:"name"`
+	Description   string             `bson:"description" json:"description"`
+	Status        Status             `bson:"status" json:"status"`
+	State         string             `bson:"state,omitempty" json:"state,omitempty"`
+	Rank          string             `bson:"rank,omitempty" json:"rank,omitempty"`
+	Sprint        string             `bson:"sprint,omitempty" json:"sprint,omitempty"`
+	BlockedBy     []string           `bson:"blocked_by,omitempty" json:"blocked_by,omitempty"`
+	Checklist     []ChecklistItem    `bson:"checklist,omitempty" json:"checklist,omitempty"`
+	Labels        []string           `bson:"labels,omitempty" json:"labels,omitempty"`
+	Members       Users              `bson:"members,omitempty" json:"members"`
+	DueDate       *time.Time         `bson:"due_date,omitempty" json:"due_date,omitempty"`
+	Priority      Priority           `bson:"priority,omitempty" json:"priority,omitempty"`
+	StoryPoints   float64            `bson:"story_points,omitempty" json:"story_points,omitempty"`
+	EstimateHours float64            `bson:"estimate_hours,omitempty" json:"estimate_hours,omitempty"`
+	Recurrence    *Recurrence        `bson:"recurrence,omitempty" json:"recurrence,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	FinishedAt    *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+	DeletedAt     *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy     string             `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+	DeletedWith   string             `bson:"deleted_with,omitempty" json:"deleted_with,omitempty"`
+}
+
+// CurrentState returns the workflow state of the task. Tasks created before
+// workflows existed are in the state named after their status.
+func (t *Task) CurrentState() string {
+	if t.State != "" {
+		return t.State
+	}
+	return t.Status.String()
 }
 
 type User struct {
@@ -82,7 +113,12 @@ const (
 	FINISHED
 )
 
+// String names the status. Values outside the known statuses, like the zero
+// value of unset fields, are written as numbers.
 func (r Status) String() string {
+	if r < PENDING || r > FINISHED {
+		return strconv.Itoa(int(r))
+	}
 	return [...]string{"PENDING", "IN_PROGRESS", "FINISHED"}[r-1]
 }
 func (r Status) EnumIndex() int {
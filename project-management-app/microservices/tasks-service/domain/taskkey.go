@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DEFAULT_KEY_PREFIX is used for task keys until a manager picks a prefix
+// for the project.
+const DEFAULT_KEY_PREFIX = "TASK"
+
+var keyPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// KeySequence hands out task numbers of a project. Last is the number of the
+// most recently created task.
+type KeySequence struct {
+	Project string `bson:"_id" json:"project"`
+	Prefix  string `bson:"prefix" json:"prefix"`
+	Last    int64  `bson:"last" json:"last"`
+}
+
+// TaskKey formats the human-friendly key of a task, like PRJ-42.
+func TaskKey(prefix string, number int64) string {
+	return prefix + "-" + strconv.FormatInt(number, 10)
+}
+
+// ParseTaskKey splits a task key into its upper-cased prefix and number.
+func ParseTaskKey(key string) (string, int64, error) {
+	i := strings.LastIndex(key, "-")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("%w: %q is not a task key", ErrInvalidTaskKey(), key)
+	}
+	number, err := strconv.ParseInt(key[i+1:], 10, 64)
+	if err != nil || number < 1 {
+		return "", 0, fmt.Errorf("%w: %q is not a task key", ErrInvalidTaskKey(), key)
+	}
+	return strings.ToUpper(key[:i]), number, nil
+}
+
+// NormalizeKeyPrefix upper-cases a prefix and checks it has 2 to 10 letters
+// or digits, starting with a letter.
+func NormalizeKeyPrefix(prefix string) (string, error) {
+	prefix = strings.ToUpper(strings.TrimSpace(prefix))
+	if !keyPrefixPattern.MatchString(prefix) {
+		return "", fmt.Errorf("%w: prefix must have 2 to 10 letters or digits and start with a letter", ErrInvalidTaskKey())
+	}
+	return prefix, nil
+}
+
+func (k *KeySequence) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(k)
+}
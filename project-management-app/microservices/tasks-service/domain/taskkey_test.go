@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTaskKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		prefix  string
+		number  int64
+		invalid bool
+	}{
+		{"PRJ-42", "PRJ", 42, false},
+		{"TASK-1", "TASK", 1, false},
+		{"prj-7", "PRJ", 7, false},
+		{"OLD-PREFIX-12", "OLD-PREFIX", 12, false},
+		{"PRJ-9223372036854775807", "PRJ", 9223372036854775807, false},
+		{"", "", 0, true},
+		{"PRJ", "", 0, true},
+		{"PRJ-", "", 0, true},
+		{"-42", "", 0, true},
+		{"42", "", 0, true},
+		{"PRJ-0", "", 0, true},
+		{"PRJ-4x", "", 0, true},
+		{"PRJ-1.5", "", 0, true},
+		{"PRJ-9223372036854775808", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			prefix, number, err := ParseTaskKey(tt.key)
+			if tt.invalid {
+				if !errors.Is(err, ErrInvalidTaskKey()) {
+					t.Fatalf("ParseTaskKey(%q) = %q, %d, %v, want ErrInvalidTaskKey", tt.key, prefix, number, err)
+				}
+				return
+			}
+			if err != nil || prefix != tt.prefix || number != tt.number {
+				t.Fatalf("ParseTaskKey(%q) = %q, %d, %v, want %q, %d", tt.key, prefix, number, err, tt.prefix, tt.number)
+			}
+		})
+	}
+}
+
+func TestTaskKeyRoundTrip(t *testing.T) {
+	for _, number := range []int64{1, 42, 1000000} {
+		key := TaskKey("PRJ", number)
+		prefix, parsed, err := ParseTaskKey(key)
+		if err != nil || prefix != "PRJ" || parsed != number {
+			t.Errorf("ParseTaskKey(%q) = %q, %d, %v", key, prefix, parsed, err)
+		}
+	}
+}
+
+func TestNormalizeKeyPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		want    string
+		invalid bool
+	}{
+		{"PRJ", "PRJ", false},
+		{"prj", "PRJ", false},
+		{"  Web2 ", "WEB2", false},
+		{"AB", "AB", false},
+		{"ABCDEFGHIJ", "ABCDEFGHIJ", false},
+		{"", "", true},
+		{"A", "", true},
+		{"ABCDEFGHIJK", "", true},
+		{"2FA", "", true},
+		{"MY-APP", "", true},
+		{"MY APP", "", true},
+		{"ŽUTO", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			prefix, err := NormalizeKeyPrefix(tt.prefix)
+			if tt.invalid {
+				if !errors.Is(err, ErrInvalidTaskKey()) {
+					t.Fatalf("NormalizeKeyPrefix(%q) = %q, %v, want ErrInvalidTaskKey", tt.prefix, prefix, err)
+				}
+				return
+			}
+			if err != nil || prefix != tt.want {
+				t.Fatalf("NormalizeKeyPrefix(%q) = %q, %v, want %q", tt.prefix, prefix, err, tt.want)
+			}
+		})
+	}
+}
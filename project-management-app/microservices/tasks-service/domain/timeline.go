@@ -0,0 +1,362 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ICAL_EVENT = "VEVENT"
+	ICAL_TODO  = "VTODO"
+)
+
+// workdayHours turns estimates into days on a timeline.
+const workdayHours = 8
+
+// TimelineItem is the bar of a task on a timeline. Start and End are whole
+// days, End included.
+type TimelineItem struct {
+	Task       string     `json:"task"`
+	Key        string     `json:"key,omitempty"`
+	Project    string     `json:"project"`
+	Name       string     `json:"name"`
+	Parent     string     `json:"parent,omitempty"`
+	Status     string     `json:"status"`
+	State      string     `json:"state"`
+	Start      time.Time  `json:"start"`
+	End        time.Time  `json:"end"`
+	DueDate    *time.Time `json:"due_date,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DependsOn  []string   `json:"depends_on"`
+	Assignees  []string   `json:"assignees"`
+	Progress   float64    `json:"progress"`
+	Late       bool       `json:"late"`
+}
+
+// Timeline lays out the tasks of a project between its start and its end
+// date. A user's calendar is a timeline of the tasks due for the user.
+type Timeline struct {
+	Project     string          `json:"project,omitempty"`
+	Name        string          `json:"name"`
+	Start       time.Time       `json:"start"`
+	End         *time.Time      `json:"end,omitempty"`
+	Items       []*TimelineItem `json:"items"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// NewTimeline schedules the tasks of a project. A task starts on the day it
+// was created, or the day after the last of its blockers ends if that is
+// later. It ends when it was finished, on its due date or, without one, after
+// its estimate in working days. A task is late when it can't be done by its
+// due date or ends after the project.
+func NewTimeline(projectId string, name string, end time.Time, tasks Tasks, now time.Time) *Timeline {
+	timeline := &Timeline{Project: projectId, Name: name, Items: []*TimelineItem{}, GeneratedAt: now}
+	if !end.IsZero() {
+		projectEnd := timelineDay(end)
+		timeline.End = &projectEnd
+	}
+
+	byId := map[string]*Task{}
+	for _, task := range tasks {
+		byId[task.Id.Hex()] = task
+	}
+
+	items := map[string]*TimelineItem{}
+	var schedule func(task *Task, visiting map[string]bool) *TimelineItem
+	schedule = func(task *Task, visiting map[string]bool) *TimelineItem {
+		id := task.Id.Hex()
+		if item, ok := items[id]; ok {
+			return item
+		}
+		visiting[id] = true
+		defer delete(visiting, id)
+
+		item := newTimelineItem(task)
+		item.Start = timelineDay(task.CreatedAt)
+		for _, blockerId := range task.BlockedBy {
+			blocker, ok := byId[blockerId]
+			if !ok || visiting[blockerId] {
+				continue
+			}
+			item.DependsOn = append(item.DependsOn, blockerId)
+			if after := schedule(blocker, visiting).End.AddDate(0, 0, 1); after.After(item.Start) {
+				item.Start = after
+			}
+		}
+
+		switch {
+		case task.FinishedAt != nil:
+			item.End = timelineDay(*task.FinishedAt)
+		case task.DueDate != nil:
+			item.End = timelineDay(*task.DueDate)
+		case task.EstimateHours > 0:
+			days := int(math.Ceil(task.EstimateHours / workdayHours))
+			item.End = item.Start.AddDate(0, 0, days-1)
+		default:
+			item.End = item.Start
+		}
+		if item.End.Before(item.Start) {
+			if task.FinishedAt == nil {
+				item.Late = true
+			}
+			item.End = item.Start
+		}
+		if task.FinishedAt == nil && task.DueDate != nil && task.DueDate.Before(now) {
+			item.Late = true
+		}
+		if timeline.End != nil && item.End.After(*timeline.End) {
+			item.Late = true
+		}
+
+		items[id] = item
+		return item
+	}
+
+	for _, task := range tasks {
+		item := schedule(task, map[string]bool{})
+		if timeline.Start.IsZero() || item.Start.Before(timeline.Start) {
+			timeline.Start = item.Start
+		}
+		timeline.Items = append(timeline.Items, item)
+	}
+	sort.SliceStable(timeline.Items, func(i, j int) bool {
+		return timeline.Items[i].Start.Before(timeline.Items[j].Start)
+	})
+	return timeline
+}
+
+// NewDueCalendar lists the tasks of a user on their due dates.
+func NewDueCalendar(username string, tasks Tasks, now time.Time) *Timeline {
+	timeline := &Timeline{Name: "Tasks of " + username, Items: []*TimelineItem{}, GeneratedAt: now}
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		item := newTimelineItem(task)
+		item.Start = timelineDay(*task.DueDate)
+		item.End = item.Start
+		item.Late = task.FinishedAt == nil && task.DueDate.Before(now)
+		if timeline.Start.IsZero() || item.Start.Before(timeline.Start) {
+			timeline.Start = item.Start
+		}
+		timeline.Items = append(timeline.Items, item)
+	}
+	return timeline
+}
+
+func newTimelineItem(task *Task) *TimelineItem {
+	item := &TimelineItem{
+		Task:       task.Id.Hex(),
+		Key:        task.Key,
+		Project:    task.Project,
+		Name:       task.Name,
+		Parent:     task.Parent,
+		Status:     task.Status.String(),
+		State:      task.CurrentState(),
+		DueDate:    task.DueDate,
+		FinishedAt: task.FinishedAt,
+		DependsOn:  []string{},
+		Assignees:  []string{},
+	}
+	for _, member := range task.Members {
+		item.Assignees = append(item.Assignees, member.Username)
+	}
+	switch {
+	case task.Status == FINISHED:
+		item.Progress = 1
+	case len(task.Checklist) > 0:
+		done := 0
+		for _, checklistItem := range task.Checklist {
+			if checklistItem.Done {
+				done++
+			}
+		}
+		item.Progress = math.Round(float64(done)/float64(len(task.Checklist))*100) / 100
+	}
+	return item
+}
+
+func timelineDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (t *Timeline) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(t)
+}
+
+// ToCSV writes a row per task, dependencies are listed by key.
+func (t *Timeline) ToCSV(w io.Writer) error {
+	keys := map[string]string{}
+	for _, item := range t.Items {
+		keys[item.Task] = item.label()
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{"key", "task", "name", "parent", "state", "start", "end", "due_date", "depends_on", "assignees", "progress", "late"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, item := range t.Items {
+		dependsOn := make([]string, 0, len(item.DependsOn))
+		for _, id := range item.DependsOn {
+			dependsOn = append(dependsOn, keys[id])
+		}
+		dueDate := ""
+		if item.DueDate != nil {
+			dueDate = item.DueDate.UTC().Format("2006-01-02")
+		}
+		record := []string{
+			item.Key,
+			item.Task,
+			item.Name,
+			keys[item.Parent],
+			item.State,
+			item.Start.Format("2006-01-02"),
+			item.End.Format("2006-01-02"),
+			dueDate,
+			strings.Join(dependsOn, ";"),
+			strings.Join(item.Assignees, ";"),
+			fmt.Sprint(item.Progress),
+			fmt.Sprint(item.Late),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ToICal writes the timeline as an iCalendar file, each task as a VEVENT
+// spanning its days or as a VTODO due on its end. The end date of the
+// project is added as an event.
+func (t *Timeline) ToICal(w io.Writer, component string) error {
+	cal := &icalWriter{w: w}
+	stamp := icalTime(t.GeneratedAt)
+
+	cal.line("BEGIN", "VCALENDAR")
+	cal.line("VERSION", "2.0")
+	cal.line("PRODID", "-//project-management-app//tasks-service//EN")
+	cal.line("CALSCALE", "GREGORIAN")
+	cal.line("METHOD", "PUBLISH")
+	cal.line("X-WR-CALNAME", icalText(t.Name))
+
+	for _, item := range t.Items {
+		cal.line("BEGIN", component)
+		cal.line("UID", item.Task+"@tasks-service")
+		cal.line("DTSTAMP", stamp)
+		cal.line("SUMMARY", icalText(item.summary()))
+		cal.line("DESCRIPTION", icalText(item.description()))
+		if component == ICAL_TODO {
+			if item.Start.Before(item.End) {
+				cal.line("DTSTART;VALUE=DATE", icalDate(item.Start))
+			}
+			cal.line("DUE;VALUE=DATE", icalDate(item.End))
+			cal.line("PERCENT-COMPLETE", fmt.Sprint(int(item.Progress*100)))
+			switch {
+			case item.FinishedAt != nil:
+				cal.line("STATUS", "COMPLETED")
+				cal.line("COMPLETED", icalTime(*item.FinishedAt))
+			case item.Status == IN_PROGRESS.String():
+				cal.line("STATUS", "IN-PROCESS")
+			default:
+				cal.line("STATUS", "NEEDS-ACTION")
+			}
+			if item.Parent != "" {
+				cal.line("RELATED-TO;RELTYPE=PARENT", item.Parent+"@tasks-service")
+			}
+		} else {
+			cal.line("DTSTART;VALUE=DATE", icalDate(item.Start))
+			cal.line("DTEND;VALUE=DATE", icalDate(item.End.AddDate(0, 0, 1)))
+			cal.line("TRANSP", "TRANSPARENT")
+		}
+		cal.line("END", component)
+	}
+
+	if t.End != nil {
+		cal.line("BEGIN", ICAL_EVENT)
+		cal.line("UID", t.Project+"-end@tasks-service")
+		cal.line("DTSTAMP", stamp)
+		cal.line("SUMMARY", icalText(t.Name+" ends"))
+		cal.line("DTSTART;VALUE=DATE", icalDate(*t.End))
+		cal.line("DTEND;VALUE=DATE", icalDate(t.End.AddDate(0, 0, 1)))
+		cal.line("TRANSP", "TRANSPARENT")
+		cal.line("END", ICAL_EVENT)
+	}
+
+	cal.line("END", "VCALENDAR")
+	return cal.err
+}
+
+func (i *TimelineItem) label() string {
+	if i.Key != "" {
+		return i.Key
+	}
+	return i.Task
+}
+
+func (i *TimelineItem) summary() string {
+	if i.Key != "" {
+		return i.Key + " " + i.Name
+	}
+	return i.Name
+}
+
+func (i *TimelineItem) description() string {
+	description := "State: " + i.State
+	if len(i.Assignees) > 0 {
+		description += "\nAssignees: " + strings.Join(i.Assignees, ", ")
+	}
+	if i.Late {
+		description += "\nLate"
+	}
+	return description
+}
+
+// icalWriter writes content lines, folded at 75 octets as RFC 5545 asks.
+type icalWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (c *icalWriter) line(name string, value string) {
+	if c.err != nil {
+		return
+	}
+	line := name + ":" + value
+	var folded strings.Builder
+	// Continuation lines start with a space, which counts towards the limit.
+	for limit := 75; len(line) > limit; limit = 74 {
+		cut := limit
+		// Don't split a multi-byte character.
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	folded.WriteString(line)
+	folded.WriteString("\r\n")
+	_, c.err = io.WriteString(c.w, folded.String())
+}
+
+func icalText(s string) string {
+	return strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\r\n", "\\n", "\n", "\\n").Replace(s)
+}
+
+func icalDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+func icalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
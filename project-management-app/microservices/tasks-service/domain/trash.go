@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// TrashedTask is a deleted task together with the time after which it is
+// permanently purged.
+type TrashedTask struct {
+	Task    *Task     `json:"task"`
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+type Trash []*TrashedTask
+
+func (t *Trash) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(t)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// UnassignedTask is a task a user was taken off when they left its project.
+type UnassignedTask struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type MemberUnassignment struct {
+	Username string           `json:"username"`
+	Tasks    []UnassignedTask `json:"tasks"`
+	Notified bool             `json:"notified"`
+}
+
+// Unassignment reports the open tasks of a project that removed members
+// were unassigned from.
+type Unassignment struct {
+	Project string                `json:"project"`
+	Members []*MemberUnassignment `json:"members"`
+}
+
+func (u *Unassignment) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(u)
+}
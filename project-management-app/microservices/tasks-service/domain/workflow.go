@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// NOTIFY_MEMBERS as a hook target notifies every member of the task.
+	NOTIFY_MEMBERS = "members"
+)
+
+// Workflow defines the statuses tasks of a project can be in and the
+// transitions allowed between them. Every state belongs to one of the fixed
+// categories PENDING, IN_PROGRESS and FINISHED, which the rest of the system
+// keeps working with.
+type Workflow struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Project      string             `bson:"project" json:"project"`
+	InitialState string             `bson:"initial_state" json:"initial_state"`
+	States       []WorkflowState    `bson:"states" json:"states"`
+	Transitions  []Transition       `bson:"transitions" json:"transitions"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+type WorkflowState struct {
+	Name     string `bson:"name" json:"name"`
+	Category string `bson:"category" json:"category"`
+}
+
+// Transition allows tasks to move from one state to another. Only users with
+// one of the given roles, PROJECT_MANAGER or PROJECT_MEMBER, may use it, any
+// role when Roles is empty.
+type Transition struct {
+	From  string           `bson:"from" json:"from"`
+	To    string           `bson:"to" json:"to"`
+	Roles []string         `bson:"roles,omitempty" json:"roles,omitempty"`
+	Hooks []TransitionHook `bson:"hooks,omitempty" json:"hooks,omitempty"`
+}
+
+// TransitionHook sends a notification when a transition is taken. Notify
+// holds usernames or NOTIFY_MEMBERS.
+type TransitionHook struct {
+	Notify  []string `bson:"notify" json:"notify"`
+	Message string   `bson:"message,omitempty" json:"message,omitempty"`
+}
+
+// DefaultWorkflow is used by projects without a workflow of their own: the
+// three fixed statuses with every transition allowed to everyone.
+func DefaultWorkflow(project string) *Workflow {
+	states := []WorkflowState{}
+	for _, status := range []Status{PENDING, IN_PROGRESS, FINISHED} {
+		states = append(states, WorkflowState{Name: status.String(), Category: status.String()})
+	}
+
+	transitions := []Transition{}
+	for _, from := range states {
+		for _, to := range states {
+			if from.Name != to.Name {
+				transitions = append(transitions, Transition{From: from.Name, To: to.Name})
+			}
+		}
+	}
+
+	return &Workflow{Project: project, InitialState: PENDING.String(), States: states, Transitions: transitions}
+}
+
+// Validate checks that the workflow is consistent: unique states with known
+// categories, an existing initial state and transitions between existing
+// states, restricted to known roles.
+func (w *Workflow) Validate() error {
+	if len(w.States) == 0 {
+		return errors.New("workflow must have at least one state")
+	}
+
+	finished := false
+	names := map[string]bool{}
+	for _, state := range w.States {
+		if state.Name == "" {
+			return errors.New("workflow state name is required")
+		}
+		if names[state.Name] {
+			return fmt.Errorf("duplicate workflow state %s", state.Name)
+		}
+		names[state.Name] = true
+
+		category, err := StatusFromString(state.Category)
+		if err != nil {
+			return fmt.Errorf("invalid category %s of state %s", state.Category, state.Name)
+		}
+		if category == FINISHED {
+			finished = true
+		}
+	}
+	if !finished {
+		return errors.New("workflow must have a state in the FINISHED category")
+	}
+	if !names[w.InitialState] {
+		return fmt.Errorf("unknown initial state %s", w.InitialState)
+	}
+
+	for _, transition := range w.Transitions {
+		if !names[transition.From] || !names[transition.To] {
+			return fmt.Errorf("transition %s -> %s uses an unknown state", transition.From, transition.To)
+		}
+		if transition.From == transition.To {
+			return fmt.Errorf("transition %s -> %s doesn't change the state", transition.From, transition.To)
+		}
+		for _, role := range transition.Roles {
+			if role != "PROJECT_MANAGER" && role != "PROJECT_MEMBER" {
+				return fmt.Errorf("transition %s -> %s is restricted to an unknown role %s", transition.From, transition.To, role)
+			}
+		}
+	}
+	return nil
+}
+
+// State returns the state with the given name.
+func (w *Workflow) State(name string) (WorkflowState, bool) {
+	for _, state := range w.States {
+		if state.Name == name {
+			return state, true
+		}
+	}
+	return WorkflowState{}, false
+}
+
+// StateForStatus returns the first state of the given category. It lets
+// clients that only know the fixed statuses keep working.
+func (w *Workflow) StateForStatus(status Status) (WorkflowState, bool) {
+	if status < PENDING || status > FINISHED {
+		return WorkflowState{}, false
+	}
+	for _, state := range w.States {
+		if state.Category == status.String() {
+			return state, true
+		}
+	}
+	return WorkflowState{}, false
+}
+
+// Transition returns the transition between two states.
+func (w *Workflow) Transition(from string, to string) (Transition, bool) {
+	for _, transition := range w.Transitions {
+		if transition.From == from && transition.To == to {
+			return transition, true
+		}
+	}
+	return Transition{}, false
+}
+
+// Allows reports whether a user with the given role may take the transition.
+func (t Transition) Allows(role string) bool {
+	if len(t.Roles) == 0 {
+		return true
+	}
+	for _, allowed := range t.Roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryStatus returns the fixed status a state belongs to.
+func (s WorkflowState) CategoryStatus() Status {
+	status, _ := StatusFromString(s.Category)
+	return status
+}
+
+func (w *Workflow) ToJSON(wr io.Writer) error {
+	e := json.NewEncoder(wr)
+	return e.Encode(w)
+}
+
+func (w *Workflow) FromJSON(r io.Reader) error {
+	d := json.NewDecoder(r)
+	return d.Decode(w)
+}
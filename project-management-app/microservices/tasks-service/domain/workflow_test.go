@@ -0,0 +1,166 @@
+package domain
+
+import "testing"
+
+// reviewWorkflow has two states in the IN_PROGRESS category and a transition
+// only managers may take.
+func reviewWorkflow() *Workflow {
+	return &Workflow{
+		Project:      "project",
+		InitialState: "Backlog",
+		States: []WorkflowState{
+			{Name: "Backlog", Category: "PENDING"},
+			{Name: "Doing", Category: "IN_PROGRESS"},
+			{Name: "Review", Category: "IN_PROGRESS"},
+			{Name: "Done", Category: "FINISHED"},
+		},
+		Transitions: []Transition{
+			{From: "Backlog", To: "Doing"},
+			{From: "Doing", To: "Review"},
+			{From: "Review", To: "Doing"},
+			{From: "Review", To: "Done", Roles: []string{"PROJECT_MANAGER"}},
+		},
+	}
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(w *Workflow)
+		invalid bool
+	}{
+		{"valid", func(w *Workflow) {}, false},
+		{"no states", func(w *Workflow) { w.States = nil }, true},
+		{"unnamed state", func(w *Workflow) { w.States[1].Name = "" }, true},
+		{"duplicate state", func(w *Workflow) { w.States[2].Name = "Doing" }, true},
+		{"unknown category", func(w *Workflow) { w.States[1].Category = "BLOCKED" }, true},
+		{"no finished state", func(w *Workflow) { w.States[3].Category = "IN_PROGRESS" }, true},
+		{"unknown initial state", func(w *Workflow) { w.InitialState = "Todo" }, true},
+		{"transition from an unknown state", func(w *Workflow) { w.Transitions[0].From = "Todo" }, true},
+		{"transition to an unknown state", func(w *Workflow) { w.Transitions[0].To = "Todo" }, true},
+		{"transition to the same state", func(w *Workflow) { w.Transitions[0].To = "Backlog" }, true},
+		{"transition for both roles", func(w *Workflow) { w.Transitions[0].Roles = []string{"PROJECT_MEMBER", "PROJECT_MANAGER"} }, false},
+		{"transition for an unknown role", func(w *Workflow) { w.Transitions[3].Roles = []string{"Manager"} }, true},
+		{"transition for a lowercase role", func(w *Workflow) { w.Transitions[0].Roles = []string{"project_member"} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflow := reviewWorkflow()
+			tt.change(workflow)
+			err := workflow.Validate()
+			if tt.invalid && err == nil {
+				t.Fatal("Validate succeeded, want an error")
+			}
+			if !tt.invalid && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+		})
+	}
+}
+
+func TestWorkflowTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		role    string
+		exists  bool
+		allowed bool
+	}{
+		{"open transition", "Backlog", "Doing", "PROJECT_MEMBER", true, true},
+		{"back to an earlier state", "Review", "Doing", "PROJECT_MEMBER", true, true},
+		{"restricted transition for a manager", "Review", "Done", "PROJECT_MANAGER", true, true},
+		{"restricted transition for a member", "Review", "Done", "PROJECT_MEMBER", true, false},
+		{"missing transition", "Backlog", "Done", "PROJECT_MANAGER", false, false},
+		{"reversed transition", "Doing", "Backlog", "PROJECT_MEMBER", false, false},
+		{"unknown state", "Backlog", "Todo", "PROJECT_MEMBER", false, false},
+	}
+
+	workflow := reviewWorkflow()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transition, ok := workflow.Transition(tt.from, tt.to)
+			if ok != tt.exists {
+				t.Fatalf("Transition(%q, %q) found = %v, want %v", tt.from, tt.to, ok, tt.exists)
+			}
+			if ok && transition.Allows(tt.role) != tt.allowed {
+				t.Fatalf("Allows(%q) = %v, want %v", tt.role, !tt.allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestDefaultWorkflow(t *testing.T) {
+	workflow := DefaultWorkflow("project")
+	if err := workflow.Validate(); err != nil {
+		t.Fatalf("default workflow is invalid: %v", err)
+	}
+	for _, from := range []Status{PENDING, IN_PROGRESS, FINISHED} {
+		for _, to := range []Status{PENDING, IN_PROGRESS, FINISHED} {
+			transition, ok := workflow.Transition(from.String(), to.String())
+			if ok != (from != to) {
+				t.Errorf("Transition(%s, %s) found = %v", from, to, ok)
+			}
+			if ok && !transition.Allows("PROJECT_MEMBER") {
+				t.Errorf("Transition(%s, %s) is restricted", from, to)
+			}
+		}
+	}
+}
+
+func TestStateForStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status Status
+		state  string
+		ok     bool
+	}{
+		{"pending", PENDING, "Backlog", true},
+		{"first of several states", IN_PROGRESS, "Doing", true},
+		{"finished", FINISHED, "Done", true},
+		{"unset status", 0, "", false},
+		{"negative status", -1, "", false},
+		{"status after the last", FINISHED + 1, "", false},
+	}
+
+	workflow := reviewWorkflow()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, ok := workflow.StateForStatus(tt.status)
+			if ok != tt.ok || state.Name != tt.state {
+				t.Fatalf("StateForStatus(%d) = %q, %v, want %q, %v", tt.status, state.Name, ok, tt.state, tt.ok)
+			}
+		})
+	}
+}
+
+func TestStatusString(t *testing.T) {
+	tests := []struct {
+		status Status
+		want   string
+	}{
+		{PENDING, "PENDING"},
+		{IN_PROGRESS, "IN_PROGRESS"},
+		{FINISHED, "FINISHED"},
+		{0, "0"},
+		{-1, "-1"},
+		{FINISHED + 1, "4"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.String(); got != tt.want {
+			t.Errorf("Status(%d).String() = %q, want %q", int(tt.status), got, tt.want)
+		}
+	}
+}
+
+func TestCategoryStatus(t *testing.T) {
+	workflow := reviewWorkflow()
+	want := []Status{PENDING, IN_PROGRESS, IN_PROGRESS, FINISHED}
+	for i, state := range workflow.States {
+		if got := state.CategoryStatus(); got != want[i] {
+			t.Errorf("CategoryStatus of %s = %s, want %s", state.Name, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// ProjectLoad is the open work of a user in one project.
+type ProjectLoad struct {
+	Project       string  `bson:"project" json:"project"`
+	Open          int     `bson:"open" json:"open"`
+	EstimateHours float64 `bson:"estimate_hours" json:"estimate_hours"`
+	StoryPoints   float64 `bson:"story_points" json:"story_points"`
+}
+
+// UserWorkload is the open work of a user across all projects. A task with
+// several members counts fully towards the task counts of each of them, its
+// estimate and story points are split evenly between them.
+type UserWorkload struct {
+	Username      string        `bson:"_id" json:"username"`
+	Open          int           `bson:"open" json:"open"`
+	InProgress    int           `bson:"in_progress" json:"in_progress"`
+	Overdue       int           `bson:"overdue" json:"overdue"`
+	EstimateHours float64       `bson:"estimate_hours" json:"estimate_hours"`
+	StoryPoints   float64       `bson:"story_points" json:"story_points"`
+	Projects      []ProjectLoad `bson:"projects" json:"projects"`
+}
+
+type Workloads []*UserWorkload
+
+// SortByLoad orders users from the least to the most loaded, by estimated
+// hours, then by open tasks.
+func (w Workloads) SortByLoad() {
+	sort.SliceStable(w, func(i, j int) bool {
+		if w[i].EstimateHours != w[j].EstimateHours {
+			return w[i].EstimateHours < w[j].EstimateHours
+		}
+		if w[i].Open != w[j].Open {
+			return w[i].Open < w[j].Open
+		}
+		return w[i].Username < w[j].Username
+	})
+}
+
+func (w *Workloads) ToJSON(wr io.Writer) error {
+	e := json.NewEncoder(wr)
+	return e.Encode(w)
+}
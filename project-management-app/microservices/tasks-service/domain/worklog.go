@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Worklog is time a member spent on a task. Entries started with a timer have
+// no End and Minutes until the timer is stopped.
+type Worklog struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Task      string             `bson:"task" json:"task"`
+	Project   string             `bson:"project" json:"project"`
+	Username  string             `bson:"username" json:"username"`
+	Start     time.Time          `bson:"start" json:"start"`
+	End       *time.Time         `bson:"end,omitempty" json:"end,omitempty"`
+	Minutes   int                `bson:"minutes" json:"minutes"`
+	Note      string             `bson:"note,omitempty" json:"note,omitempty"`
+	Manual    bool               `bson:"manual" json:"manual"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type Worklogs []*Worklog
+
+// Running reports whether the worklog is a timer that wasn't stopped yet.
+func (w *Worklog) Running() bool {
+	return w.End == nil
+}
+
+// WorklogFilter selects worklogs. Empty fields don't filter, From and To
+// bound the start of the worklog.
+type WorklogFilter struct {
+	Project  string
+	Task     string
+	Username string
+	From     *time.Time
+	To       *time.Time
+}
+
+// TimeTotal is the logged time of one member or task.
+type TimeTotal struct {
+	Key     string `bson:"_id" json:"key"`
+	Minutes int    `bson:"minutes" json:"minutes"`
+}
+
+// TimeSummary sums up the time logged on a task or project.
+type TimeSummary struct {
+	Target        string      `json:"target"`
+	TotalMinutes  int         `json:"total_minutes"`
+	EstimateHours float64     `json:"estimate_hours"`
+	ByMember      []TimeTotal `json:"by_member"`
+	ByTask        []TimeTotal `json:"by_task,omitempty"`
+	Worklogs      Worklogs    `json:"worklogs,omitempty"`
+}
+
+func (w *Worklog) ToJSON(wr io.Writer) error {
+	e := json.NewEncoder(wr)
+	return e.Encode(w)
+}
+
+func (w *Worklogs) ToJSON(wr io.Writer) error {
+	e := json.NewEncoder(wr)
+	return e.Encode(w)
+}
+
+// ToCSV writes the worklogs as CSV with a header row.
+func (w Worklogs) ToCSV(wr io.Writer) error {
+	writer := csv.NewWriter(wr)
+	if err := writer.Write([]string{"id", "project", "task", "username", "start", "end", "minutes", "manual", "note"}); err != nil {
+		return err
+	}
+	for _, worklog := range w {
+		end := ""
+		if worklog.End != nil {
+			end = worklog.End.Format(time.RFC3339)
+		}
+		record := []string{
+			worklog.Id.Hex(),
+			worklog.Project,
+			worklog.Task,
+			worklog.Username,
+			worklog.Start.Format(time.RFC3339),
+			end,
+			fmt.Sprint(worklog.Minutes),
+			fmt.Sprint(worklog.Manual),
+			worklog.Note,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *TimeSummary) ToJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	return e.Encode(s)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+)
+
+// idResources tells what the {id} of a route refers to, by the start of its
+// path template. Saved filters belong to the caller, their routes aren't tied
+// to a project.
+var idResources = []struct {
+	prefix string
+	kind   string
+}{
+	{"/tasks/members/", domain.RESOURCE_TASK},
+	{"/users/", domain.RESOURCE_TASK},
+	{"/tasks/", domain.RESOURCE_PROJECT},
+	{"/sprints/", domain.RESOURCE_SPRINT},
+	{"/labels/", domain.RESOURCE_LABEL},
+	{"/comments/", domain.RESOURCE_COMMENT},
+	{"/worklogs/", domain.RESOURCE_WORKLOG},
+	{"/attachments/", domain.RESOURCE_ATTACHMENT},
+	{"/imports/", domain.RESOURCE_IMPORT},
+}
+
+// MiddlewareProjectAccess rejects requests on a project, or on a task or
+// another resource of a project, the caller doesn't manage or isn't a member
+// of. It has to run after the authentication middleware.
+func (h *TaskHandler) MiddlewareProjectAccess(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		projectId, err := h.routeProject(r)
+		if err == nil && projectId != "" {
+			err = h.checkAccess(r, projectId)
+		}
+		if err != nil {
+			writeErrorResp(err, w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// MiddlewareGatewayAuth protects routes other services call without a token.
+// Requests coming through the API gateway, or carrying a token, are
+// authenticated with auth and checked like the private routes.
+func (h *TaskHandler) MiddlewareGatewayAuth(auth mux.MiddlewareFunc) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		external := auth(h.MiddlewareProjectAccess(next))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Forwarded-Prefix") == "" && r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			external.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MiddlewareInternal keeps the routes meant for other services off the API
+// gateway, which marks every request it forwards with X-Forwarded-Prefix.
+func (h *TaskHandler) MiddlewareInternal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Forwarded-Prefix") != "" {
+			writeErrorResp(domain.ErrUnauthorized(), w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeProject returns the project a request refers to through its route
+// variables, "" when the route isn't tied to a project.
+func (h *TaskHandler) routeProject(r *http.Request) (string, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	if taskId, ok := vars["taskId"]; ok {
+		projectId, err := h.tasks.ResourceProject(ctx, domain.RESOURCE_TASK, taskId)
+		if err != nil {
+			return "", err
+		}
+		if routeProject, ok := vars["projectId"]; ok && routeProject != projectId {
+			return "", errors.New("task not found")
+		}
+		return projectId, nil
+	}
+	if projectId, ok := vars["projectId"]; ok {
+		return projectId, nil
+	}
+
+	id, ok := vars["id"]
+	route := mux.CurrentRoute(r)
+	if !ok || route == nil {
+		return "", nil
+	}
+	template, _ := route.GetPathTemplate()
+	for _, resource := range idResources {
+		if strings.HasPrefix(template, resource.prefix) {
+			return h.tasks.ResourceProject(ctx, resource.kind, id)
+		}
+	}
+	return "", nil
+}
+
+// checkAccess checks the caller against a project that isn't part of the
+// route, like the project in the body of a new task.
+func (h *TaskHandler) checkAccess(r *http.Request, projectId string) error {
+	username, _ := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role, _ := r.Context().Value(authorizationlib.RoleKey).(string)
+	return h.tasks.CheckProjectAccess(r.Context(), projectId, username, role)
+}
+
+// visibleTasks drops the tasks of projects the caller has no access to.
+// Calls from other services carry no user and see all tasks.
+func (h *TaskHandler) visibleTasks(r *http.Request, tasks domain.Tasks) domain.Tasks {
+	username, ok := r.Context().Value(authorizationlib.UsernameKey).(string)
+	if !ok {
+		return tasks
+	}
+	role, _ := r.Context().Value(authorizationlib.RoleKey).(string)
+	return h.tasks.VisibleTasks(r.Context(), tasks, username, role)
+}
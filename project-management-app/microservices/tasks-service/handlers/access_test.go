@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"project-management-app/microservices/projects-service/domain"
+	"project-management-app/microservices/projects-service/repositories"
+	"project-management-app/microservices/projects-service/services"
+	"strings"
+	"testing"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// projectsService answers the access requests tasks-service sends to
+// projects-service, 404 for projects it doesn't know.
+type projectsService map[string]*domain.ProjectAccess
+
+func (p projectsService) RoundTrip(r *http.Request) (*http.Response, error) {
+	access, ok := p[strings.TrimPrefix(r.URL.Path, "/projects/access/")]
+	if r.URL.Host != "projects-service:8000" || !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(&bytes.Buffer{}), Request: r}, nil
+	}
+	body, err := json.Marshal(access)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body)), Request: r}, nil
+}
+
+var projects = projectsService{
+	"p1": {Project: "p1", Manager: "mila", Members: []string{"ana"}},
+	"p2": {Project: "p2", Manager: "boss", Members: []string{"ivan"}},
+}
+
+// useProjectsService sends the calls to projects-service to projects until
+// the test ends. The task service uses the default transport.
+func useProjectsService(t *testing.T) {
+	transport := http.DefaultTransport
+	http.DefaultTransport = projects
+	t.Cleanup(func() { http.DefaultTransport = transport })
+}
+
+// fakeAuth stands in for the token check and takes the caller from the
+// X-Test-User and X-Test-Role headers.
+func fakeAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), authorizationlib.UsernameKey, r.Header.Get("X-Test-User"))
+		ctx = context.WithValue(ctx, authorizationlib.RoleKey, r.Header.Get("X-Test-Role"))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newTestHandler(mt *mtest.T) *TaskHandler {
+	tracer := noop.NewTracerProvider().Tracer("")
+	repo := repositories.NewTaskRepoWithClient(mt.Client, log.New(io.Discard, "", 0), tracer)
+	return NewTaskHandler(services.NewTaskService(repo, tracer, nil, services.AttachmentLimits{}, 0), repo, tracer)
+}
+
+// newAccessRouter wires the access middlewares the way main does, in front
+// of routes that only answer 204.
+func newAccessRouter(h *TaskHandler) *mux.Router {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+
+	router := mux.NewRouter()
+	privateRouter := router.NewRoute().Subrouter()
+	privateRouter.Use(fakeAuth)
+	privateRouter.Use(h.MiddlewareProjectAccess)
+	getRouter := router.Methods(http.MethodGet).Subrouter()
+	getRouter.Use(h.MiddlewareGatewayAuth(fakeAuth))
+
+	privateRouter.HandleFunc("/tasks/{taskId}/comments", ok)
+	privateRouter.HandleFunc("/tasks/project/{projectId}/keys/{key}", ok)
+	privateRouter.HandleFunc("/sprints/{id}", ok)
+	privateRouter.HandleFunc("/filters/{id}/tasks", ok)
+	getRouter.HandleFunc("/tasks/{projectId}/{taskId}/members", ok)
+	return router
+}
+
+func toDoc(t testing.TB, v interface{}) bson.D {
+	t.Helper()
+	data, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func found(t testing.TB, collection string, v interface{}) bson.D {
+	return mtest.CreateCursorResponse(0, "tasks."+collection, mtest.FirstBatch, toDoc(t, v))
+}
+
+func notFound(collection string) bson.D {
+	return mtest.CreateCursorResponse(0, "tasks."+collection, mtest.FirstBatch)
+}
+
+func TestMiddlewareProjectAccess(t *testing.T) {
+	useProjectsService(t)
+	taskId := primitive.NewObjectID()
+	sprintId := primitive.NewObjectID()
+	inP1 := func(t testing.TB) bson.D {
+		return found(t, "tasks", domain.Task{Id: taskId, Project: "p1", Name: "task"})
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		user      string
+		role      string
+		forwarded bool
+		db        func(t testing.TB) []bson.D
+		want      int
+	}{
+		{name: "member of the project in the route", path: "/tasks/project/p1/keys/TASK-1", user: "ana", role: "PROJECT_MEMBER", want: http.StatusNoContent},
+		{name: "manager of the project in the route", path: "/tasks/project/p1/keys/TASK-1", user: "mila", role: "PROJECT_MANAGER", want: http.StatusNoContent},
+		{name: "manager of another project", path: "/tasks/project/p1/keys/TASK-1", user: "boss", role: "PROJECT_MANAGER", want: http.StatusForbidden},
+		{name: "manager acting as a member", path: "/tasks/project/p1/keys/TASK-1", user: "mila", role: "PROJECT_MEMBER", want: http.StatusForbidden},
+		{name: "unknown project", path: "/tasks/project/p9/keys/TASK-1", user: "ana", role: "PROJECT_MEMBER", want: http.StatusNotFound},
+		{
+			name: "task of the caller's project", path: "/tasks/" + taskId.Hex() + "/comments", user: "ana", role: "PROJECT_MEMBER",
+			db:   func(t testing.TB) []bson.D { return []bson.D{inP1(t)} },
+			want: http.StatusNoContent,
+		},
+		{
+			name: "task of another project", path: "/tasks/" + taskId.Hex() + "/comments", user: "ivan", role: "PROJECT_MEMBER",
+			db:   func(t testing.TB) []bson.D { return []bson.D{inP1(t)} },
+			want: http.StatusForbidden,
+		},
+		{
+			name: "trashed task", path: "/tasks/" + taskId.Hex() + "/comments", user: "ana", role: "PROJECT_MEMBER",
+			db:   func(t testing.TB) []bson.D { return []bson.D{notFound("tasks"), inP1(t)} },
+			want: http.StatusNoContent,
+		},
+		{
+			name: "missing task", path: "/tasks/" + taskId.Hex() + "/comments", user: "ana", role: "PROJECT_MEMBER",
+			db:   func(t testing.TB) []bson.D { return []bson.D{notFound("tasks"), notFound("tasks")} },
+			want: http.StatusNotFound,
+		},
+		{
+			name: "task under another project of the route", path: "/tasks/p2/" + taskId.Hex() + "/members", user: "ivan", role: "PROJECT_MEMBER", forwarded: true,
+			db:   func(t testing.TB) []bson.D { return []bson.D{inP1(t)} },
+			want: http.StatusNotFound,
+		},
+		{
+			name: "task under its project through the gateway", path: "/tasks/p1/" + taskId.Hex() + "/members", user: "ana", role: "PROJECT_MEMBER", forwarded: true,
+			db:   func(t testing.TB) []bson.D { return []bson.D{inP1(t)} },
+			want: http.StatusNoContent,
+		},
+		{name: "call from another service", path: "/tasks/p2/" + taskId.Hex() + "/members", want: http.StatusNoContent},
+		{
+			name: "sprint by id", path: "/sprints/" + sprintId.Hex(), user: "ana", role: "PROJECT_MEMBER",
+			db: func(t testing.TB) []bson.D {
+				return []bson.D{found(t, "sprints", domain.Sprint{Id: sprintId, Project: "p1", Name: "Sprint 1"})}
+			},
+			want: http.StatusNoContent,
+		},
+		{
+			name: "sprint of another project", path: "/sprints/" + sprintId.Hex(), user: "ana", role: "PROJECT_MEMBER",
+			db: func(t testing.TB) []bson.D {
+				return []bson.D{found(t, "sprints", domain.Sprint{Id: sprintId, Project: "p2", Name: "Sprint 1"})}
+			},
+			want: http.StatusForbidden,
+		},
+		{name: "id not tied to a project", path: "/filters/" + primitive.NewObjectID().Hex() + "/tasks", user: "ana", role: "PROJECT_MEMBER", want: http.StatusNoContent},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			if tt.db != nil {
+				mt.AddMockResponses(tt.db(mt)...)
+			}
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.user != "" {
+				r.Header.Set("X-Test-User", tt.user)
+				r.Header.Set("X-Test-Role", tt.role)
+			}
+			if tt.forwarded {
+				r.Header.Set("X-Forwarded-Prefix", "/api/tasks")
+			}
+			w := httptest.NewRecorder()
+
+			newAccessRouter(newTestHandler(mt)).ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				mt.Fatalf("GET %s as %s = %d, want %d", tt.path, tt.user, w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibleTasks(t *testing.T) {
+	useProjectsService(t)
+	tasks := domain.Tasks{
+		{Name: "first of p1", Project: "p1"},
+		{Name: "of p2", Project: "p2"},
+		{Name: "of a deleted project", Project: "p9"},
+		{Name: "second of p1", Project: "p1"},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("member sees the tasks of their projects", func(mt *mtest.T) {
+		r := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		r = r.WithContext(context.WithValue(r.Context(), authorizationlib.UsernameKey, "ana"))
+		r = r.WithContext(context.WithValue(r.Context(), authorizationlib.RoleKey, "PROJECT_MEMBER"))
+
+		visible := newTestHandler(mt).visibleTasks(r, tasks)
+		if len(visible) != 2 || visible[0].Name != "first of p1" || visible[1].Name != "second of p1" {
+			mt.Fatalf("visibleTasks = %v, want the two tasks of p1", visible)
+		}
+	})
+	mt.Run("other services see every task", func(mt *mtest.T) {
+		r := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+
+		if visible := newTestHandler(mt).visibleTasks(r, tasks); len(visible) != len(tasks) {
+			mt.Fatalf("visibleTasks kept %d of %d tasks", len(visible), len(tasks))
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"strconv"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+)
+
+// multipartOverhead leaves room for the form fields around the file.
+const multipartOverhead = 1 << 20
+
+func (h *TaskHandler) AddAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.AddAttachment")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role := r.Context().Value(authorizationlib.RoleKey).(string)
+
+	if limit := h.tasks.MaxAttachmentSize(); limit > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, limit+multipartOverhead)
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeErrorResp(fmt.Errorf("%w: limit is %d bytes", domain.ErrAttachmentTooLarge(), h.tasks.MaxAttachmentSize()), w)
+			return
+		}
+		writeErrorResp(fmt.Errorf("%w: %v", domain.ErrInvalidAttachment(), err), w)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeErrorResp(fmt.Errorf("%w: missing file", domain.ErrInvalidAttachment()), w)
+		return
+	}
+	defer file.Close()
+
+	checksum := r.FormValue("checksum")
+	if checksum == "" {
+		checksum = r.Header.Get("X-Checksum-SHA256")
+	}
+
+	attachment, err := h.tasks.AddAttachment(ctx, mux.Vars(r)["taskId"], username, role, header.Filename, file, header.Size, checksum)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(attachment, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetAttachments")
+	defer span.End()
+
+	attachments, err := h.tasks.GetAttachments(ctx, mux.Vars(r)["taskId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = attachments.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.DownloadAttachment")
+	defer span.End()
+
+	attachment, content, err := h.tasks.OpenAttachment(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Checksum-SHA256", attachment.Checksum)
+
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("Error streaming attachment %s: %v\n", attachment.Id.Hex(), err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func (h *TaskHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.DeleteAttachment")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role := r.Context().Value(authorizationlib.RoleKey).(string)
+
+	if err := h.tasks.DeleteAttachment(ctx, mux.Vars(r)["id"], username, role); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
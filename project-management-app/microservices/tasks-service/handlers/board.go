@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) GetBoard(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetBoard")
+	defer span.End()
+
+	board, err := h.tasks.GetBoard(ctx, mux.Vars(r)["projectId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = board.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+// MoveTask moves a task on the board. An If-Match header with the task's
+// ETag makes the move fail with 412 when the task has been changed in the
+// meantime.
+func (h *TaskHandler) MoveTask(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.MoveTask")
+	defer span.End()
+
+	taskId := mux.Vars(r)["taskId"]
+	version, err := parseIfMatch(r.Header.Get("If-Match"), taskId)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role := r.Context().Value(authorizationlib.RoleKey).(string)
+
+	move := &domain.Move{}
+	if err := readReq(move, r, w); err != nil {
+		return
+	}
+
+	task, err := h.tasks.MoveTask(ctx, taskId, username, role, *move, version)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	writeResp(task, http.StatusOK, w)
+}
+
+// BulkMoveTasks moves several tasks to the bottom of a column. The versions
+// in the body work like If-Match for each task.
+func (h *TaskHandler) BulkMoveTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.BulkMoveTasks")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role := r.Context().Value(authorizationlib.RoleKey).(string)
+
+	bulk := &domain.BulkMove{}
+	if err := readReq(bulk, r, w); err != nil {
+		return
+	}
+
+	tasks, err := h.tasks.BulkMoveTasks(ctx, *bulk, username, role)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(tasks, http.StatusOK, w)
+}
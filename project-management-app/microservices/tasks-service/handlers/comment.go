@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetComments")
+	defer span.End()
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	size, _ := strconv.Atoi(query.Get("size"))
+
+	comments, err := h.tasks.GetComments(ctx, mux.Vars(r)["taskId"], page, size)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = comments.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) AddComment(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.AddComment")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	req := &struct {
+		Body   string `json:"body"`
+		Parent string `json:"parent"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	comment, err := h.tasks.AddComment(ctx, mux.Vars(r)["taskId"], req.Parent, username, req.Body)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(comment, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) EditComment(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.EditComment")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	req := &struct {
+		Body string `json:"body"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	comment, err := h.tasks.EditComment(ctx, mux.Vars(r)["id"], username, req.Body)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(comment, http.StatusOK, w)
+}
+
+func (h *TaskHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.DeleteComment")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	if err := h.tasks.DeleteComment(ctx, mux.Vars(r)["id"], username); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
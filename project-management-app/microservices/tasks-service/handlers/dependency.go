@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.AddDependency")
+	defer span.End()
+
+	taskId := mux.Vars(r)["taskId"]
+
+	req := &struct {
+		BlockedBy string `json:"blocked_by"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+	if req.BlockedBy == "" {
+		http.Error(w, "blocked_by is required", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.tasks.AddDependency(ctx, taskId, req.BlockedBy)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(task, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.RemoveDependency")
+	defer span.End()
+
+	vars := mux.Vars(r)
+
+	if _, err := h.tasks.RemoveDependency(ctx, vars["taskId"], vars["blockerId"]); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) GetDependencies(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetDependencies")
+	defer span.End()
+
+	dependencies, err := h.tasks.GetDependencies(ctx, mux.Vars(r)["taskId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = dependencies.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) GetDependencyGraph(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetDependencyGraph")
+	defer span.End()
+
+	graph, err := h.tasks.GetDependencyGraph(ctx, mux.Vars(r)["projectId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = graph.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
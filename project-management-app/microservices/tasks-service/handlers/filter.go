@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+	"time"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+)
+
+// QueryTasks filters tasks by the query parameters project, label, status,
+// state, assignee, sprint (a sprint id or "backlog"), q (text in name or
+// description) and the date ranges due_from/due_to and
+// created_from/created_to. List parameters can be repeated or comma
+// separated.
+func (h *TaskHandler) QueryTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.QueryTasks")
+	defer span.End()
+
+	query, err := parseTaskQuery(r.URL.Query())
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	tasks, err := h.tasks.QueryTasks(ctx, query)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	tasks = h.visibleTasks(r, tasks)
+
+	err = tasks.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) GetFilters(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetFilters")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	filters, err := h.tasks.GetFilters(ctx, username)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = filters.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) SaveFilter(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.SaveFilter")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	filter := &domain.SavedFilter{}
+	if err := filter.FromJSON(r.Body); err != nil {
+		http.Error(w, "Unable to decode json", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := h.tasks.SaveFilter(ctx, username, filter)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(filter, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) DeleteFilter(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.DeleteFilter")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	if err := h.tasks.DeleteFilter(ctx, username, mux.Vars(r)["id"]); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) RunFilter(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.RunFilter")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	tasks, err := h.tasks.RunFilter(ctx, username, mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	tasks = h.visibleTasks(r, tasks)
+
+	err = tasks.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseTaskQuery(values url.Values) (domain.TaskQuery, error) {
+	query := domain.TaskQuery{
+		Project:  values.Get("project"),
+		Labels:   listParam(values, "label"),
+		States:   listParam(values, "state"),
+		Assignee: values.Get("assignee"),
+		Sprint:   values.Get("sprint"),
+		Text:     strings.TrimSpace(values.Get("q")),
+	}
+
+	for _, name := range listParam(values, "status") {
+		status, err := domain.StatusFromString(strings.ToUpper(name))
+		if err != nil {
+			return query, fmt.Errorf("%w: unknown status %s", domain.ErrInvalidFilter(), name)
+		}
+		query.Statuses = append(query.Statuses, status)
+	}
+
+	dates := []struct {
+		param string
+		end   bool
+		into  **time.Time
+	}{
+		{"due_from", false, &query.DueFrom},
+		{"due_to", true, &query.DueTo},
+		{"created_from", false, &query.CreatedFrom},
+		{"created_to", true, &query.CreatedTo},
+	}
+	for _, date := range dates {
+		value, err := parseTimeParam(values.Get(date.param), date.end)
+		if err != nil {
+			return query, fmt.Errorf("%w: %s must be a date or an RFC 3339 time", domain.ErrInvalidFilter(), date.param)
+		}
+		*date.into = value
+	}
+	return query, nil
+}
+
+// listParam returns the values of a repeated or comma separated parameter.
+func listParam(values url.Values, name string) []string {
+	list := []string{}
+	for _, value := range values[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
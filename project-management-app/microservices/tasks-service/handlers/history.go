@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetHistory")
+	defer span.End()
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	size, _ := strconv.Atoi(query.Get("size"))
+
+	history, err := h.tasks.GetHistory(ctx, mux.Vars(r)["taskId"], page, size)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = history.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+// RevertTask reverts a task to the version in the body, which must be one of
+// the versions listed in its history. Other versions are answered with 400.
+func (h *TaskHandler) RevertTask(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.RevertTask")
+	defer span.End()
+
+	req := &struct {
+		Version int64 `json:"version"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	task, err := h.tasks.RevertTask(ctx, mux.Vars(r)["taskId"], req.Version)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(&task))
+	writeResp(task, http.StatusOK, w)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"project-management-app/microservices/projects-service/domain"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// maxImportSize bounds the size of an import file.
+const maxImportSize = 10 << 20
+
+// ImportTasks starts importing a file into a project. The multipart form
+// holds the file, its source (csv, jira or trello, csv by default for .csv
+// files), an optional JSON mapping like
+// {"columns": {"Summary": "name"}, "members": {"jdoe@example.com": "jdoe"}}
+// and dry_run=true to only check the rows. The job is returned right away
+// and can be followed on /imports/{id}.
+func (h *TaskHandler) ImportTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.ImportTasks")
+	defer span.End()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize+multipartOverhead)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeErrorResp(fmt.Errorf("%w: files are limited to %d bytes", domain.ErrInvalidImport(), maxImportSize), w)
+			return
+		}
+		writeErrorResp(fmt.Errorf("%w: %v", domain.ErrInvalidImport(), err), w)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeErrorResp(fmt.Errorf("%w: missing file", domain.ErrInvalidImport()), w)
+		return
+	}
+	defer file.Close()
+
+	source := strings.ToLower(r.FormValue("source"))
+	if source == "" && strings.EqualFold(filepath.Ext(header.Filename), ".csv") {
+		source = domain.IMPORT_CSV
+	}
+
+	var mapping domain.ImportMapping
+	if value := r.FormValue("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			writeErrorResp(fmt.Errorf("%w: malformed mapping: %v", domain.ErrInvalidImport(), err), w)
+			return
+		}
+	}
+
+	dryRun := false
+	if value := r.FormValue("dry_run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			writeErrorResp(fmt.Errorf("%w: dry_run must be true or false", domain.ErrInvalidImport()), w)
+			return
+		}
+	}
+
+	job, err := h.tasks.StartImport(ctx, mux.Vars(r)["projectId"], source, header.Filename, file, mapping, dryRun)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	writeResp(job, http.StatusAccepted, w)
+}
+
+func (h *TaskHandler) GetImportJobs(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetImportJobs")
+	defer span.End()
+
+	jobs, err := h.tasks.GetImportJobs(ctx, mux.Vars(r)["projectId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = jobs.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetImportJob returns the progress of an import and the results of the rows
+// processed so far.
+func (h *TaskHandler) GetImportJob(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetImportJob")
+	defer span.End()
+
+	job, err := h.tasks.GetImportJob(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = job.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) GetLabels(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetLabels")
+	defer span.End()
+
+	labels, err := h.tasks.GetLabels(ctx, mux.Vars(r)["projectId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = labels.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) CreateLabel(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.CreateLabel")
+	defer span.End()
+
+	label := &domain.Label{}
+	if err := label.FromJSON(r.Body); err != nil {
+		http.Error(w, "Unable to decode json", http.StatusBadRequest)
+		return
+	}
+
+	label, err := h.tasks.CreateLabel(ctx, mux.Vars(r)["projectId"], label)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(label, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) UpdateLabel(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.UpdateLabel")
+	defer span.End()
+
+	changes := &domain.Label{}
+	if err := changes.FromJSON(r.Body); err != nil {
+		http.Error(w, "Unable to decode json", http.StatusBadRequest)
+		return
+	}
+
+	label, err := h.tasks.UpdateLabel(ctx, mux.Vars(r)["id"], changes)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(label, http.StatusOK, w)
+}
+
+func (h *TaskHandler) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.DeleteLabel")
+	defer span.End()
+
+	if err := h.tasks.DeleteLabel(ctx, mux.Vars(r)["id"]); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) AddTaskLabel(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.AddTaskLabel")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role := r.Context().Value(authorizationlib.RoleKey).(string)
+
+	req := &struct {
+		Label string `json:"label"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	task, err := h.tasks.AddTaskLabel(ctx, mux.Vars(r)["taskId"], req.Label, username, role)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(task, http.StatusOK, w)
+}
+
+func (h *TaskHandler) RemoveTaskLabel(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.RemoveTaskLabel")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role := r.Context().Value(authorizationlib.RoleKey).(string)
+
+	vars := mux.Vars(r)
+	task, err := h.tasks.RemoveTaskLabel(ctx, vars["taskId"], vars["labelId"], username, role)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(task, http.StatusOK, w)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// UnassignProjectMembers is called by projects-service when members leave a
+// project, with {"usernames": [...]}.
+func (h *TaskHandler) UnassignProjectMembers(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.UnassignProjectMembers")
+	defer span.End()
+
+	req := &struct {
+		Usernames []string `json:"usernames"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	unassignment, err := h.tasks.UnassignProjectMembers(ctx, mux.Vars(r)["projectId"], req.Usernames)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(unassignment, http.StatusOK, w)
+}
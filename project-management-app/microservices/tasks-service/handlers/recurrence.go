@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// SetRecurrence makes a task recur. The rule is written like an iCalendar
+// RRULE, e.g. {"rule": "FREQ=WEEKLY;BYDAY=MO", "carry_assignees": true}.
+func (h *TaskHandler) SetRecurrence(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.SetRecurrence")
+	defer span.End()
+
+	req := &struct {
+		Rule           string `json:"rule"`
+		CarryAssignees bool   `json:"carry_assignees"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	task, err := h.tasks.SetRecurrence(ctx, mux.Vars(r)["taskId"], req.Rule, req.CarryAssignees)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	writeResp(task, http.StatusOK, w)
+}
+
+func (h *TaskHandler) ClearRecurrence(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.ClearRecurrence")
+	defer span.End()
+
+	task, err := h.tasks.ClearRecurrence(ctx, mux.Vars(r)["taskId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	writeResp(task, http.StatusOK, w)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type report interface {
+	ToJSON(w io.Writer) error
+	ToCSV(w io.Writer) error
+}
+
+// GetSnapshots returns the daily task counts of a project by status and by
+// workflow state, the data of a cumulative flow diagram. The range is given
+// with ?from= and ?to=, ?format=csv returns CSV.
+func (h *TaskHandler) GetSnapshots(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetSnapshots")
+	defer span.End()
+
+	from, to, ok := reportRangeParams(w, r)
+	if !ok {
+		return
+	}
+
+	projectId := mux.Vars(r)["projectId"]
+	snapshots, err := h.tasks.GetSnapshots(ctx, projectId, from, to)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	writeReport(w, r, "flow-"+projectId, snapshots)
+}
+
+// GetBurndown returns the burndown of a project over a range, or of the
+// sprint given with ?sprint=.
+func (h *TaskHandler) GetBurndown(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetBurndown")
+	defer span.End()
+
+	from, to, ok := reportRangeParams(w, r)
+	if !ok {
+		return
+	}
+
+	projectId := mux.Vars(r)["projectId"]
+	burndown, err := h.tasks.GetBurndown(ctx, projectId, r.URL.Query().Get("sprint"), from, to)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	writeReport(w, r, "burndown-"+projectId, burndown)
+}
+
+// GetVelocity returns the work completed in past periods, chosen with
+// ?by=sprint|week|month and ?periods=.
+func (h *TaskHandler) GetVelocity(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetVelocity")
+	defer span.End()
+
+	query := r.URL.Query()
+	periods, _ := strconv.Atoi(query.Get("periods"))
+
+	projectId := mux.Vars(r)["projectId"]
+	velocity, err := h.tasks.GetVelocity(ctx, projectId, query.Get("by"), periods)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	writeReport(w, r, "velocity-"+projectId, velocity)
+}
+
+func reportRangeParams(w http.ResponseWriter, r *http.Request) (*time.Time, *time.Time, bool) {
+	query := r.URL.Query()
+	from, err := parseTimeParam(query.Get("from"), false)
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	to, err := parseTimeParam(query.Get("to"), true)
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return from, to, true
+}
+
+// writeReport writes a report as JSON, or as CSV with ?format=csv.
+func writeReport(w http.ResponseWriter, r *http.Request, name string, report report) {
+	var err error
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=\""+name+".csv\"")
+		err = report.ToCSV(w)
+	} else {
+		err = report.ToJSON(w)
+	}
+	if err != nil {
+		log.Println("Unable to write report:", err)
+		http.Error(w, "Unable to write report", http.StatusInternalServerError)
+		return
+	}
+}
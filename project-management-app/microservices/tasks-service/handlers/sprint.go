@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) GetSprints(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetSprints")
+	defer span.End()
+
+	sprints, err := h.tasks.GetSprints(ctx, mux.Vars(r)["projectId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = sprints.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) CreateSprint(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.CreateSprint")
+	defer span.End()
+
+	sprint := &domain.Sprint{}
+	if err := sprint.FromJSON(r.Body); err != nil {
+		http.Error(w, "Unable to decode json", http.StatusBadRequest)
+		return
+	}
+
+	sprint, err := h.tasks.CreateSprint(ctx, mux.Vars(r)["projectId"], sprint)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(sprint, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) GetSprint(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetSprint")
+	defer span.End()
+
+	sprint, err := h.tasks.GetSprint(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = sprint.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) DeleteSprint(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.DeleteSprint")
+	defer span.End()
+
+	if err := h.tasks.DeleteSprint(ctx, mux.Vars(r)["id"]); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) StartSprint(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.StartSprint")
+	defer span.End()
+
+	sprint, err := h.tasks.StartSprint(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(sprint, http.StatusOK, w)
+}
+
+// CloseSprint closes a sprint, moving its unfinished tasks as given by
+// {"move_to": "backlog" | "next" | "<sprint id>"}. An empty body moves them
+// to the backlog.
+func (h *TaskHandler) CloseSprint(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.CloseSprint")
+	defer span.End()
+
+	req := &struct {
+		MoveTo string `json:"move_to"`
+	}{}
+	if r.ContentLength != 0 {
+		if err := readReq(req, r, w); err != nil {
+			return
+		}
+	}
+
+	sprint, err := h.tasks.CloseSprint(ctx, mux.Vars(r)["id"], req.MoveTo)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(sprint, http.StatusOK, w)
+}
+
+func (h *TaskHandler) GetSprintReport(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetSprintReport")
+	defer span.End()
+
+	report, err := h.tasks.GetSprintReport(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = report.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetTaskSprint puts a task into a sprint, {"sprint": ""} moves it back to
+// the backlog. If-Match is honoured like on updates.
+func (h *TaskHandler) SetTaskSprint(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.SetTaskSprint")
+	defer span.End()
+
+	taskId := mux.Vars(r)["taskId"]
+	version, err := parseIfMatch(r.Header.Get("If-Match"), taskId)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	req := &struct {
+		Sprint string `json:"sprint"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	task, err := h.tasks.SetTaskSprint(ctx, taskId, req.Sprint, version)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	writeResp(task, http.StatusOK, w)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) CreateSubtask(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.CreateSubtask")
+	defer span.End()
+
+	req := &struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		domain.Schedule
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	task, err := h.tasks.CreateSubtask(ctx, mux.Vars(r)["taskId"], req.Name, req.Description, req.Schedule)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(task, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) GetTaskTree(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetTaskTree")
+	defer span.End()
+
+	tree, err := h.tasks.GetTaskTree(ctx, mux.Vars(r)["taskId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(tree.Task))
+
+	err = tree.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.AddChecklistItem")
+	defer span.End()
+
+	req := &struct {
+		Text string `json:"text"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	task, err := h.tasks.AddChecklistItem(ctx, mux.Vars(r)["taskId"], req.Text)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(task, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) ToggleChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.ToggleChecklistItem")
+	defer span.End()
+
+	vars := mux.Vars(r)
+
+	task, err := h.tasks.ToggleChecklistItem(ctx, vars["taskId"], vars["itemId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(task, http.StatusOK, w)
+}
+
+func (h *TaskHandler) RemoveChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.RemoveChecklistItem")
+	defer span.End()
+
+	vars := mux.Vars(r)
+
+	if _, err := h.tasks.RemoveChecklistItem(ctx, vars["taskId"], vars["itemId"]); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.ReorderChecklist")
+	defer span.End()
+
+	req := &struct {
+		Items []string `json:"items"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	task, err := h.tasks.ReorderChecklist(ctx, mux.Vars(r)["taskId"], req.Items)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(task, http.StatusOK, w)
+}
+
+func (h *TaskHandler) ConvertChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.ConvertChecklistItem")
+	defer span.End()
+
+	vars := mux.Vars(r)
+
+	task, err := h.tasks.ConvertChecklistItem(ctx, vars["taskId"], vars["itemId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(task, http.StatusCreated, w)
+}
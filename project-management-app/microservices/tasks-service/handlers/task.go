@@ -10,6 +10,7 @@ import (
 	"project-management-app/microservices/projects-service/repositories"
 	"project-management-app/microservices/projects-service/services"
 	"strconv"
+	"time"
 
 	authorizationlib "github.com/Bijelic03/authorizationlibGo"
 	"github.com/gorilla/mux"
@@ -38,59 +39,111 @@ func (h TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Name        string        `json:"name"`
 		Description string        `json:"description"`
 		ProjectId   string        `json:"project"`
+		domain.Schedule
 	}{}
 
 	err := readReq(req, r, w)
 	if err != nil {
 		return
 	}
+	if err := h.checkAccess(r, req.ProjectId); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
 
-	task, err := h.tasks.Create(ctx, req.Status, req.Name, req.Description, req.ProjectId)
+	task, err := h.tasks.Create(ctx, req.Status, req.Name, req.Description, req.ProjectId, req.Schedule)
 	if err != nil {
 		writeErrorResp(err, w)
 		return
 	}
 
 	resp := struct {
-		Id          string `json:"id"`
-		ProjectId   string `json:"project"`
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		Status      string `json:"status"`
+		Id          string     `json:"id"`
+		Key         string     `json:"key"`
+		ProjectId   string     `json:"project"`
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		Status      string     `json:"status"`
+		State       string     `json:"state"`
+		DueDate     *time.Time `json:"due_date,omitempty"`
+		Priority    string     `json:"priority"`
+		StoryPoints float64    `json:"story_points"`
+		Estimate    float64    `json:"estimate_hours"`
 	}{
 		Id:          task.Id.Hex(),
+		Key:         task.Key,
 		ProjectId:   task.Project,
 		Name:        task.Name,
 		Description: task.Description,
 		Status:      strconv.Itoa(int(task.Status)),
+		State:       task.CurrentState(),
+		DueDate:     task.DueDate,
+		Priority:    string(task.Priority),
+		StoryPoints: task.StoryPoints,
+		Estimate:    task.EstimateHours,
 	}
 	writeResp(resp, http.StatusCreated, w)
 }
 
-func (h TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
-	req := &struct {
-		Id          string        `json:"id"`
-		Status      domain.Status `json:"status"`
-		Name        string        `json:"name"`
-		Description string        `json:"description"`
-		ProjectId   string        `json:"project"`
-	}{}
+// GetTask returns a task with its ETag, which Update accepts in If-Match.
+func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetTask")
+	defer span.End()
 
-	err := readReq(req, r, w)
+	task, err := h.tasks.GetTask(ctx, mux.Vars(r)["taskId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	err = task.ToJSON(w)
 	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Update changes only the fields present in the body. An If-Match header
+// with the task's ETag makes the update fail with 412 when the task has been
+// changed in the meantime.
+func (h TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
+	patch := &domain.TaskPatch{}
+	if err := patch.FromJSON(r.Body); err != nil {
 		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := parseIfMatch(r.Header.Get("If-Match"), patch.Id)
+	if err != nil {
+		writeErrorResp(err, w)
 		return
 	}
 
 	username := r.Context().Value(authorizationlib.UsernameKey).(string)
 	role := r.Context().Value(authorizationlib.RoleKey).(string)
 
+	projectId, err := h.tasks.ResourceProject(r.Context(), domain.RESOURCE_TASK, patch.Id)
+	if err == nil {
+		err = h.checkAccess(r, projectId)
+	}
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
 	if role == "PROJECT_MEMBER" {
-		task, err := h.repo.FindById(req.Id)
+		task, err := h.repo.FindById(patch.Id)
 		if err != nil {
 			writeErrorResp(err, w)
 			return
 		}
+		if task == nil {
+			writeErrorResp(errors.New("task not found"), w)
+			return
+		}
 
 		isMember := false
 		for _, member := range task.Members {
@@ -106,26 +159,39 @@ func (h TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	task, err := h.tasks.Update(req.Id, req.Status, req.Name, req.Description, req.ProjectId)
+	task, err := h.tasks.Update(r.Context(), *patch, version)
 	if err != nil {
 		writeErrorResp(err, w)
 		return
 	}
 
 	resp := struct {
-		Id          string `json:"id"`
-		ProjectId   string `json:"project"`
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		Status      string `json:"status"`
+		Id          string     `json:"id"`
+		Version     int64      `json:"version"`
+		ProjectId   string     `json:"project"`
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		Status      string     `json:"status"`
+		State       string     `json:"state"`
+		DueDate     *time.Time `json:"due_date,omitempty"`
+		Priority    string     `json:"priority"`
+		StoryPoints float64    `json:"story_points"`
+		Estimate    float64    `json:"estimate_hours"`
 	}{
 		Id:          task.Id.Hex(),
+		Version:     task.Version,
 		ProjectId:   task.Project,
 		Name:        task.Name,
 		Description: task.Description,
 		Status:      strconv.Itoa(int(task.Status)),
+		State:       task.CurrentState(),
+		DueDate:     task.DueDate,
+		Priority:    string(task.Priority),
+		StoryPoints: task.StoryPoints,
+		Estimate:    task.EstimateHours,
 	}
 
+	w.Header().Set("ETag", taskETag(&task))
 	writeResp(resp, http.StatusCreated, w)
 }
 
@@ -142,6 +208,15 @@ func (h *TaskHandler) FilterMembersNotOnTask(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Sa ?sort=workload najmanje opterećeni članovi su prvi
+	if r.URL.Query().Get("sort") == "workload" {
+		members, err = h.tasks.SortByWorkload(r.Context(), members)
+		if err != nil {
+			writeErrorResp(err, w)
+			return
+		}
+	}
+
 	// Vraćamo filtrirane članove kao JSON
 	err = members.ToJSON(w)
 	if err != nil {
@@ -152,6 +227,11 @@ func (h *TaskHandler) FilterMembersNotOnTask(w http.ResponseWriter, r *http.Requ
 }
 
 func (p *TaskHandler) GetAll(rw http.ResponseWriter, h *http.Request) {
+	if len(h.URL.Query()) > 0 {
+		p.QueryTasks(rw, h)
+		return
+	}
+
 	tasks, err := p.repo.GetAll()
 	if err != nil {
 		log.Print("Database exception: ", err)
@@ -160,6 +240,7 @@ func (p *TaskHandler) GetAll(rw http.ResponseWriter, h *http.Request) {
 	if tasks == nil {
 		return
 	}
+	tasks = p.visibleTasks(h, tasks)
 
 	err = tasks.ToJSON(rw)
 	if err != nil {
@@ -229,7 +310,7 @@ func (h TaskHandler) AddMember(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Pozivamo ProjectService da doda korisnika u projekat
-	err = h.tasks.AddMember(id, *user)
+	err = h.tasks.AddMember(r.Context(), id, *user)
 	if err != nil {
 		writeErrorResp(err, w)
 		return
@@ -262,7 +343,7 @@ func (h TaskHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.tasks.RemoveMember(taskId, *user)
+	err = h.tasks.RemoveMember(r.Context(), taskId, *user)
 	if err != nil {
 		writeErrorResp(err, w)
 		return
@@ -296,3 +377,25 @@ func (u *TaskHandler) ProjectContextMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(rw, h)
 	})
 }
+
+func (p *TaskHandler) GetProjectStats(rw http.ResponseWriter, h *http.Request) {
+	ctx, span := p.tracer.Start(h.Context(), "TasksHandler.GetProjectStats")
+	defer span.End()
+
+	projectId := mux.Vars(h)["projectId"]
+	days, _ := strconv.Atoi(h.URL.Query().Get("days"))
+
+	stats, err := p.tasks.GetProjectStats(ctx, projectId, days)
+	if err != nil {
+		log.Print("Database exception: ", err)
+		http.Error(rw, "Unable to get task statistics", http.StatusInternalServerError)
+		return
+	}
+
+	err = stats.ToJSON(rw)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(rw, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// GetTaskByKey finds a task of a project by its key, like PRJ-42.
+func (h *TaskHandler) GetTaskByKey(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetTaskByKey")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	task, err := h.tasks.GetTaskByKey(ctx, vars["projectId"], vars["key"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	err = task.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) GetKeySequence(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetKeySequence")
+	defer span.End()
+
+	sequence, err := h.tasks.GetKeySequence(ctx, mux.Vars(r)["projectId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = sequence.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetKeyPrefix changes the prefix of the task keys of a project, e.g.
+// {"prefix": "PRJ"}.
+func (h *TaskHandler) SetKeyPrefix(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.SetKeyPrefix")
+	defer span.End()
+
+	req := &struct {
+		Prefix string `json:"prefix"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	sequence, err := h.tasks.SetKeyPrefix(ctx, mux.Vars(r)["projectId"], req.Prefix)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	writeResp(sequence, http.StatusOK, w)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+)
+
+// GetTimeline returns the timeline of a project as JSON, as CSV with
+// ?format=csv or as an iCalendar file with ?format=ics. Tasks are events in
+// the calendar, or to-dos with ?component=vtodo.
+func (h *TaskHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetTimeline")
+	defer span.End()
+
+	projectId := mux.Vars(r)["projectId"]
+	timeline, err := h.tasks.GetTimeline(ctx, projectId)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "ics" {
+		component := domain.ICAL_EVENT
+		if strings.EqualFold(r.URL.Query().Get("component"), domain.ICAL_TODO) {
+			component = domain.ICAL_TODO
+		}
+		writeCalendar(w, "timeline-"+projectId, timeline, component)
+		return
+	}
+	writeReport(w, r, "timeline-"+projectId, timeline)
+}
+
+// GetCalendarFeed returns the URL calendar apps can subscribe to for the due
+// dates of the caller's tasks.
+func (h *TaskHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetCalendarFeed")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	feed, err := h.tasks.GetCalendarFeed(ctx, username)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	feed.URL = calendarURL(r, feed.Token)
+	err = feed.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ResetCalendarFeed replaces the caller's calendar URL, for when it was
+// shared by mistake.
+func (h *TaskHandler) ResetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.ResetCalendarFeed")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	feed, err := h.tasks.ResetCalendarFeed(ctx, username)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	feed.URL = calendarURL(r, feed.Token)
+	writeResp(feed, http.StatusOK, w)
+}
+
+// GetCalendar serves a calendar subscription. The token in the URL is the
+// only credential, calendar apps can't send one.
+func (h *TaskHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetCalendar")
+	defer span.End()
+
+	calendar, err := h.tasks.GetCalendar(ctx, mux.Vars(r)["token"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	writeCalendar(w, "tasks", calendar, domain.ICAL_EVENT)
+}
+
+func writeCalendar(w http.ResponseWriter, name string, timeline *domain.Timeline, component string) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+name+".ics\"")
+	if err := timeline.ToICal(w, component); err != nil {
+		log.Println("Unable to write calendar:", err)
+		http.Error(w, "Unable to write calendar", http.StatusInternalServerError)
+		return
+	}
+}
+
+// calendarURL builds the path of a calendar feed as seen through the API
+// gateway.
+func calendarURL(r *http.Request, token string) string {
+	return r.Header.Get("X-Forwarded-Prefix") + "/calendar/" + token + ".ics"
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.DeleteTask")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	if err := h.tasks.DeleteTask(ctx, mux.Vars(r)["taskId"], username); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetTrash")
+	defer span.End()
+
+	trash, err := h.tasks.GetTrash(ctx, mux.Vars(r)["projectId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = trash.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.RestoreTask")
+	defer span.End()
+
+	task, err := h.tasks.RestoreTask(ctx, mux.Vars(r)["taskId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(task, http.StatusOK, w)
+}
+
+func (h *TaskHandler) PurgeTask(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.PurgeTask")
+	defer span.End()
+
+	if err := h.tasks.PurgeTask(ctx, mux.Vars(r)["taskId"]); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"project-management-app/microservices/projects-service/domain"
+	"strconv"
 	"strings"
 )
 
@@ -20,6 +23,36 @@ func writeErrorResp(err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusNotFound)
 	case strings.Contains(err.Error(), "cannot remove member from a finished task"):
 		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvalidWorkflow()),
+		errors.Is(err, domain.ErrInvalidMention()),
+		errors.Is(err, domain.ErrInvalidAttachment()),
+		errors.Is(err, domain.ErrInvalidMove()),
+		errors.Is(err, domain.ErrInvalidLabel()),
+		errors.Is(err, domain.ErrInvalidFilter()),
+		errors.Is(err, domain.ErrInvalidRevision()),
+		errors.Is(err, domain.ErrInvalidRecurrence()),
+		errors.Is(err, domain.ErrInvalidSprint()),
+		errors.Is(err, domain.ErrInvalidReport()),
+		errors.Is(err, domain.ErrInvalidTaskKey()),
+		errors.Is(err, domain.ErrInvalidImport()),
+		errors.Is(err, domain.ErrInvalidInput()):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, domain.ErrAttachmentTooLarge()):
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	case errors.Is(err, domain.ErrTransitionForbidden()):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, domain.ErrTransitionNotAllowed()),
+		errors.Is(err, domain.ErrDependencyCycle()),
+		errors.Is(err, domain.ErrTaskBlocked()),
+		errors.Is(err, domain.ErrTimerRunning()),
+		errors.Is(err, domain.ErrRestoreConflict()),
+		errors.Is(err, domain.ErrLabelExists()),
+		errors.Is(err, domain.ErrFilterExists()),
+		errors.Is(err, domain.ErrSprintState()),
+		errors.Is(err, domain.ErrTaskNameExists()):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, domain.ErrVersionConflict()):
+		w.WriteHeader(http.StatusPreconditionFailed)
 	default:
 		log.Printf("Unexpected error: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -53,3 +86,31 @@ func readReq(req any, r *http.Request, w http.ResponseWriter) error {
 	}
 	return err
 }
+
+// taskETag identifies the version of a task.
+func taskETag(task *domain.Task) string {
+	return fmt.Sprintf("\"%s-%d\"", task.Id.Hex(), task.Version)
+}
+
+// parseIfMatch returns the version of the task required by an If-Match
+// header, nil when the header is missing or "*". The header holds the ETag of
+// the task or just its version. An ETag of another task never matches.
+func parseIfMatch(header string, taskId string) (*int64, error) {
+	header = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(header), "W/"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tag := strings.Trim(header, "\"")
+	if i := strings.LastIndex(tag, "-"); i >= 0 {
+		if tag[:i] != taskId {
+			return nil, fmt.Errorf("%w: If-Match names another task", domain.ErrVersionConflict())
+		}
+		tag = tag[i+1:]
+	}
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 0 {
+		return nil, fmt.Errorf("%w: malformed If-Match header", domain.ErrInvalidInput())
+	}
+	return &version, nil
+}
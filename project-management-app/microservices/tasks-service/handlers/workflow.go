@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetWorkflow")
+	defer span.End()
+
+	projectId := mux.Vars(r)["projectId"]
+
+	workflow, err := h.tasks.GetWorkflow(ctx, projectId)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = workflow.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) SaveWorkflow(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.SaveWorkflow")
+	defer span.End()
+
+	projectId := mux.Vars(r)["projectId"]
+
+	workflow := &domain.Workflow{}
+	if err := workflow.FromJSON(r.Body); err != nil {
+		http.Error(w, "Unable to decode json", http.StatusBadRequest)
+		return
+	}
+
+	workflow, err := h.tasks.SaveWorkflow(ctx, projectId, workflow)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = workflow.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+)
+
+// GetWorkload returns the open work of the users given with ?users= across
+// all projects, least loaded first. It covers every project, so only other
+// services may call it, never users through the gateway.
+func (h *TaskHandler) GetWorkload(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetWorkload")
+	defer span.End()
+
+	workloads, err := h.tasks.GetWorkload(ctx, listParam(r.URL.Query(), "users"))
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = workloads.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
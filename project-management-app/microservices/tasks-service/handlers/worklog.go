@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/gorilla/mux"
+)
+
+func (h *TaskHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.StartTimer")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role := r.Context().Value(authorizationlib.RoleKey).(string)
+
+	worklog, err := h.tasks.StartTimer(ctx, mux.Vars(r)["taskId"], username, role)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(worklog, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.StopTimer")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	worklog, err := h.tasks.StopTimer(ctx, mux.Vars(r)["taskId"], username)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(worklog, http.StatusOK, w)
+}
+
+func (h *TaskHandler) GetRunningTimer(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetRunningTimer")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+
+	worklog, err := h.tasks.GetRunningTimer(ctx, username)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+	if worklog == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	err = worklog.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) LogWork(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.LogWork")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role := r.Context().Value(authorizationlib.RoleKey).(string)
+
+	req := &struct {
+		Start   time.Time `json:"start"`
+		Minutes int       `json:"minutes"`
+		Note    string    `json:"note"`
+	}{}
+	if err := readReq(req, r, w); err != nil {
+		return
+	}
+
+	worklog, err := h.tasks.LogWork(ctx, mux.Vars(r)["taskId"], username, role, req.Start, req.Minutes, req.Note)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	writeResp(worklog, http.StatusCreated, w)
+}
+
+func (h *TaskHandler) DeleteWorklog(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.DeleteWorklog")
+	defer span.End()
+
+	username := r.Context().Value(authorizationlib.UsernameKey).(string)
+	role := r.Context().Value(authorizationlib.RoleKey).(string)
+
+	if err := h.tasks.DeleteWorklog(ctx, mux.Vars(r)["id"], username, role); err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) GetTaskTime(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetTaskTime")
+	defer span.End()
+
+	summary, err := h.tasks.GetTaskTime(ctx, mux.Vars(r)["taskId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = summary.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TaskHandler) GetProjectTime(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.GetProjectTime")
+	defer span.End()
+
+	summary, err := h.tasks.GetProjectTime(ctx, mux.Vars(r)["projectId"])
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	err = summary.ToJSON(w)
+	if err != nil {
+		log.Println("Unable to convert to json:", err)
+		http.Error(w, "Unable to convert to json", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ExportWorklogs exports the worklogs of a project as JSON or, with
+// ?format=csv, as CSV. The range is given with ?from= and ?to=, either as
+// dates (both inclusive) or RFC 3339 timestamps.
+func (h *TaskHandler) ExportWorklogs(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "TasksHandler.ExportWorklogs")
+	defer span.End()
+
+	query := r.URL.Query()
+	from, err := parseTimeParam(query.Get("from"), false)
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(query.Get("to"), true)
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	projectId := mux.Vars(r)["projectId"]
+	worklogs, err := h.tasks.ExportWorklogs(ctx, projectId, from, to)
+	if err != nil {
+		writeErrorResp(err, w)
+		return
+	}
+
+	if query.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"worklogs-"+projectId+".csv\"")
+		err = worklogs.ToCSV(w)
+	} else {
+		err = worklogs.ToJSON(w)
+	}
+	if err != nil {
+		log.Println("Unable to write worklogs:", err)
+		http.Error(w, "Unable to write worklogs", http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseTimeParam parses a date or RFC 3339 query parameter. A plain date used
+// as the end of a range includes the whole day.
+func parseTimeParam(value string, end bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
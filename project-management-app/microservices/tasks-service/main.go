@@ -11,6 +11,7 @@ import (
 	"project-management-app/microservices/projects-service/handlers"
 	"project-management-app/microservices/projects-service/repositories"
 	"project-management-app/microservices/projects-service/services"
+	"project-management-app/microservices/projects-service/storage"
 	"project-management-app/microservices/projects-service/config"
 
 
@@ -56,7 +57,60 @@ func main() {
 	taskRepository, err := repositories.NewTaskRepo(timeoutContext, storeLogger, tracer)
 	handleErr(err)
 
-	taskService := services.NewTaskService(taskRepository , tracer)
+	blobs, err := storage.New(cfg.Storage)
+	handleErr(err)
+
+	taskService := services.NewTaskService(taskRepository , tracer, blobs, services.AttachmentLimits{
+		MaxSize:      cfg.Storage.MaxSize,
+		AllowedTypes: cfg.Storage.AllowedTypes,
+	}, cfg.TrashRetention)
+
+	// Periodicno trajno brisanje zadataka iz korpe
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			purged, err := taskService.PurgeExpiredTasks(context.Background())
+			if err != nil {
+				log.Println("Error purging trash:", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d tasks from trash\n", purged)
+			}
+		}
+	}()
+
+	// Generisanje sledecih instanci ponavljajucih zadataka
+	go func() {
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			generated, err := taskService.GenerateDueRecurrences(context.Background())
+			if err != nil {
+				log.Println("Error generating recurring tasks:", err)
+			}
+			if generated > 0 {
+				log.Printf("Generated %d recurring task instances\n", generated)
+			}
+		}
+	}()
+
+	// Kljucevi postojecih zadataka i jedinstveni indeksi, pre prvog zahteva
+	err = taskService.PrepareTaskKeys(context.Background())
+	handleErr(err)
+
+	// Najvise jedan aktivan sprint po projektu
+	err = taskRepository.CreateSprintIndexes(context.Background())
+	handleErr(err)
+
+	// Uvozi prekinuti restartom servisa
+	go func() {
+		failed, err := taskService.FailInterruptedImports(context.Background())
+		if err != nil {
+			log.Println("Error failing interrupted imports:", err)
+		} else if failed > 0 {
+			log.Printf("Failed %d interrupted imports\n", failed)
+		}
+	}()
 
 	taskHandler := handlers.NewTaskHandler(taskService, taskRepository, tracer)
 
@@ -70,16 +124,23 @@ func main() {
 
 	privateRouter := router.NewRoute().Subrouter()
 	privateRouter.Use(authHandler.MiddlewareAuth)
+	privateRouter.Use(taskHandler.MiddlewareProjectAccess)
 
 	managerRouter := router.NewRoute().Subrouter()
 	managerRouter.Use(authHandler.MiddlewareAuthManager)
+	managerRouter.Use(taskHandler.MiddlewareProjectAccess)
 
 	getRouter := router.Methods(http.MethodGet).Subrouter()
+	getRouter.Use(taskHandler.MiddlewareGatewayAuth(authHandler.MiddlewareAuth))
+
+	internalRouter := router.NewRoute().Subrouter()
+	internalRouter.Use(taskHandler.MiddlewareInternal)
 
 	// Dodajemo GET rute ovde
 	getRouter.HandleFunc("/tasks/{id}", taskHandler.GetTasksByProject)
 	getRouter.HandleFunc("/tasks", taskHandler.GetAll)
 	getRouter.HandleFunc("/tasks/members/{id}", taskHandler.GetMembersByID)
+	getRouter.HandleFunc("/tasks/{projectId}/stats", taskHandler.GetProjectStats)
 	getRouter.HandleFunc("/tasks/{projectId}/{taskId}/members", taskHandler.FilterMembersNotOnTask)
 
 	// POST subrouter
@@ -101,6 +162,119 @@ func main() {
 	deleteRouter := privateRouter.Methods(http.MethodDelete).Subrouter()
 	deleteRouter.HandleFunc("/users/{taskId}", taskHandler.RemoveMember)
 
+	// Jedan zadatak sa ETag zaglavljem za If-Match
+	privateRouter.HandleFunc("/tasks/task/{taskId}", taskHandler.GetTask).Methods(http.MethodGet)
+
+	// Zavisnosti izmedju zadataka
+	privateRouter.HandleFunc("/tasks/{taskId}/dependencies", taskHandler.GetDependencies).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/tasks/{taskId}/dependencies", taskHandler.AddDependency).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/tasks/{taskId}/dependencies/{blockerId}", taskHandler.RemoveDependency).Methods(http.MethodDelete)
+	privateRouter.HandleFunc("/tasks/{projectId}/graph", taskHandler.GetDependencyGraph).Methods(http.MethodGet)
+
+	// Podzadaci i checkliste
+	privateRouter.HandleFunc("/tasks/{taskId}/subtasks", taskHandler.GetTaskTree).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/tasks/{taskId}/subtasks", taskHandler.CreateSubtask).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/tasks/{taskId}/checklist", taskHandler.AddChecklistItem).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/tasks/{taskId}/checklist", taskHandler.ReorderChecklist).Methods(http.MethodPut)
+	privateRouter.HandleFunc("/tasks/{taskId}/checklist/{itemId}", taskHandler.RemoveChecklistItem).Methods(http.MethodDelete)
+	privateRouter.HandleFunc("/tasks/{taskId}/checklist/{itemId}/toggle", taskHandler.ToggleChecklistItem).Methods(http.MethodPatch)
+	managerRouter.HandleFunc("/tasks/{taskId}/checklist/{itemId}/subtask", taskHandler.ConvertChecklistItem).Methods(http.MethodPost)
+
+	// Procene i evidencija vremena
+	privateRouter.HandleFunc("/timer", taskHandler.GetRunningTimer).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/tasks/{taskId}/timer/start", taskHandler.StartTimer).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/tasks/{taskId}/timer/stop", taskHandler.StopTimer).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/tasks/{taskId}/worklogs", taskHandler.GetTaskTime).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/tasks/{taskId}/worklogs", taskHandler.LogWork).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/worklogs/{id}", taskHandler.DeleteWorklog).Methods(http.MethodDelete)
+	privateRouter.HandleFunc("/worklogs/project/{projectId}", taskHandler.GetProjectTime).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/worklogs/project/{projectId}/export", taskHandler.ExportWorklogs).Methods(http.MethodGet)
+
+	// Komentari
+	privateRouter.HandleFunc("/tasks/{taskId}/comments", taskHandler.GetComments).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/tasks/{taskId}/comments", taskHandler.AddComment).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/comments/{id}", taskHandler.EditComment).Methods(http.MethodPatch)
+	privateRouter.HandleFunc("/comments/{id}", taskHandler.DeleteComment).Methods(http.MethodDelete)
+
+	// Attachment rute
+	privateRouter.HandleFunc("/tasks/{taskId}/attachments", taskHandler.GetAttachments).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/tasks/{taskId}/attachments", taskHandler.AddAttachment).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/attachments/{id}", taskHandler.DownloadAttachment).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/attachments/{id}", taskHandler.DeleteAttachment).Methods(http.MethodDelete)
+
+	// Brisanje i vracanje zadataka
+	managerRouter.HandleFunc("/tasks/{taskId}", taskHandler.DeleteTask).Methods(http.MethodDelete)
+	privateRouter.HandleFunc("/tasks/{projectId}/trash", taskHandler.GetTrash).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/tasks/{taskId}/restore", taskHandler.RestoreTask).Methods(http.MethodPost)
+	managerRouter.HandleFunc("/tasks/{taskId}/purge", taskHandler.PurgeTask).Methods(http.MethodDelete)
+
+	// Kanban tabla
+	privateRouter.HandleFunc("/board/{projectId}", taskHandler.GetBoard).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/board/move", taskHandler.BulkMoveTasks).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/tasks/{taskId}/move", taskHandler.MoveTask).Methods(http.MethodPost)
+
+	// Labele i sacuvani filteri
+	privateRouter.HandleFunc("/labels/project/{projectId}", taskHandler.GetLabels).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/labels/project/{projectId}", taskHandler.CreateLabel).Methods(http.MethodPost)
+	managerRouter.HandleFunc("/labels/{id}", taskHandler.UpdateLabel).Methods(http.MethodPatch)
+	managerRouter.HandleFunc("/labels/{id}", taskHandler.DeleteLabel).Methods(http.MethodDelete)
+	privateRouter.HandleFunc("/tasks/{taskId}/labels", taskHandler.AddTaskLabel).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/tasks/{taskId}/labels/{labelId}", taskHandler.RemoveTaskLabel).Methods(http.MethodDelete)
+	privateRouter.HandleFunc("/filters", taskHandler.GetFilters).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/filters", taskHandler.SaveFilter).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/filters/{id}", taskHandler.DeleteFilter).Methods(http.MethodDelete)
+	privateRouter.HandleFunc("/filters/{id}/tasks", taskHandler.RunFilter).Methods(http.MethodGet)
+
+	// Istorija izmena zadatka
+	privateRouter.HandleFunc("/tasks/{taskId}/history", taskHandler.GetHistory).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/tasks/{taskId}/revert", taskHandler.RevertTask).Methods(http.MethodPost)
+
+	// Ponavljajuci zadaci
+	managerRouter.HandleFunc("/tasks/{taskId}/recurrence", taskHandler.SetRecurrence).Methods(http.MethodPut)
+	managerRouter.HandleFunc("/tasks/{taskId}/recurrence", taskHandler.ClearRecurrence).Methods(http.MethodDelete)
+
+	// Sprintovi
+	privateRouter.HandleFunc("/sprints/project/{projectId}", taskHandler.GetSprints).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/sprints/project/{projectId}", taskHandler.CreateSprint).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/sprints/{id}", taskHandler.GetSprint).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/sprints/{id}", taskHandler.DeleteSprint).Methods(http.MethodDelete)
+	managerRouter.HandleFunc("/sprints/{id}/start", taskHandler.StartSprint).Methods(http.MethodPost)
+	managerRouter.HandleFunc("/sprints/{id}/close", taskHandler.CloseSprint).Methods(http.MethodPost)
+	privateRouter.HandleFunc("/sprints/{id}/report", taskHandler.GetSprintReport).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/tasks/{taskId}/sprint", taskHandler.SetTaskSprint).Methods(http.MethodPut)
+
+	// Izvestaji o napretku
+	privateRouter.HandleFunc("/reports/project/{projectId}/flow", taskHandler.GetSnapshots).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/reports/project/{projectId}/burndown", taskHandler.GetBurndown).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/reports/project/{projectId}/velocity", taskHandler.GetVelocity).Methods(http.MethodGet)
+
+	// Kljucevi zadataka
+	privateRouter.HandleFunc("/tasks/project/{projectId}/keys", taskHandler.GetKeySequence).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/tasks/project/{projectId}/keys", taskHandler.SetKeyPrefix).Methods(http.MethodPut)
+	privateRouter.HandleFunc("/tasks/project/{projectId}/keys/{key}", taskHandler.GetTaskByKey).Methods(http.MethodGet)
+
+	// Vremenska linija i kalendar
+	privateRouter.HandleFunc("/timeline/project/{projectId}", taskHandler.GetTimeline).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/calendar/feed", taskHandler.GetCalendarFeed).Methods(http.MethodGet)
+	privateRouter.HandleFunc("/calendar/feed/reset", taskHandler.ResetCalendarFeed).Methods(http.MethodPost)
+	router.HandleFunc("/calendar/{token:[0-9a-f]+}.ics", taskHandler.GetCalendar).Methods(http.MethodGet)
+
+	// Uvoz zadataka
+	managerRouter.HandleFunc("/imports/project/{projectId}", taskHandler.ImportTasks).Methods(http.MethodPost)
+	managerRouter.HandleFunc("/imports/project/{projectId}", taskHandler.GetImportJobs).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/imports/{id}", taskHandler.GetImportJob).Methods(http.MethodGet)
+
+	// Poziva projects-service kada clan napusti projekat
+	router.Handle("/tasks/project/{projectId}/unassign", taskHandler.MiddlewareGatewayAuth(authHandler.MiddlewareAuthManager)(
+		http.HandlerFunc(taskHandler.UnassignProjectMembers))).Methods(http.MethodPost)
+
+	// Poziva users-service za sortiranje korisnika po opterecenju
+	internalRouter.HandleFunc("/workload", taskHandler.GetWorkload).Methods(http.MethodGet)
+
+	// Workflow rute
+	privateRouter.HandleFunc("/workflows/{projectId}", taskHandler.GetWorkflow).Methods(http.MethodGet)
+	managerRouter.HandleFunc("/workflows/{projectId}", taskHandler.SaveWorkflow).Methods(http.MethodPut)
+
 	// Middleware za deserializaciju korisničkih podataka, primenjen samo na PATCH i POST rute gde je potrebno
 	// patchRouter.Use(taskHandler.ProjectContextMiddleware)
 
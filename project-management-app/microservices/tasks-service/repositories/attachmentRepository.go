@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getAttachmentCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	attachmentsCollection := taskDatabase.Collection("attachments")
+	return attachmentsCollection
+}
+
+func (pr *TaskRepo) InsertAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.InsertAttachment")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getAttachmentCollection().InsertOne(ctx, attachment); err != nil {
+		pr.logger.Println("Error inserting attachment:", err)
+		return err
+	}
+	return nil
+}
+
+func (pr *TaskRepo) GetAttachmentById(ctx context.Context, id string) (*domain.Attachment, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetAttachmentById")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	var attachment domain.Attachment
+	err = pr.getAttachmentCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&attachment)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println("Error fetching attachment:", err)
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// GetAttachmentsByTask returns the attachments of a task, oldest first.
+func (pr *TaskRepo) GetAttachmentsByTask(ctx context.Context, taskId string) (domain.Attachments, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetAttachmentsByTask")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getAttachmentCollection().Find(ctx, bson.M{"task": taskId}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		pr.logger.Println("Error fetching attachments:", err)
+		return nil, err
+	}
+
+	attachments := domain.Attachments{}
+	if err = cursor.All(ctx, &attachments); err != nil {
+		pr.logger.Println("Error decoding attachments:", err)
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (pr *TaskRepo) DeleteAttachment(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.DeleteAttachment")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getAttachmentCollection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		pr.logger.Println("Error deleting attachment:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MoveTasks stores the board position and state of tasks, in order. Each
+// task is written with a single update at the version it was read at, so its
+// state and rank always change together and never over a concurrent change.
+// Writing stops at the first task that has changed, with
+// domain.ErrVersionConflict. It returns how many tasks were moved.
+func (pr *TaskRepo) MoveTasks(ctx context.Context, tasks domain.Tasks) (int, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.MoveTasks")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	for i, task := range tasks {
+		result, err := pr.getCollection().UpdateOne(ctx,
+			atVersion(active(bson.M{"_id": task.Id}), task.Version),
+			bson.M{"$set": bson.M{
+				"state":       task.State,
+				"status":      task.Status,
+				"rank":        task.Rank,
+				"finished_at": task.FinishedAt,
+			}, "$inc": bson.M{"version": 1}},
+		)
+		if err != nil {
+			pr.logger.Println("Error moving tasks:", err)
+			return i, err
+		}
+		if result.MatchedCount == 0 {
+			return i, fmt.Errorf("%w: task %s was changed in the meantime", domain.ErrVersionConflict(), task.Name)
+		}
+	}
+	return len(tasks), nil
+}
+
+// SetRanks sets the ranks of tasks on the board.
+func (pr *TaskRepo) SetRanks(ctx context.Context, ranks map[primitive.ObjectID]string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetRanks")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	models := []mongo.WriteModel{}
+	for id, rank := range ranks {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{"rank": rank}}))
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	if _, err := pr.getCollection().BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		pr.logger.Println("Error ranking tasks:", err)
+		return err
+	}
+	return nil
+}
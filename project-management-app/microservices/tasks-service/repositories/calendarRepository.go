@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getCalendarCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	calendarsCollection := taskDatabase.Collection("calendar_feeds")
+	return calendarsCollection
+}
+
+func (pr *TaskRepo) GetCalendarFeed(ctx context.Context, username string) (*domain.CalendarFeed, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetCalendarFeed")
+	defer span.End()
+
+	return pr.findCalendarFeed(ctx, bson.M{"_id": username})
+}
+
+func (pr *TaskRepo) GetCalendarFeedByToken(ctx context.Context, token string) (*domain.CalendarFeed, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetCalendarFeedByToken")
+	defer span.End()
+
+	return pr.findCalendarFeed(ctx, bson.M{"token": token})
+}
+
+func (pr *TaskRepo) findCalendarFeed(ctx context.Context, filter bson.M) (*domain.CalendarFeed, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var feed domain.CalendarFeed
+	err := pr.getCalendarCollection().FindOne(ctx, filter).Decode(&feed)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		pr.logger.Println("Error finding calendar feed:", err)
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// SaveCalendarFeed stores the feed of a user, replacing the previous token.
+func (pr *TaskRepo) SaveCalendarFeed(ctx context.Context, feed *domain.CalendarFeed) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SaveCalendarFeed")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCalendarCollection().ReplaceOne(ctx, bson.M{"_id": feed.Username}, feed, options.Replace().SetUpsert(true))
+	if err != nil {
+		pr.logger.Println("Error saving calendar feed:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AddChecklistItem appends an item to the checklist of a task.
+func (pr *TaskRepo) AddChecklistItem(ctx context.Context, taskId primitive.ObjectID, item domain.ChecklistItem) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.AddChecklistItem")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().UpdateOne(ctx, bson.M{"_id": taskId}, bson.M{"$push": bson.M{"checklist": item}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		pr.logger.Println("Error adding checklist item:", err)
+		return err
+	}
+	return nil
+}
+
+// SetChecklistItemDone marks a checklist item as done or not done.
+func (pr *TaskRepo) SetChecklistItemDone(ctx context.Context, taskId primitive.ObjectID, itemId primitive.ObjectID, done bool) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetChecklistItemDone")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := pr.getCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": taskId, "checklist.id": itemId},
+		bson.M{"$set": bson.M{"checklist.$.done": done}, "$inc": bson.M{"version": 1}},
+	)
+	if err != nil {
+		pr.logger.Println("Error updating checklist item:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("checklist item not found")
+	}
+	return nil
+}
+
+// RemoveChecklistItem removes an item from the checklist of a task.
+func (pr *TaskRepo) RemoveChecklistItem(ctx context.Context, taskId primitive.ObjectID, itemId primitive.ObjectID) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.RemoveChecklistItem")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := pr.getCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": taskId, "checklist.id": itemId},
+		bson.M{"$pull": bson.M{"checklist": bson.M{"id": itemId}}, "$inc": bson.M{"version": 1}},
+	)
+	if err != nil {
+		pr.logger.Println("Error removing checklist item:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("checklist item not found")
+	}
+	return nil
+}
+
+// SetChecklist replaces the checklist of a task. It is used to reorder the
+// items, so it only succeeds while the task still has exactly these items.
+func (pr *TaskRepo) SetChecklist(ctx context.Context, taskId primitive.ObjectID, items []domain.ChecklistItem) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetChecklist")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ids := bson.A{}
+	for _, item := range items {
+		ids = append(ids, item.Id)
+	}
+
+	result, err := pr.getCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": taskId, "checklist.id": bson.M{"$all": ids}, "checklist": bson.M{"$size": len(items)}},
+		bson.M{"$set": bson.M{"checklist": items}, "$inc": bson.M{"version": 1}},
+	)
+	if err != nil {
+		pr.logger.Println("Error reordering checklist:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("checklist changed, reorder not applied")
+	}
+	return nil
+}
+
+// GetSubtasks returns the direct subtasks of a task.
+func (pr *TaskRepo) GetSubtasks(ctx context.Context, parentId string) (domain.Tasks, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetSubtasks")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getCollection().Find(ctx, active(bson.M{"parent": parentId}))
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	tasks := domain.Tasks{}
+	if err = cursor.All(ctx, &tasks); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return tasks, nil
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getCommentCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	commentsCollection := taskDatabase.Collection("comments")
+	return commentsCollection
+}
+
+func (pr *TaskRepo) InsertComment(ctx context.Context, comment *domain.Comment) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.InsertComment")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getCommentCollection().InsertOne(ctx, comment); err != nil {
+		pr.logger.Println("Error inserting comment:", err)
+		return err
+	}
+	return nil
+}
+
+func (pr *TaskRepo) GetCommentById(ctx context.Context, id string) (*domain.Comment, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetCommentById")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	var comment domain.Comment
+	err = pr.getCommentCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&comment)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println("Error fetching comment:", err)
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// UpdateComment stores the edited body and mentions of a comment, or marks it
+// as deleted.
+func (pr *TaskRepo) UpdateComment(ctx context.Context, comment *domain.Comment) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.UpdateComment")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"body":      comment.Body,
+		"mentions":  comment.Mentions,
+		"deleted":   comment.Deleted,
+		"edited_at": comment.EditedAt,
+	}}
+	if _, err := pr.getCommentCollection().UpdateOne(ctx, bson.M{"_id": comment.Id}, update); err != nil {
+		pr.logger.Println("Error updating comment:", err)
+		return err
+	}
+	return nil
+}
+
+// GetCommentThreads returns one page of the top-level comments of a task,
+// oldest first, each with all of its replies.
+func (pr *TaskRepo) GetCommentThreads(ctx context.Context, taskId string, page int, size int) (*domain.CommentPage, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetCommentThreads")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	commentsCollection := pr.getCommentCollection()
+	query := bson.M{"task": taskId, "parent": bson.M{"$exists": false}}
+
+	total, err := commentsCollection.CountDocuments(ctx, query)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetSkip(int64((page - 1) * size)).
+		SetLimit(int64(size))
+	cursor, err := commentsCollection.Find(ctx, query, opts)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	comments := domain.Comments{}
+	if err = cursor.All(ctx, &comments); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	threads := []*domain.CommentThread{}
+	byId := map[string]*domain.CommentThread{}
+	parents := bson.A{}
+	for _, comment := range comments {
+		thread := &domain.CommentThread{Comment: comment, Replies: domain.Comments{}}
+		threads = append(threads, thread)
+		byId[comment.Id.Hex()] = thread
+		parents = append(parents, comment.Id.Hex())
+	}
+
+	if len(parents) > 0 {
+		cursor, err = commentsCollection.Find(ctx, bson.M{"parent": bson.M{"$in": parents}}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+		if err != nil {
+			pr.logger.Println(err)
+			return nil, err
+		}
+		replies := domain.Comments{}
+		if err = cursor.All(ctx, &replies); err != nil {
+			pr.logger.Println(err)
+			return nil, err
+		}
+		for _, reply := range replies {
+			if thread, ok := byId[reply.Parent]; ok {
+				thread.Replies = append(thread.Replies, reply)
+			}
+		}
+	}
+
+	return &domain.CommentPage{Items: threads, Total: total, Page: page, Size: size}, nil
+}
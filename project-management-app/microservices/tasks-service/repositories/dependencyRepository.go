@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AddDependency marks a task as blocked by another task.
+func (pr *TaskRepo) AddDependency(ctx context.Context, taskId primitive.ObjectID, blockerId string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.AddDependency")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().UpdateOne(ctx, bson.M{"_id": taskId}, bson.M{"$addToSet": bson.M{"blocked_by": blockerId}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		pr.logger.Println("Error adding dependency:", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveDependency removes a blocker from a task.
+func (pr *TaskRepo) RemoveDependency(ctx context.Context, taskId primitive.ObjectID, blockerId string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.RemoveDependency")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().UpdateOne(ctx, bson.M{"_id": taskId}, bson.M{"$pull": bson.M{"blocked_by": blockerId}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		pr.logger.Println("Error removing dependency:", err)
+		return err
+	}
+	return nil
+}
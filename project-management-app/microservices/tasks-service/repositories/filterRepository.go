@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getFilterCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	filtersCollection := taskDatabase.Collection("filters")
+	return filtersCollection
+}
+
+func (pr *TaskRepo) InsertFilter(ctx context.Context, filter *domain.SavedFilter) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.InsertFilter")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getFilterCollection().InsertOne(ctx, filter); err != nil {
+		pr.logger.Println("Error inserting filter:", err)
+		return err
+	}
+	return nil
+}
+
+// GetFilter returns a saved filter of a user, nil if the user has no filter
+// with that id.
+func (pr *TaskRepo) GetFilter(ctx context.Context, owner string, id string) (*domain.SavedFilter, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetFilter")
+	defer span.End()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+	return pr.findFilter(ctx, bson.M{"_id": objID, "owner": owner})
+}
+
+func (pr *TaskRepo) GetFilterByName(ctx context.Context, owner string, name string) (*domain.SavedFilter, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetFilterByName")
+	defer span.End()
+
+	return pr.findFilter(ctx, bson.M{"owner": owner, "name": name})
+}
+
+func (pr *TaskRepo) findFilter(ctx context.Context, query bson.M) (*domain.SavedFilter, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var filter domain.SavedFilter
+	err := pr.getFilterCollection().FindOne(ctx, query).Decode(&filter)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println("Error fetching filter:", err)
+		return nil, err
+	}
+	return &filter, nil
+}
+
+// GetFilters returns the saved filters of a user ordered by name.
+func (pr *TaskRepo) GetFilters(ctx context.Context, owner string) (domain.SavedFilters, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetFilters")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getFilterCollection().Find(ctx, bson.M{"owner": owner}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	filters := domain.SavedFilters{}
+	if err = cursor.All(ctx, &filters); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return filters, nil
+}
+
+func (pr *TaskRepo) DeleteFilter(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.DeleteFilter")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getFilterCollection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		pr.logger.Println("Error deleting filter:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getImportCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	importsCollection := taskDatabase.Collection("imports")
+	return importsCollection
+}
+
+func (pr *TaskRepo) InsertImportJob(ctx context.Context, job *domain.ImportJob) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.InsertImportJob")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getImportCollection().InsertOne(ctx, job); err != nil {
+		pr.logger.Println("Error inserting import job:", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateImportJob stores the progress and the results of an import job.
+func (pr *TaskRepo) UpdateImportJob(ctx context.Context, job *domain.ImportJob) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.UpdateImportJob")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getImportCollection().ReplaceOne(ctx, bson.M{"_id": job.Id}, job); err != nil {
+		pr.logger.Println("Error updating import job:", err)
+		return err
+	}
+	return nil
+}
+
+func (pr *TaskRepo) GetImportJob(ctx context.Context, id string) (*domain.ImportJob, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetImportJob")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	var job domain.ImportJob
+	err = pr.getImportCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println("Error fetching import job:", err)
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetImportJobs returns the import jobs of a project, the latest first and
+// without their row results.
+func (pr *TaskRepo) GetImportJobs(ctx context.Context, projectId string) (domain.ImportJobs, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetImportJobs")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetProjection(bson.M{"results": 0})
+	cursor, err := pr.getImportCollection().Find(ctx, bson.M{"project": projectId}, opts)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	jobs := domain.ImportJobs{}
+	if err = cursor.All(ctx, &jobs); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// SetImported fills in a task created by an import with the state, the
+// members and the labels of its row.
+func (pr *TaskRepo) SetImported(ctx context.Context, task domain.Task) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetImported")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	set := bson.M{
+		"status":      task.Status,
+		"state":       task.State,
+		"finished_at": task.FinishedAt,
+	}
+	if len(task.Members) > 0 {
+		set["members"] = task.Members
+	}
+	if len(task.Labels) > 0 {
+		set["labels"] = task.Labels
+	}
+	_, err := pr.getCollection().UpdateOne(ctx, bson.M{"_id": task.Id}, bson.M{"$set": set, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		pr.logger.Println("Error setting imported task:", err)
+		return err
+	}
+	return nil
+}
+
+// FailInterruptedImports fails the import jobs that are still queued or
+// running, which after a restart no one is processing anymore.
+func (pr *TaskRepo) FailInterruptedImports(ctx context.Context, at time.Time) (int64, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.FailInterruptedImports")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"state": bson.M{"$in": bson.A{domain.IMPORT_QUEUED, domain.IMPORT_RUNNING}}}
+	update := bson.M{"$set": bson.M{
+		"state":       domain.IMPORT_FAILED,
+		"error":       "interrupted by a restart of the service",
+		"finished_at": at,
+	}}
+	result, err := pr.getImportCollection().UpdateMany(ctx, filter, update)
+	if err != nil {
+		pr.logger.Println("Error failing interrupted imports:", err)
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getLabelCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	labelsCollection := taskDatabase.Collection("labels")
+	return labelsCollection
+}
+
+func (pr *TaskRepo) InsertLabel(ctx context.Context, label *domain.Label) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.InsertLabel")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getLabelCollection().InsertOne(ctx, label); err != nil {
+		pr.logger.Println("Error inserting label:", err)
+		return err
+	}
+	return nil
+}
+
+func (pr *TaskRepo) GetLabelById(ctx context.Context, id string) (*domain.Label, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetLabelById")
+	defer span.End()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+	return pr.findLabel(ctx, bson.M{"_id": objID})
+}
+
+// GetLabelByName finds a label of a project by name, ignoring case.
+func (pr *TaskRepo) GetLabelByName(ctx context.Context, projectId string, name string) (*domain.Label, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetLabelByName")
+	defer span.End()
+
+	return pr.findLabel(ctx, bson.M{
+		"project": projectId,
+		"name":    primitive.Regex{Pattern: "^" + regexp.QuoteMeta(name) + "$", Options: "i"},
+	})
+}
+
+func (pr *TaskRepo) findLabel(ctx context.Context, filter bson.M) (*domain.Label, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var label domain.Label
+	err := pr.getLabelCollection().FindOne(ctx, filter).Decode(&label)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println("Error fetching label:", err)
+		return nil, err
+	}
+	return &label, nil
+}
+
+// GetLabels returns the labels of a project ordered by name.
+func (pr *TaskRepo) GetLabels(ctx context.Context, projectId string) (domain.Labels, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetLabels")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getLabelCollection().Find(ctx, bson.M{"project": projectId}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	labels := domain.Labels{}
+	if err = cursor.All(ctx, &labels); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (pr *TaskRepo) UpdateLabel(ctx context.Context, label *domain.Label) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.UpdateLabel")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getLabelCollection().UpdateOne(ctx, bson.M{"_id": label.Id}, bson.M{"$set": bson.M{"name": label.Name, "color": label.Color}})
+	if err != nil {
+		pr.logger.Println("Error updating label:", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteLabel deletes a label and removes it from every task.
+func (pr *TaskRepo) DeleteLabel(ctx context.Context, label *domain.Label) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.DeleteLabel")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	labelId := label.Id.Hex()
+	if _, err := pr.getCollection().UpdateMany(ctx, bson.M{"labels": labelId}, bson.M{"$pull": bson.M{"labels": labelId}, "$inc": bson.M{"version": 1}}); err != nil {
+		pr.logger.Println("Error removing label from tasks:", err)
+		return err
+	}
+	if _, err := pr.getLabelCollection().DeleteOne(ctx, bson.M{"_id": label.Id}); err != nil {
+		pr.logger.Println("Error deleting label:", err)
+		return err
+	}
+	return nil
+}
+
+// AddTaskLabel assigns a label to a task.
+func (pr *TaskRepo) AddTaskLabel(ctx context.Context, taskId primitive.ObjectID, labelId string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.AddTaskLabel")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().UpdateOne(ctx, bson.M{"_id": taskId}, bson.M{"$addToSet": bson.M{"labels": labelId}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		pr.logger.Println("Error adding label:", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveTaskLabel unassigns a label from a task.
+func (pr *TaskRepo) RemoveTaskLabel(ctx context.Context, taskId primitive.ObjectID, labelId string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.RemoveTaskLabel")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().UpdateOne(ctx, bson.M{"_id": taskId}, bson.M{"$pull": bson.M{"labels": labelId}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		pr.logger.Println("Error removing label:", err)
+		return err
+	}
+	return nil
+}
+
+func taskQuery(query domain.TaskQuery) bson.M {
+	filter := active(bson.M{})
+	if query.Project != "" {
+		filter["project"] = query.Project
+	}
+	if len(query.Labels) > 0 {
+		filter["labels"] = bson.M{"$in": query.Labels}
+	}
+	if len(query.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": query.Statuses}
+	}
+	if len(query.States) > 0 {
+		filter["state"] = bson.M{"$in": query.States}
+	}
+	if query.Assignee != "" {
+		filter["members.username"] = query.Assignee
+	}
+	if query.Sprint == domain.SPRINT_BACKLOG {
+		filter["sprint"] = bson.M{"$exists": false}
+	} else if query.Sprint != "" {
+		filter["sprint"] = query.Sprint
+	}
+	if query.Text != "" {
+		text := primitive.Regex{Pattern: regexp.QuoteMeta(query.Text), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"name": text}, bson.M{"description": text}}
+	}
+	if dates := dateRange(query.DueFrom, query.DueTo); dates != nil {
+		filter["due_date"] = dates
+	}
+	if dates := dateRange(query.CreatedFrom, query.CreatedTo); dates != nil {
+		filter["created_at"] = dates
+	}
+	return filter
+}
+
+func dateRange(from *time.Time, to *time.Time) bson.M {
+	if from == nil && to == nil {
+		return nil
+	}
+	dates := bson.M{}
+	if from != nil {
+		dates["$gte"] = *from
+	}
+	if to != nil {
+		dates["$lt"] = *to
+	}
+	return dates
+}
+
+// QueryTasks returns the tasks matching a query, newest first.
+func (pr *TaskRepo) QueryTasks(ctx context.Context, query domain.TaskQuery) (domain.Tasks, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.QueryTasks")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getCollection().Find(ctx, taskQuery(query), options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	tasks := domain.Tasks{}
+	if err = cursor.All(ctx, &tasks); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return tasks, nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SetRecurrence sets the recurrence rule of a task, a nil rule stops the
+// task from recurring.
+func (pr *TaskRepo) SetRecurrence(ctx context.Context, taskId primitive.ObjectID, recurrence *domain.Recurrence) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetRecurrence")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$unset": bson.M{"recurrence": ""}, "$inc": bson.M{"version": 1}}
+	if recurrence != nil {
+		update = bson.M{"$set": bson.M{"recurrence": recurrence}, "$inc": bson.M{"version": 1}}
+	}
+	_, err := pr.getCollection().UpdateOne(ctx, bson.M{"_id": taskId}, update)
+	if err != nil {
+		pr.logger.Println("Error setting recurrence:", err)
+		return err
+	}
+	return nil
+}
+
+// SetInstance fills in a generated instance of a recurring task with the
+// rule and the members and labels carried over from the previous instance.
+func (pr *TaskRepo) SetInstance(ctx context.Context, taskId primitive.ObjectID, recurrence *domain.Recurrence, members domain.Users, labels []string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetInstance")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	set := bson.M{"recurrence": recurrence}
+	if len(members) > 0 {
+		set["members"] = members
+	}
+	if len(labels) > 0 {
+		set["labels"] = labels
+	}
+	_, err := pr.getCollection().UpdateOne(ctx, bson.M{"_id": taskId}, bson.M{"$set": set, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		pr.logger.Println("Error setting instance:", err)
+		return err
+	}
+	return nil
+}
+
+// ClaimRecurrence marks that the next instance of a recurring task is being
+// generated, or releases the mark when generating it failed. It reports
+// false when the mark was already in the requested state, so only one caller
+// generates each instance.
+func (pr *TaskRepo) ClaimRecurrence(ctx context.Context, taskId primitive.ObjectID, claim bool) (bool, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.ClaimRecurrence")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := pr.getCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": taskId, "recurrence": bson.M{"$exists": true}, "recurrence.spawned": bson.M{"$ne": claim}},
+		bson.M{"$set": bson.M{"recurrence.spawned": claim}},
+	)
+	if err != nil {
+		pr.logger.Println("Error claiming recurrence:", err)
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// GetDueRecurrences returns the recurring tasks whose next instance was due
+// by the given time and hasn't been generated yet.
+func (pr *TaskRepo) GetDueRecurrences(ctx context.Context, now time.Time) (domain.Tasks, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetDueRecurrences")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getCollection().Find(ctx, active(bson.M{
+		"recurrence.next_at": bson.M{"$lte": now},
+		"recurrence.spawned": bson.M{"$ne": true},
+	}))
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	tasks := domain.Tasks{}
+	if err = cursor.All(ctx, &tasks); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return tasks, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getRevisionCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	revisionsCollection := taskDatabase.Collection("revisions")
+	return revisionsCollection
+}
+
+func (pr *TaskRepo) InsertRevision(ctx context.Context, revision *domain.Revision) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.InsertRevision")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getRevisionCollection().InsertOne(ctx, revision); err != nil {
+		pr.logger.Println("Error inserting revision:", err)
+		return err
+	}
+	return nil
+}
+
+// GetRevisions returns one page of the revisions of a task, newest first.
+func (pr *TaskRepo) GetRevisions(ctx context.Context, taskId string, page int, size int) (*domain.RevisionPage, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetRevisions")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	revisionsCollection := pr.getRevisionCollection()
+	query := bson.M{"task": taskId}
+
+	total, err := revisionsCollection.CountDocuments(ctx, query)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}, {Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * size)).
+		SetLimit(int64(size))
+	cursor, err := revisionsCollection.Find(ctx, query, opts)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	revisions := domain.Revisions{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	return &domain.RevisionPage{Items: revisions, Total: total, Page: page, Size: size}, nil
+}
+
+// GetRevisionsSince returns the revisions of a task from a version on,
+// newest first.
+func (pr *TaskRepo) GetRevisionsSince(ctx context.Context, taskId string, version int64) (domain.Revisions, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetRevisionsSince")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getRevisionCollection().Find(
+		ctx,
+		bson.M{"task": taskId, "version": bson.M{"$gte": version}},
+		options.Find().SetSort(bson.D{{Key: "version", Value: -1}, {Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	revisions := domain.Revisions{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return revisions, nil
+}
@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getSprintCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	sprintsCollection := taskDatabase.Collection("sprints")
+	return sprintsCollection
+}
+
+// CreateSprintIndexes lets at most one sprint of a project be in progress.
+func (pr *TaskRepo) CreateSprintIndexes(ctx context.Context) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.CreateSprintIndexes")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := pr.getSprintCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "project", Value: 1}},
+		Options: options.Index().SetName("project_active").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"state": domain.SPRINT_ACTIVE}),
+	})
+	if err != nil {
+		pr.logger.Println("Error creating sprint indexes:", err)
+		return err
+	}
+	return nil
+}
+
+func (pr *TaskRepo) InsertSprint(ctx context.Context, sprint *domain.Sprint) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.InsertSprint")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getSprintCollection().InsertOne(ctx, sprint); err != nil {
+		pr.logger.Println("Error inserting sprint:", err)
+		return err
+	}
+	return nil
+}
+
+func (pr *TaskRepo) GetSprintById(ctx context.Context, id string) (*domain.Sprint, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetSprintById")
+	defer span.End()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+	return pr.findSprint(ctx, bson.M{"_id": objID}, nil)
+}
+
+// GetActiveSprint returns the sprint of a project that is in progress, nil if
+// there is none.
+func (pr *TaskRepo) GetActiveSprint(ctx context.Context, projectId string) (*domain.Sprint, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetActiveSprint")
+	defer span.End()
+
+	return pr.findSprint(ctx, bson.M{"project": projectId, "state": domain.SPRINT_ACTIVE}, nil)
+}
+
+// GetNextSprint returns the planned sprint of a project that starts first,
+// nil if there is none.
+func (pr *TaskRepo) GetNextSprint(ctx context.Context, projectId string) (*domain.Sprint, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetNextSprint")
+	defer span.End()
+
+	return pr.findSprint(ctx, bson.M{"project": projectId, "state": domain.SPRINT_PLANNED}, options.FindOne().SetSort(bson.D{{Key: "start", Value: 1}}))
+}
+
+func (pr *TaskRepo) findSprint(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*domain.Sprint, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if opts == nil {
+		opts = options.FindOne()
+	}
+
+	var sprint domain.Sprint
+	err := pr.getSprintCollection().FindOne(ctx, filter, opts).Decode(&sprint)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println("Error fetching sprint:", err)
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+// GetSprints returns the sprints of a project in the order they start.
+func (pr *TaskRepo) GetSprints(ctx context.Context, projectId string) (domain.Sprints, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetSprints")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getSprintCollection().Find(ctx, bson.M{"project": projectId}, options.Find().SetSort(bson.D{{Key: "start", Value: 1}}))
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	sprints := domain.Sprints{}
+	if err = cursor.All(ctx, &sprints); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return sprints, nil
+}
+
+// UpdateSprintState moves a sprint from one state to the next. It reports
+// false when the sprint was no longer in the expected state, and fails with
+// domain.ErrSprintState when another sprint of the project is in progress.
+func (pr *TaskRepo) UpdateSprintState(ctx context.Context, sprint *domain.Sprint, from string) (bool, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.UpdateSprintState")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := pr.getSprintCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": sprint.Id, "state": from},
+		bson.M{"$set": bson.M{
+			"state":      sprint.State,
+			"committed":  sprint.Committed,
+			"planned":    sprint.Planned,
+			"report":     sprint.Report,
+			"started_at": sprint.StartedAt,
+			"closed_at":  sprint.ClosedAt,
+		}},
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, fmt.Errorf("%w: another sprint of the project is already in progress", domain.ErrSprintState())
+	}
+	if err != nil {
+		pr.logger.Println("Error updating sprint:", err)
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// DeleteSprint deletes a sprint and moves its tasks to the backlog.
+func (pr *TaskRepo) DeleteSprint(ctx context.Context, sprint *domain.Sprint) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.DeleteSprint")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	sprintId := sprint.Id.Hex()
+	if _, err := pr.getCollection().UpdateMany(ctx, bson.M{"sprint": sprintId}, bson.M{"$unset": bson.M{"sprint": ""}, "$inc": bson.M{"version": 1}}); err != nil {
+		pr.logger.Println("Error moving sprint tasks to backlog:", err)
+		return err
+	}
+	if _, err := pr.getSprintCollection().DeleteOne(ctx, bson.M{"_id": sprint.Id}); err != nil {
+		pr.logger.Println("Error deleting sprint:", err)
+		return err
+	}
+	return nil
+}
+
+// GetSprintTasks returns the tasks of a sprint, or of the project's backlog
+// when the sprint is empty.
+func (pr *TaskRepo) GetSprintTasks(ctx context.Context, projectId string, sprintId string) (domain.Tasks, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetSprintTasks")
+	defer span.End()
+
+	return pr.QueryTasks(ctx, domain.TaskQuery{Project: projectId, Sprint: sprintOrBacklog(sprintId)})
+}
+
+// SetTaskSprint moves a task to a sprint, or to the backlog when the sprint
+// is empty. The task is only written at the version it was read at.
+func (pr *TaskRepo) SetTaskSprint(ctx context.Context, task *domain.Task, sprintId string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetTaskSprint")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := atVersion(active(bson.M{"_id": task.Id}), task.Version)
+	result, err := pr.getCollection().UpdateOne(ctx, filter, sprintUpdate(sprintId))
+	if err != nil {
+		pr.logger.Println("Error moving task to sprint:", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w: task was changed in the meantime", domain.ErrVersionConflict())
+	}
+	return nil
+}
+
+// SetTasksSprint moves the unfinished tasks of a sprint to another sprint, or
+// to the backlog when sprintId is empty. Tasks that were finished or moved
+// out of the sprint in the meantime are left where they are.
+func (pr *TaskRepo) SetTasksSprint(ctx context.Context, ids []primitive.ObjectID, from string, sprintId string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetTasksSprint")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := active(bson.M{"_id": bson.M{"$in": ids}, "sprint": from, "status": bson.M{"$ne": domain.FINISHED}})
+	_, err := pr.getCollection().UpdateMany(ctx, filter, sprintUpdate(sprintId))
+	if err != nil {
+		pr.logger.Println("Error moving tasks to sprint:", err)
+		return err
+	}
+	return nil
+}
+
+func sprintUpdate(sprintId string) bson.M {
+	if sprintId == "" {
+		return bson.M{"$unset": bson.M{"sprint": ""}, "$inc": bson.M{"version": 1}}
+	}
+	return bson.M{"$set": bson.M{"sprint": sprintId}, "$inc": bson.M{"version": 1}}
+}
+
+func sprintOrBacklog(sprintId string) string {
+	if sprintId == "" {
+		return domain.SPRINT_BACKLOG
+	}
+	return sprintId
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getStatusEventCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	eventsCollection := taskDatabase.Collection("status_events")
+	return eventsCollection
+}
+
+func (pr *TaskRepo) InsertStatusEvents(ctx context.Context, events domain.StatusEvents) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.InsertStatusEvents")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	documents := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		documents = append(documents, event)
+	}
+	if _, err := pr.getStatusEventCollection().InsertMany(ctx, documents); err != nil {
+		pr.logger.Println("Error inserting status events:", err)
+		return err
+	}
+	return nil
+}
+
+// GetStatusEvents returns the status log of a project up to a moment,
+// oldest first.
+func (pr *TaskRepo) GetStatusEvents(ctx context.Context, projectId string, before time.Time) (domain.StatusEvents, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetStatusEvents")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getStatusEventCollection().Find(
+		ctx,
+		bson.M{"project": projectId, "at": bson.M{"$lt": before}},
+		options.Find().SetSort(bson.D{{Key: "at", Value: 1}}),
+	)
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	events := domain.StatusEvents{}
+	if err = cursor.All(ctx, &events); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getKeyCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	keysCollection := taskDatabase.Collection("task_keys")
+	return keysCollection
+}
+
+// CreateTaskIndexes makes task names unique within a project and task
+// numbers unique within a project. Trashed tasks keep their deleted_at, so
+// they don't block a new task with the same name.
+func (pr *TaskRepo) CreateTaskIndexes(ctx context.Context) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.CreateTaskIndexes")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "project", Value: 1}, {Key: "name", Value: 1}, {Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetName("project_name").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "project", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetName("project_number").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"number": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		pr.logger.Println("Error creating task indexes:", err)
+		return err
+	}
+	return nil
+}
+
+// isDuplicateName tells whether a write failed on the unique index of task
+// names rather than on the one of task numbers.
+func isDuplicateName(err error) bool {
+	return mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), "project_name")
+}
+
+// NextTaskNumbers atomically reserves count task numbers of a project and
+// returns the sequence after the reservation, so the numbers are
+// Last-count+1 to Last.
+func (pr *TaskRepo) NextTaskNumbers(ctx context.Context, projectId string, count int64) (*domain.KeySequence, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.NextTaskNumbers")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$inc":         bson.M{"last": count},
+		"$setOnInsert": bson.M{"prefix": domain.DEFAULT_KEY_PREFIX},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var sequence domain.KeySequence
+	err := pr.getKeyCollection().FindOneAndUpdate(ctx, bson.M{"_id": projectId}, update, opts).Decode(&sequence)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another request created the sequence at the same time.
+		err = pr.getKeyCollection().FindOneAndUpdate(ctx, bson.M{"_id": projectId}, update, opts).Decode(&sequence)
+	}
+	if err != nil {
+		pr.logger.Println("Error reserving task numbers:", err)
+		return nil, err
+	}
+	return &sequence, nil
+}
+
+// GetKeySequence returns the key sequence of a project, nil if no task of
+// the project has a key yet.
+func (pr *TaskRepo) GetKeySequence(ctx context.Context, projectId string) (*domain.KeySequence, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetKeySequence")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var sequence domain.KeySequence
+	err := pr.getKeyCollection().FindOne(ctx, bson.M{"_id": projectId}).Decode(&sequence)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		pr.logger.Println("Error finding key sequence:", err)
+		return nil, err
+	}
+	return &sequence, nil
+}
+
+// SetKeyPrefix changes the key prefix of a project and rewrites the keys of
+// its tasks with the new prefix. Numbers reserved after the prefix is changed
+// get the new prefix from NextTaskNumbers, tasks numbered before it but
+// stored after the rewrite are left to ReplaceTaskKey.
+func (pr *TaskRepo) SetKeyPrefix(ctx context.Context, projectId string, prefix string) (*domain.KeySequence, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetKeyPrefix")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var sequence domain.KeySequence
+	err := pr.getKeyCollection().FindOneAndUpdate(ctx,
+		bson.M{"_id": projectId},
+		bson.M{"$set": bson.M{"prefix": prefix}, "$setOnInsert": bson.M{"last": 0}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&sequence)
+	if err != nil {
+		pr.logger.Println("Error setting key prefix:", err)
+		return nil, err
+	}
+
+	_, err = pr.getCollection().UpdateMany(ctx,
+		bson.M{"project": projectId, "number": bson.M{"$exists": true}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"key": bson.M{"$concat": bson.A{prefix, "-", bson.M{"$toString": "$number"}}},
+		}}}},
+	)
+	if err != nil {
+		pr.logger.Println("Error rewriting task keys:", err)
+		return nil, err
+	}
+	return &sequence, nil
+}
+
+// ReplaceTaskKey changes the key of a task, unless its key has already been
+// changed from the given one.
+func (pr *TaskRepo) ReplaceTaskKey(ctx context.Context, id primitive.ObjectID, from string, to string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.ReplaceTaskKey")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().UpdateOne(ctx, bson.M{"_id": id, "key": from}, bson.M{"$set": bson.M{"key": to}})
+	if err != nil {
+		pr.logger.Println("Error replacing task key:", err)
+		return err
+	}
+	return nil
+}
+
+// FindByNumber finds a task of a project by the number of its key.
+func (pr *TaskRepo) FindByNumber(ctx context.Context, projectId string, number int64) (*domain.Task, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.FindByNumber")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var task domain.Task
+	err := pr.getCollection().FindOne(ctx, active(bson.M{"project": projectId, "number": number})).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		pr.logger.Println("Error finding task by key:", err)
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetTasksWithoutKey returns tasks created before task keys existed, trashed
+// ones included, oldest first.
+func (pr *TaskRepo) GetTasksWithoutKey(ctx context.Context) (domain.Tasks, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetTasksWithoutKey")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getCollection().Find(ctx,
+		bson.M{"number": bson.M{"$exists": false}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		pr.logger.Println("Error finding tasks without key:", err)
+		return nil, err
+	}
+	tasks := domain.Tasks{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		pr.logger.Println("Error decoding tasks without key:", err)
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// SetTaskKey stores the key of a task that doesn't have one yet.
+func (pr *TaskRepo) SetTaskKey(ctx context.Context, id primitive.ObjectID, number int64, key string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetTaskKey")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().UpdateOne(ctx,
+		bson.M{"_id": id, "number": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"number": number, "key": key}},
+	)
+	if err != nil {
+		pr.logger.Println("Error setting task key:", err)
+		return err
+	}
+	return nil
+}
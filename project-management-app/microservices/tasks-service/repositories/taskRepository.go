@@ -44,6 +44,16 @@ func NewTaskRepo(ctx context.Context, logger *log.Logger,tracer trace.Tracer) (*
 	}, nil
 }
 
+// NewTaskRepoWithClient wraps a client that is already connected, like the
+// mocked client of the tests.
+func NewTaskRepoWithClient(client *mongo.Client, logger *log.Logger, tracer trace.Tracer) *TaskRepo {
+	return &TaskRepo{
+		cli:    client,
+		logger: logger,
+		tracer: tracer,
+	}
+}
+
 func (pr *TaskRepo) Disconnect(ctx context.Context) error {
 	err := pr.cli.Disconnect(ctx)
 	if err != nil {
@@ -77,14 +87,16 @@ func (ur *TaskRepo) getCollection() *mongo.Collection {
 	return tasksCollection
 }
 
-func (pr *TaskRepo) FindByName(name string) (*domain.Task, error) {
+// FindByName finds a task of a project by name. Task names are unique within
+// a project only.
+func (pr *TaskRepo) FindByName(projectId string, name string) (*domain.Task, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	tasksCollection := pr.getCollection()
 
 	var task domain.Task
-	filter := bson.M{"name": name}
+	filter := active(bson.M{"project": projectId, "name": name})
 	err := tasksCollection.FindOne(ctx, filter).Decode(&task)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -104,7 +116,7 @@ func (pr *TaskRepo) GetByProject(ctx context.Context ,projectId string) (domain.
 	patientsCollection := pr.getCollection()
 
 	var tasks domain.Tasks
-	patientsCursor, err := patientsCollection.Find(ctx, bson.M{"project": projectId})
+	patientsCursor, err := patientsCollection.Find(ctx, active(bson.M{"project": projectId}))
 	if err != nil {
 		pr.logger.Println(err)
 		return nil, err
@@ -123,7 +135,7 @@ func (pr *TaskRepo) FindByProject(projectId string) (*domain.Task, error) {
 	tasksCollection := pr.getCollection()
 
 	var task domain.Task
-	filter := bson.M{"project": projectId}
+	filter := active(bson.M{"project": projectId})
 	err := tasksCollection.FindOne(ctx, filter).Decode(&task)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -144,7 +156,7 @@ func (pr *TaskRepo) GetAll() (domain.Tasks, error) {
 	tasksCollection := pr.getCollection()
 
 	var tasks domain.Tasks
-	tasksCursor, err := tasksCollection.Find(ctx, bson.M{})
+	tasksCursor, err := tasksCollection.Find(ctx, active(bson.M{}))
 	if err != nil {
 		pr.logger.Println(err)
 		return nil, err
@@ -164,7 +176,7 @@ func (ur *TaskRepo) GetMembersByTaskId(id string) (domain.Users, error) {
 
 	var task domain.Task
 	objID, _ := primitive.ObjectIDFromHex(id)
-	err := tasksCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&task)
+	err := tasksCollection.FindOne(ctx, active(bson.M{"_id": objID})).Decode(&task)
 	if err != nil {
 		ur.logger.Println(err)
 		return nil, err
@@ -180,7 +192,7 @@ func (ur *TaskRepo) GetAllByManager(id string) (domain.Tasks, error) {
 	tasksCollection := ur.getCollection()
 
 	var projects domain.Tasks
-	projectsCursor, err := tasksCollection.Find(ctx, bson.M{"project": id})
+	projectsCursor, err := tasksCollection.Find(ctx, active(bson.M{"project": id}))
 	if err != nil {
 		ur.logger.Println(err)
 		return nil, err
@@ -222,6 +234,7 @@ func (ur *TaskRepo) AddMember(projectId primitive.ObjectID, user domain.User) er
 		bson.M{"_id": projectId},
 		bson.M{
 			"$push": bson.M{"members": user},
+			"$inc":  bson.M{"version": 1},
 		},
 	)
 	if err != nil {
@@ -240,7 +253,7 @@ func (ur *TaskRepo) FindById(id string) (*domain.Task, error) {
 
 	var task domain.Task
 	objID, _ := primitive.ObjectIDFromHex(id)
-	err := tasksCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&task)
+	err := tasksCollection.FindOne(ctx, active(bson.M{"_id": objID})).Decode(&task)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -262,6 +275,7 @@ func (ur *TaskRepo) RemoveMember(taskId primitive.ObjectID, user domain.User) er
 		bson.M{"_id": taskId},
 		bson.M{
 			"$pull": bson.M{"members": bson.M{"username": user.Username}},
+			"$inc":  bson.M{"version": 1},
 		},
 	)
 	if err != nil {
@@ -272,6 +286,29 @@ func (ur *TaskRepo) RemoveMember(taskId primitive.ObjectID, user domain.User) er
 	return nil
 }
 
+// UnassignMember removes a user from the members of the given tasks.
+func (pr *TaskRepo) UnassignMember(ctx context.Context, ids []primitive.ObjectID, username string) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.UnassignMember")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().UpdateMany(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{
+			"$pull": bson.M{"members": bson.M{"username": username}},
+			"$inc":  bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		pr.logger.Println("Error unassigning member:", err)
+		return err
+	}
+	return nil
+}
+
 // Insert umeće novi zadatak u MongoDB kolekciju
 func (pr *TaskRepo) Insert(ctx context.Context, task domain.Task) (domain.Task, error) {
 	ctx, span := pr.tracer.Start(ctx, "Tasks.Handler.GetByProject")
@@ -280,6 +317,9 @@ func (pr *TaskRepo) Insert(ctx context.Context, task domain.Task) (domain.Task,
 	tasksCollection := pr.getCollection()
 
 	_, err := tasksCollection.InsertOne(ctx, task)
+	if isDuplicateName(err) {
+		return domain.Task{}, domain.ErrTaskNameExists()
+	}
 	if err != nil {
 		pr.logger.Println("Greška prilikom umetanja zadatka:", err)
 		return domain.Task{}, err
@@ -289,30 +329,55 @@ func (pr *TaskRepo) Insert(ctx context.Context, task domain.Task) (domain.Task,
 	return task, nil
 }
 
-func (pr *TaskRepo) Update(updatedTask domain.Task) (domain.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Update stores the given fields of a task and increments its version. It
+// fails with domain.ErrVersionConflict when the task is no longer at version.
+func (pr *TaskRepo) Update(ctx context.Context, updatedTask domain.Task, version int64, fields []string) (domain.Task, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.Update")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	tasksCollection := pr.getCollection()
 
-	filter := bson.M{"_id": updatedTask.Id}
-	update := bson.M{
-		"$set": bson.M{
-			"project":     updatedTask.Project,
-			"name":        updatedTask.Name,
-			"description": updatedTask.Description,
-			"status":      updatedTask.Status,
-		},
+	values := bson.M{
+		"name":           updatedTask.Name,
+		"description":    updatedTask.Description,
+		"status":         updatedTask.Status,
+		"state":          updatedTask.State,
+		"due_date":       updatedTask.DueDate,
+		"priority":       updatedTask.Priority,
+		"story_points":   updatedTask.StoryPoints,
+		"estimate_hours": updatedTask.EstimateHours,
+		"finished_at":    updatedTask.FinishedAt,
+	}
+	set := bson.M{}
+	for _, field := range fields {
+		if value, ok := values[field]; ok {
+			set[field] = value
+		}
+	}
+
+	filter := atVersion(active(bson.M{"_id": updatedTask.Id}), version)
+	update := bson.M{"$inc": bson.M{"version": 1}}
+	if len(set) > 0 {
+		update["$set"] = set
 	}
 
-	_, err := tasksCollection.UpdateOne(ctx, filter, update)
+	result, err := tasksCollection.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.Task{}, domain.ErrTaskNameExists()
+	}
 	if err != nil {
 		pr.logger.Println("Error updating task:", err)
 		return domain.Task{}, fmt.Errorf("failed to update task: %w", err)
 	}
+	if result.MatchedCount == 0 {
+		return domain.Task{}, domain.ErrVersionConflict()
+	}
 
 	var task domain.Task
-	err = tasksCollection.FindOne(ctx, filter).Decode(&task)
+	err = tasksCollection.FindOne(ctx, bson.M{"_id": updatedTask.Id}).Decode(&task)
 	if err != nil {
 		pr.logger.Println("Error fetching updated task:", err)
 		return domain.Task{}, fmt.Errorf("failed to fetch updated task: %w", err)
@@ -321,3 +386,137 @@ func (pr *TaskRepo) Update(updatedTask domain.Task) (domain.Task, error) {
 	pr.logger.Println("Task successfully updated:", task.Id)
 	return task, nil
 }
+
+// GetProjectStats aggregates the tasks of a project in a single pipeline:
+// counts by status, per-member workload, overdue tasks and the number of
+// tasks created and finished per day since the given time.
+func (pr *TaskRepo) GetProjectStats(ctx context.Context, projectId string, since time.Time) (*domain.TaskStats, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetProjectStats")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	isOpen := bson.M{"$ne": bson.A{"$status", domain.FINISHED}}
+	isOverdue := bson.M{"$and": bson.A{
+		isOpen,
+		bson.M{"$eq": bson.A{bson.M{"$type": "$due_date"}, "date"}},
+		bson.M{"$lt": bson.A{"$due_date", now}},
+	}}
+	perDay := func(field string) bson.A {
+		return bson.A{
+			bson.M{"$match": bson.M{field: bson.M{"$gte": since}}},
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$" + field}},
+				"count": bson.M{"$sum": 1},
+			}},
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: active(bson.M{"project": projectId})}},
+		{{Key: "$facet", Value: bson.M{
+			"status": bson.A{
+				bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+			},
+			"workload": bson.A{
+				bson.M{"$unwind": "$members"},
+				bson.M{"$group": bson.M{
+					"_id":     "$members.username",
+					"total":   bson.M{"$sum": 1},
+					"open":    bson.M{"$sum": bson.M{"$cond": bson.A{isOpen, 1, 0}}},
+					"overdue": bson.M{"$sum": bson.M{"$cond": bson.A{isOverdue, 1, 0}}},
+				}},
+				bson.M{"$sort": bson.D{{Key: "open", Value: -1}, {Key: "_id", Value: 1}}},
+			},
+			"overdue": bson.A{
+				bson.M{"$match": bson.M{"$expr": isOverdue}},
+				bson.M{"$count": "count"},
+			},
+			"created":  perDay("created_at"),
+			"finished": perDay("finished_at"),
+		}}},
+	}
+
+	cursor, err := pr.getCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		pr.logger.Println("Error aggregating task stats:", err)
+		return nil, err
+	}
+
+	type count struct {
+		Id    interface{} `bson:"_id"`
+		Count int         `bson:"count"`
+	}
+	var facets []struct {
+		Status   []count                 `bson:"status"`
+		Workload []domain.MemberWorkload `bson:"workload"`
+		Overdue  []count                 `bson:"overdue"`
+		Created  []count                 `bson:"created"`
+		Finished []count                 `bson:"finished"`
+	}
+	if err = cursor.All(ctx, &facets); err != nil {
+		pr.logger.Println("Error decoding task stats:", err)
+		return nil, err
+	}
+
+	stats := &domain.TaskStats{
+		Project:  projectId,
+		ByStatus: map[string]int{},
+		Workload: []domain.MemberWorkload{},
+		Trend:    []domain.TrendPoint{},
+	}
+	if len(facets) == 0 {
+		return stats, nil
+	}
+	facet := facets[0]
+
+	for _, c := range facet.Status {
+		status := domain.Status(toInt(c.Id))
+		stats.Total += c.Count
+		if status == domain.FINISHED {
+			stats.Finished += c.Count
+		}
+		stats.ByStatus[statusKey(status)] += c.Count
+	}
+	if len(facet.Overdue) > 0 {
+		stats.Overdue = facet.Overdue[0].Count
+	}
+	stats.Workload = append(stats.Workload, facet.Workload...)
+
+	created := map[string]int{}
+	for _, c := range facet.Created {
+		created[fmt.Sprint(c.Id)] = c.Count
+	}
+	finished := map[string]int{}
+	for _, c := range facet.Finished {
+		finished[fmt.Sprint(c.Id)] = c.Count
+	}
+	for day := since.UTC().Truncate(24 * time.Hour); !day.After(now); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		stats.Trend = append(stats.Trend, domain.TrendPoint{Date: date, Created: created[date], Finished: finished[date]})
+	}
+
+	return stats, nil
+}
+
+func toInt(value interface{}) int {
+	switch v := value.(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+func statusKey(status domain.Status) string {
+	if status < domain.PENDING || status > domain.FINISHED {
+		return fmt.Sprint(int(status))
+	}
+	return status.String()
+}
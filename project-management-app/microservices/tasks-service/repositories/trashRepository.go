@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// active restricts a task filter to tasks that are not in the trash.
+func active(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+// atVersion restricts a task filter to the given version of the task. Tasks
+// created before versioning have no version field and count as version 0.
+func atVersion(filter bson.M, version int64) bson.M {
+	filter["version"] = version
+	if version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	return filter
+}
+
+// TrashTasks moves tasks to the trash. root is the task the user deleted,
+// its subtasks are trashed and restored together with it.
+func (pr *TaskRepo) TrashTasks(ctx context.Context, ids []primitive.ObjectID, root string, deletedBy string, deletedAt time.Time) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.TrashTasks")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := pr.getCollection().UpdateMany(
+		ctx,
+		active(bson.M{"_id": bson.M{"$in": ids}}),
+		bson.M{"$set": bson.M{"deleted_at": deletedAt, "deleted_by": deletedBy, "deleted_with": root}},
+	)
+	if err != nil {
+		pr.logger.Println("Error trashing tasks:", err)
+		return err
+	}
+	return nil
+}
+
+// RestoreTasks takes a task and the subtasks trashed with it, given by ids,
+// out of the trash. When a restored name clashes with an active task, the
+// tasks restored so far go back to the trash and domain.ErrTaskNameExists is
+// returned.
+func (pr *TaskRepo) RestoreTasks(ctx context.Context, root *domain.Task, ids []primitive.ObjectID) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.RestoreTasks")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rootId := root.Id.Hex()
+	_, err := pr.getCollection().UpdateMany(
+		ctx,
+		bson.M{"deleted_with": rootId},
+		bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": "", "deleted_with": ""}},
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// Tasks are trashed together, so they share the deletion fields of the root.
+		_, undoErr := pr.getCollection().UpdateMany(
+			ctx,
+			bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"deleted_at": root.DeletedAt, "deleted_by": root.DeletedBy, "deleted_with": rootId}},
+		)
+		if undoErr != nil {
+			pr.logger.Println("Error undoing partial restore:", undoErr)
+		}
+		return domain.ErrTaskNameExists()
+	}
+	if err != nil {
+		pr.logger.Println("Error restoring tasks:", err)
+		return err
+	}
+	return nil
+}
+
+// SetTrashedState moves trashed tasks to a workflow state before they are
+// restored.
+func (pr *TaskRepo) SetTrashedState(ctx context.Context, ids []primitive.ObjectID, state domain.WorkflowState) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SetTrashedState")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$set": bson.M{"state": state.Name, "status": state.CategoryStatus()},
+		"$inc": bson.M{"version": 1},
+	}
+	if state.CategoryStatus() != domain.FINISHED {
+		update["$unset"] = bson.M{"finished_at": ""}
+	}
+	_, err := pr.getCollection().UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$exists": true}}, update)
+	if err != nil {
+		pr.logger.Println("Error setting state of trashed tasks:", err)
+		return err
+	}
+	return nil
+}
+
+// FindTrashedById returns a task from the trash, nil if it isn't there.
+func (pr *TaskRepo) FindTrashedById(ctx context.Context, id string) (*domain.Task, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.FindTrashedById")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	var task domain.Task
+	err = pr.getCollection().FindOne(ctx, bson.M{"_id": objID, "deleted_at": bson.M{"$exists": true}}).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetTrash returns the tasks in the trash, most recently deleted first. An
+// empty project returns the trash of all projects.
+func (pr *TaskRepo) GetTrash(ctx context.Context, projectId string, deletedBefore *time.Time) (domain.Tasks, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetTrash")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	deletedAt := bson.M{"$exists": true}
+	if deletedBefore != nil {
+		deletedAt["$lt"] = *deletedBefore
+	}
+	filter := bson.M{"deleted_at": deletedAt}
+	if projectId != "" {
+		filter["project"] = projectId
+	}
+
+	cursor, err := pr.getCollection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}}))
+	if err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+
+	tasks := domain.Tasks{}
+	if err = cursor.All(ctx, &tasks); err != nil {
+		pr.logger.Println(err)
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// StopTaskWorklogs stops the running timers on the given tasks.
+func (pr *TaskRepo) StopTaskWorklogs(ctx context.Context, taskIds []string, end time.Time) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.StopTaskWorklogs")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	minutes := bson.M{"$max": bson.A{1, bson.M{"$round": bson.A{
+		bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{end, "$start"}}, 60000}},
+	}}}}
+	_, err := pr.getWorklogCollection().UpdateMany(
+		ctx,
+		bson.M{"task": bson.M{"$in": taskIds}, "end": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"end": end, "minutes": minutes}}}},
+	)
+	if err != nil {
+		pr.logger.Println("Error stopping timers:", err)
+		return err
+	}
+	return nil
+}
+
+// PurgeTasks permanently deletes tasks with their comments, worklogs and
+// history, and removes them from the blockers of other tasks.
+func (pr *TaskRepo) PurgeTasks(ctx context.Context, ids []primitive.ObjectID) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.PurgeTasks")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	taskIds := bson.A{}
+	for _, id := range ids {
+		taskIds = append(taskIds, id.Hex())
+	}
+
+	if _, err := pr.getCommentCollection().DeleteMany(ctx, bson.M{"task": bson.M{"$in": taskIds}}); err != nil {
+		pr.logger.Println("Error deleting comments:", err)
+		return err
+	}
+	if _, err := pr.getWorklogCollection().DeleteMany(ctx, bson.M{"task": bson.M{"$in": taskIds}}); err != nil {
+		pr.logger.Println("Error deleting worklogs:", err)
+		return err
+	}
+	if _, err := pr.getRevisionCollection().DeleteMany(ctx, bson.M{"task": bson.M{"$in": taskIds}}); err != nil {
+		pr.logger.Println("Error deleting revisions:", err)
+		return err
+	}
+	if _, err := pr.getCollection().UpdateMany(
+		ctx,
+		bson.M{"blocked_by": bson.M{"$in": taskIds}},
+		bson.M{"$pull": bson.M{"blocked_by": bson.M{"$in": taskIds}}},
+	); err != nil {
+		pr.logger.Println("Error removing dependencies:", err)
+		return err
+	}
+	if _, err := pr.getCollection().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		pr.logger.Println("Error deleting tasks:", err)
+		return err
+	}
+	return nil
+}
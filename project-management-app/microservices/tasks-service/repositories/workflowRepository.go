@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getWorkflowCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	workflowsCollection := taskDatabase.Collection("workflows")
+	return workflowsCollection
+}
+
+// GetWorkflow returns the workflow of a project, nil when the project uses
+// the default one.
+func (pr *TaskRepo) GetWorkflow(ctx context.Context, projectId string) (*domain.Workflow, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetWorkflow")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var workflow domain.Workflow
+	err := pr.getWorkflowCollection().FindOne(ctx, bson.M{"project": projectId}).Decode(&workflow)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println("Error fetching workflow:", err)
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// SaveWorkflow replaces the workflow of a project.
+func (pr *TaskRepo) SaveWorkflow(ctx context.Context, workflow *domain.Workflow) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SaveWorkflow")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"project":       workflow.Project,
+		"initial_state": workflow.InitialState,
+		"states":        workflow.States,
+		"transitions":   workflow.Transitions,
+		"updated_at":    workflow.UpdatedAt,
+	}}
+	_, err := pr.getWorkflowCollection().UpdateOne(ctx, bson.M{"project": workflow.Project}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		pr.logger.Println("Error saving workflow:", err)
+		return err
+	}
+	return nil
+}
+
+// CountTasksInStates counts the tasks of a project that are in one of the
+// given workflow states. Tasks without a state are counted by their status.
+// Trashed tasks are counted too, since they can still be restored.
+func (pr *TaskRepo) CountTasksInStates(ctx context.Context, projectId string, states []string, statuses []domain.Status) (int64, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.CountTasksInStates")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"project": projectId,
+		"$or": bson.A{
+			bson.M{"state": bson.M{"$in": states}},
+			bson.M{"state": bson.M{"$exists": false}, "status": bson.M{"$in": statuses}},
+		},
+	}
+	count, err := pr.getCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		pr.logger.Println("Error counting tasks:", err)
+		return 0, err
+	}
+	return count, nil
+}
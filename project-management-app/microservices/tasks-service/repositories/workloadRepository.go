@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetWorkload aggregates the open tasks of the given users across all
+// projects, of every assigned user when none are given.
+func (pr *TaskRepo) GetWorkload(ctx context.Context, usernames []string) (domain.Workloads, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetWorkload")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	match := active(bson.M{"status": bson.M{"$ne": domain.FINISHED}})
+	members := bson.M{}
+	if len(usernames) > 0 {
+		match["members.username"] = bson.M{"$in": usernames}
+		members["members.username"] = bson.M{"$in": usernames}
+	}
+	share := func(field string) bson.M {
+		return bson.M{"$divide": bson.A{bson.M{"$ifNull": bson.A{"$" + field, 0}}, "$assigned"}}
+	}
+	isOverdue := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": "$due_date"}, "date"}},
+		bson.M{"$lt": bson.A{"$due_date", time.Now()}},
+	}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"assigned": bson.M{"$max": bson.A{1, bson.M{"$size": bson.M{"$ifNull": bson.A{"$members", bson.A{}}}}}}}}},
+		{{Key: "$unwind", Value: "$members"}},
+		{{Key: "$match", Value: members}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            bson.M{"username": "$members.username", "project": "$project"},
+			"open":           bson.M{"$sum": 1},
+			"in_progress":    bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", domain.IN_PROGRESS}}, 1, 0}}},
+			"overdue":        bson.M{"$sum": bson.M{"$cond": bson.A{isOverdue, 1, 0}}},
+			"estimate_hours": bson.M{"$sum": share("estimate_hours")},
+			"story_points":   bson.M{"$sum": share("story_points")},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.project", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$_id.username",
+			"open":           bson.M{"$sum": "$open"},
+			"in_progress":    bson.M{"$sum": "$in_progress"},
+			"overdue":        bson.M{"$sum": "$overdue"},
+			"estimate_hours": bson.M{"$sum": "$estimate_hours"},
+			"story_points":   bson.M{"$sum": "$story_points"},
+			"projects": bson.M{"$push": bson.M{
+				"project":        "$_id.project",
+				"open":           "$open",
+				"estimate_hours": "$estimate_hours",
+				"story_points":   "$story_points",
+			}},
+		}}},
+	}
+
+	cursor, err := pr.getCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		pr.logger.Println("Error aggregating workload:", err)
+		return nil, err
+	}
+
+	workloads := domain.Workloads{}
+	if err = cursor.All(ctx, &workloads); err != nil {
+		pr.logger.Println("Error decoding workload:", err)
+		return nil, err
+	}
+	return workloads, nil
+}
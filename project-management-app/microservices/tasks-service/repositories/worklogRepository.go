@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"project-management-app/microservices/projects-service/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *TaskRepo) getWorklogCollection() *mongo.Collection {
+	taskDatabase := pr.cli.Database("tasks")
+	worklogsCollection := taskDatabase.Collection("worklogs")
+	return worklogsCollection
+}
+
+func (pr *TaskRepo) InsertWorklog(ctx context.Context, worklog *domain.Worklog) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.InsertWorklog")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getWorklogCollection().InsertOne(ctx, worklog); err != nil {
+		pr.logger.Println("Error inserting worklog:", err)
+		return err
+	}
+	return nil
+}
+
+// GetRunningWorklog returns the running timer of a user, nil when there is
+// none.
+func (pr *TaskRepo) GetRunningWorklog(ctx context.Context, username string) (*domain.Worklog, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetRunningWorklog")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var worklog domain.Worklog
+	err := pr.getWorklogCollection().FindOne(ctx, bson.M{"username": username, "end": bson.M{"$exists": false}}).Decode(&worklog)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println("Error fetching running worklog:", err)
+		return nil, err
+	}
+	return &worklog, nil
+}
+
+// StopWorklog closes a running timer. It doesn't touch worklogs that were
+// already stopped.
+func (pr *TaskRepo) StopWorklog(ctx context.Context, id primitive.ObjectID, end time.Time, minutes int) (bool, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.StopWorklog")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := pr.getWorklogCollection().UpdateOne(
+		ctx,
+		bson.M{"_id": id, "end": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"end": end, "minutes": minutes}},
+	)
+	if err != nil {
+		pr.logger.Println("Error stopping worklog:", err)
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (pr *TaskRepo) GetWorklogById(ctx context.Context, id string) (*domain.Worklog, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetWorklogById")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	var worklog domain.Worklog
+	err = pr.getWorklogCollection().FindOne(ctx, bson.M{"_id": objID}).Decode(&worklog)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		pr.logger.Println("Error fetching worklog:", err)
+		return nil, err
+	}
+	return &worklog, nil
+}
+
+func (pr *TaskRepo) DeleteWorklog(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.DeleteWorklog")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := pr.getWorklogCollection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		pr.logger.Println("Error deleting worklog:", err)
+		return err
+	}
+	return nil
+}
+
+func worklogQuery(filter domain.WorklogFilter) bson.M {
+	query := bson.M{}
+	if filter.Project != "" {
+		query["project"] = filter.Project
+	}
+	if filter.Task != "" {
+		query["task"] = filter.Task
+	}
+	if filter.Username != "" {
+		query["username"] = filter.Username
+	}
+	if filter.From != nil || filter.To != nil {
+		start := bson.M{}
+		if filter.From != nil {
+			start["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			start["$lt"] = *filter.To
+		}
+		query["start"] = start
+	}
+	return query
+}
+
+// GetWorklogs returns the worklogs matching a filter, oldest first.
+func (pr *TaskRepo) GetWorklogs(ctx context.Context, filter domain.WorklogFilter) (domain.Worklogs, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.GetWorklogs")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getWorklogCollection().Find(ctx, worklogQuery(filter), options.Find().SetSort(bson.D{{Key: "start", Value: 1}}))
+	if err != nil {
+		pr.logger.Println("Error fetching worklogs:", err)
+		return nil, err
+	}
+
+	worklogs := domain.Worklogs{}
+	if err = cursor.All(ctx, &worklogs); err != nil {
+		pr.logger.Println("Error decoding worklogs:", err)
+		return nil, err
+	}
+	return worklogs, nil
+}
+
+// SumWorklogs adds up the minutes of the finished worklogs matching a filter,
+// grouped by the given field.
+func (pr *TaskRepo) SumWorklogs(ctx context.Context, filter domain.WorklogFilter, groupBy string) ([]domain.TimeTotal, error) {
+	ctx, span := pr.tracer.Start(ctx, "TaskRepo.SumWorklogs")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := worklogQuery(filter)
+	query["end"] = bson.M{"$exists": true}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + groupBy, "minutes": bson.M{"$sum": "$minutes"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "minutes", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := pr.getWorklogCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		pr.logger.Println("Error aggregating worklogs:", err)
+		return nil, err
+	}
+
+	totals := []domain.TimeTotal{}
+	if err = cursor.All(ctx, &totals); err != nil {
+		pr.logger.Println("Error decoding worklog totals:", err)
+		return nil, err
+	}
+	return totals, nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"sync"
+	"time"
+
+	"github.com/eapache/go-resiliency/retrier"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const accessCacheTTL = 30 * time.Second
+
+// accessCache keeps the managers and members of projects for a short while.
+// Only granted access is served from the cache, a denial is always checked
+// against projects-service so new members get access right away.
+type accessCache struct {
+	mu      sync.Mutex
+	entries map[string]accessCacheEntry
+}
+
+type accessCacheEntry struct {
+	access    *domain.ProjectAccess
+	expiresAt time.Time
+}
+
+func newAccessCache() *accessCache {
+	return &accessCache{entries: map[string]accessCacheEntry{}}
+}
+
+func (c *accessCache) get(projectId string) (*domain.ProjectAccess, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[projectId]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.access, true
+}
+
+func (c *accessCache) put(access *domain.ProjectAccess) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[access.Project] = accessCacheEntry{access: access, expiresAt: time.Now().Add(accessCacheTTL)}
+}
+
+func (c *accessCache) invalidate(projectId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, projectId)
+}
+
+// CheckProjectAccess fails with domain.ErrUnauthorized unless the user is
+// the manager of the project or, with the member role, one of its members.
+func (s TaskService) CheckProjectAccess(ctx context.Context, projectId string, username string, role string) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.CheckProjectAccess")
+	defer span.End()
+
+	if access, ok := s.access.get(projectId); ok && access.Allows(username, role) {
+		return nil
+	}
+
+	access, err := s.getProjectAccess(ctx, projectId)
+	if err != nil {
+		return err
+	}
+	s.access.put(access)
+	if !access.Allows(username, role) {
+		return domain.ErrUnauthorized()
+	}
+	return nil
+}
+
+// projectAccess returns the manager, the members, the name and the end date
+// of a project.
+func (s TaskService) projectAccess(ctx context.Context, projectId string) (*domain.ProjectAccess, error) {
+	if access, ok := s.access.get(projectId); ok {
+		return access, nil
+	}
+	access, err := s.getProjectAccess(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	s.access.put(access)
+	return access, nil
+}
+
+// ResourceProject returns the project a resource referred to by a route
+// belongs to. Trashed tasks are found too, so they can be restored.
+func (s TaskService) ResourceProject(ctx context.Context, kind string, id string) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.ResourceProject")
+	defer span.End()
+
+	switch kind {
+	case domain.RESOURCE_PROJECT:
+		return id, nil
+	case domain.RESOURCE_TASK:
+		task, err := s.tasks.FindById(id)
+		if err != nil {
+			return "", err
+		}
+		if task == nil {
+			task, err = s.tasks.FindTrashedById(ctx, id)
+			if err != nil {
+				return "", err
+			}
+		}
+		if task == nil {
+			return "", errors.New("task not found")
+		}
+		return task.Project, nil
+	case domain.RESOURCE_SPRINT:
+		sprint, err := s.tasks.GetSprintById(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if sprint == nil {
+			return "", errors.New("sprint not found")
+		}
+		return sprint.Project, nil
+	case domain.RESOURCE_LABEL:
+		label, err := s.tasks.GetLabelById(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if label == nil {
+			return "", errors.New("label not found")
+		}
+		return label.Project, nil
+	case domain.RESOURCE_COMMENT:
+		comment, err := s.tasks.GetCommentById(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if comment == nil {
+			return "", errors.New("comment not found")
+		}
+		return comment.Project, nil
+	case domain.RESOURCE_WORKLOG:
+		worklog, err := s.tasks.GetWorklogById(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if worklog == nil {
+			return "", errors.New("worklog not found")
+		}
+		return worklog.Project, nil
+	case domain.RESOURCE_ATTACHMENT:
+		attachment, err := s.tasks.GetAttachmentById(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if attachment == nil {
+			return "", errors.New("attachment not found")
+		}
+		return attachment.Project, nil
+	case domain.RESOURCE_IMPORT:
+		job, err := s.tasks.GetImportJob(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if job == nil {
+			return "", errors.New("import not found")
+		}
+		return job.Project, nil
+	}
+	return "", fmt.Errorf("unknown resource %s", kind)
+}
+
+// VisibleTasks keeps the tasks of the projects the user has access to.
+func (s TaskService) VisibleTasks(ctx context.Context, tasks domain.Tasks, username string, role string) domain.Tasks {
+	allowed := map[string]bool{}
+	visible := domain.Tasks{}
+	for _, task := range tasks {
+		ok, checked := allowed[task.Project]
+		if !checked {
+			ok = s.CheckProjectAccess(ctx, task.Project, username, role) == nil
+			allowed[task.Project] = ok
+		}
+		if ok {
+			visible = append(visible, task)
+		}
+	}
+	return visible
+}
+
+func (s TaskService) getProjectAccess(ctx context.Context, projectId string) (*domain.ProjectAccess, error) {
+	url := fmt.Sprintf("http://projects-service:8000/projects/access/%s", projectId)
+
+	r := retrier.New(retrier.ConstantBackoff(3, 100*time.Millisecond), nil)
+
+	var access *domain.ProjectAccess
+	notFound := false
+	err := r.Run(func() error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			log.Println("Error creating request:", err)
+			return fmt.Errorf("failed to create request: %v", err)
+		}
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			log.Println("Failed to fetch project access:", err)
+			return fmt.Errorf("failed to fetch project access: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			notFound = true
+			return nil
+		}
+		if resp.StatusCode != http.StatusOK {
+			log.Printf("Error fetching project access, status code: %d", resp.StatusCode)
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		access = &domain.ProjectAccess{}
+		if err := json.NewDecoder(resp.Body).Decode(access); err != nil {
+			log.Println("Failed to decode project access:", err)
+			return fmt.Errorf("failed to decode project access: %v", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	if notFound {
+		return nil, errors.New("project not found")
+	}
+	return access, nil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"github.com/eapache/go-resiliency/retrier"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordActivity reports a task change to the activity log of its project in
+// projects-service. Failing to record never fails the operation itself.
+func (s TaskService) recordActivity(ctx context.Context, projectId string, action string, taskId string, before map[string]interface{}, after map[string]interface{}) {
+	s.recordTargetActivity(ctx, projectId, action, "task", taskId, before, after)
+}
+
+func (s TaskService) recordTargetActivity(ctx context.Context, projectId string, action string, targetType string, target string, before map[string]interface{}, after map[string]interface{}) {
+	activity := domain.Activity{
+		Actor:      actorFromContext(ctx),
+		Action:     action,
+		TargetType: targetType,
+		Target:     target,
+		Before:     before,
+		After:      after,
+		Source:     "tasks-service",
+		CreatedAt:  time.Now(),
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		activity.TraceId = spanContext.TraceID().String()
+	}
+
+	if err := s.sendActivity(ctx, projectId, activity); err != nil {
+		log.Printf("Error recording activity %s for %s %s: %v\n", action, targetType, target, err)
+	}
+}
+
+func (s TaskService) sendActivity(ctx context.Context, projectId string, activity domain.Activity) error {
+	url := fmt.Sprintf("http://projects-service:8000/projects/%s/activity", projectId)
+
+	jsonData, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity: %v", err)
+	}
+
+	r := retrier.New(retrier.ConstantBackoff(3, 100*time.Millisecond), nil)
+
+	return r.Run(func() error {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send activity: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("failed to send activity: status %d, response: %s", resp.StatusCode, string(body))
+		}
+		return nil
+	})
+}
+
+// taskDiff returns the old and new values of the fields that differ between
+// two versions of a task.
+func taskDiff(previous domain.Task, current domain.Task) (map[string]interface{}, map[string]interface{}) {
+	before := map[string]interface{}{}
+	after := map[string]interface{}{}
+
+	if previous.Name != current.Name {
+		before["name"], after["name"] = previous.Name, current.Name
+	}
+	if previous.Description != current.Description {
+		before["description"], after["description"] = previous.Description, current.Description
+	}
+	if previous.Status != current.Status {
+		before["status"], after["status"] = statusName(previous.Status), statusName(current.Status)
+	}
+	if previous.CurrentState() != current.CurrentState() {
+		before["state"], after["state"] = previous.CurrentState(), current.CurrentState()
+	}
+	if !sameDate(previous.DueDate, current.DueDate) {
+		before["due_date"], after["due_date"] = previous.DueDate, current.DueDate
+	}
+	if previous.Priority != current.Priority {
+		before["priority"], after["priority"] = previous.Priority, current.Priority
+	}
+	if previous.StoryPoints != current.StoryPoints {
+		before["story_points"], after["story_points"] = previous.StoryPoints, current.StoryPoints
+	}
+	if previous.EstimateHours != current.EstimateHours {
+		before["estimate_hours"], after["estimate_hours"] = previous.EstimateHours, current.EstimateHours
+	}
+
+	return before, after
+}
+
+func sameDate(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func statusName(status domain.Status) string {
+	if status < domain.PENDING || status > domain.FINISHED {
+		return fmt.Sprintf("%d", int(status))
+	}
+	return status.String()
+}
+
+func actorFromContext(ctx context.Context) string {
+	if username, ok := ctx.Value(authorizationlib.UsernameKey).(string); ok {
+		return username
+	}
+	return "system"
+}
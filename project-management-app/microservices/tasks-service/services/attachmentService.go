@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AttachmentLimits restricts what can be uploaded. AllowedTypes holds MIME
+// types, entries ending with "/" allow a whole family such as "image/".
+type AttachmentLimits struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+// MaxAttachmentSize returns the largest accepted upload in bytes, or 0 when
+// uploads are not limited.
+func (s TaskService) MaxAttachmentSize() int64 {
+	return s.limits.MaxSize
+}
+
+// AddAttachment stores a file on a task. The content type is detected from
+// the content, and when the client sent a SHA-256 checksum the content must
+// match it.
+func (s TaskService) AddAttachment(ctx context.Context, taskId string, username string, role string, fileName string, content io.ReadSeeker, size int64, checksum string) (*domain.Attachment, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.AddAttachment")
+	defer span.End()
+
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: empty file", domain.ErrInvalidAttachment())
+	}
+	if s.limits.MaxSize > 0 && size > s.limits.MaxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", domain.ErrAttachmentTooLarge(), s.limits.MaxSize)
+	}
+
+	task, err := s.findWorkTask(taskId, username, role)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, err := detectContentType(content)
+	if err != nil {
+		return nil, err
+	}
+	if !s.limits.allows(contentType) {
+		return nil, fmt.Errorf("%w: type %s is not allowed", domain.ErrInvalidAttachment(), contentType)
+	}
+
+	digest := sha256.New()
+	if _, err := io.Copy(digest, content); err != nil {
+		return nil, err
+	}
+	sum := hex.EncodeToString(digest.Sum(nil))
+	if checksum != "" && !strings.EqualFold(checksum, sum) {
+		return nil, fmt.Errorf("%w: checksum mismatch", domain.ErrInvalidAttachment())
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	attachment := &domain.Attachment{
+		Id:          primitive.NewObjectID(),
+		Task:        taskId,
+		Project:     task.Project,
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    sum,
+		UploadedBy:  username,
+		CreatedAt:   time.Now(),
+	}
+	attachment.StorageKey = path.Join(task.Project, taskId, attachment.Id.Hex())
+
+	if err := s.blobs.Put(ctx, attachment.StorageKey, content, size, contentType, sum); err != nil {
+		return nil, err
+	}
+	if err := s.tasks.InsertAttachment(ctx, attachment); err != nil {
+		if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			log.Printf("Error removing orphaned attachment %s: %v\n", attachment.StorageKey, err)
+		}
+		return nil, err
+	}
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_ATTACHMENT_ADDED, taskId, nil, map[string]interface{}{"attachment": attachment.Id.Hex(), "file_name": attachment.FileName})
+	return attachment, nil
+}
+
+func (s TaskService) GetAttachments(ctx context.Context, taskId string) (domain.Attachments, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetAttachments")
+	defer span.End()
+
+	if _, err := s.findTask(taskId); err != nil {
+		return nil, err
+	}
+	return s.tasks.GetAttachmentsByTask(ctx, taskId)
+}
+
+// OpenAttachment returns an attachment with a reader of its content. The
+// reader fails at the end of the content if it doesn't match the checksum
+// taken on upload.
+func (s TaskService) OpenAttachment(ctx context.Context, id string) (*domain.Attachment, io.ReadCloser, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.OpenAttachment")
+	defer span.End()
+
+	attachment, err := s.tasks.GetAttachmentById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, errors.New("attachment not found")
+	}
+
+	content, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, &checksumReader{ReadCloser: content, digest: sha256.New(), expected: attachment.Checksum}, nil
+}
+
+// DeleteAttachment removes an attachment. Members can only remove the files
+// they uploaded.
+func (s TaskService) DeleteAttachment(ctx context.Context, id string, username string, role string) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.DeleteAttachment")
+	defer span.End()
+
+	attachment, err := s.tasks.GetAttachmentById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if attachment == nil {
+		return errors.New("attachment not found")
+	}
+	if attachment.UploadedBy != username && role != "PROJECT_MANAGER" {
+		return domain.ErrUnauthorized()
+	}
+
+	if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+	if err := s.tasks.DeleteAttachment(ctx, attachment.Id); err != nil {
+		return err
+	}
+
+	s.recordActivity(ctx, attachment.Project, domain.ACTIVITY_ATTACHMENT_REMOVED, attachment.Task, map[string]interface{}{"attachment": id, "file_name": attachment.FileName}, nil)
+	return nil
+}
+
+// deleteTaskAttachments removes all attachments of a task together with their
+// content. It is used when a task is deleted.
+func (s TaskService) deleteTaskAttachments(ctx context.Context, taskId string) error {
+	attachments, err := s.tasks.GetAttachmentsByTask(ctx, taskId)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			return err
+		}
+		if err := s.tasks.DeleteAttachment(ctx, attachment.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l AttachmentLimits) allows(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	for _, allowed := range l.AllowedTypes {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "" {
+			continue
+		}
+		if strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed) || mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContentType sniffs the type of the content and rewinds it.
+func detectContentType(content io.ReadSeeker) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+// checksumReader hashes the content while it is read and reports a mismatch
+// instead of io.EOF at the end.
+type checksumReader struct {
+	io.ReadCloser
+	digest   hash.Hash
+	expected string
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.digest.Write(p[:n])
+	if err == io.EOF && r.expected != "" && hex.EncodeToString(r.digest.Sum(nil)) != r.expected {
+		return n, fmt.Errorf("%w: stored content doesn't match its checksum", domain.ErrInvalidAttachment())
+	}
+	return n, err
+}
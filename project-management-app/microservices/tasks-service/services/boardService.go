@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetBoard returns the kanban board of a project with the tasks of every
+// column in board order.
+func (s TaskService) GetBoard(ctx context.Context, projectId string) (*domain.Board, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetBoard")
+	defer span.End()
+
+	workflow, err := s.GetWorkflow(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.tasks.GetByProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	return buildBoard(projectId, workflow, tasks), nil
+}
+
+// MoveTask moves a task to a position on the board, changing its state when
+// it lands in another column. Only the moved task is written, at the version
+// the move was checked against. With a version the move fails with
+// domain.ErrVersionConflict when the task is at another one, without it a
+// move that loses a race with another change is checked again.
+func (s TaskService) MoveTask(ctx context.Context, taskId string, username string, role string, move domain.Move, version *int64) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.MoveTask")
+	defer span.End()
+
+	for attempt := 1; ; attempt++ {
+		task, err := s.moveTask(ctx, taskId, username, role, move, version)
+		if errors.Is(err, domain.ErrVersionConflict()) && version == nil && attempt < 3 {
+			continue
+		}
+		return task, err
+	}
+}
+
+func (s TaskService) moveTask(ctx context.Context, taskId string, username string, role string, move domain.Move, version *int64) (*domain.Task, error) {
+	task, err := s.findWorkTask(taskId, username, role)
+	if err != nil {
+		return nil, err
+	}
+	if version != nil && task.Version != *version {
+		return nil, fmt.Errorf("%w: current version is %d", domain.ErrVersionConflict(), task.Version)
+	}
+	workflow, err := s.GetWorkflow(ctx, task.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	state := move.State
+	if state == "" {
+		state = boardState(workflow, task)
+	}
+	target, ok := workflow.State(state)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown state %s", domain.ErrInvalidMove(), state)
+	}
+
+	tasks, err := s.tasks.GetByProject(ctx, task.Project)
+	if err != nil {
+		return nil, err
+	}
+	column, _ := buildBoard(task.Project, workflow, tasks).Column(target.Name)
+	others := withoutTasks(column.Tasks, map[string]bool{taskId: true})
+
+	position, err := movePosition(others, move)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rankColumn(ctx, others); err != nil {
+		return nil, err
+	}
+	prev, next := "", ""
+	if position > 0 {
+		prev = others[position-1].Rank
+	}
+	if position < len(others) {
+		next = others[position].Rank
+	}
+	rank, err := domain.RankBetween(prev, next)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *task
+	transition, err := s.moveToState(ctx, workflow, task, target)
+	if err != nil {
+		return nil, err
+	}
+	task.Rank = rank
+
+	if _, err := s.tasks.MoveTasks(ctx, domain.Tasks{task}); err != nil {
+		return nil, err
+	}
+	task.Version++
+	s.afterMove(ctx, previous, *task, transition)
+	return task, nil
+}
+
+// BulkMoveTasks moves several tasks of a project to the bottom of a column.
+// Every move is validated before any task is written. The tasks are written
+// in order, each at the version it was checked against. When one of them has
+// changed in the meantime the move is checked again if nothing was written
+// yet and no versions were given, otherwise it stops there and the error
+// names the tasks that moved.
+func (s TaskService) BulkMoveTasks(ctx context.Context, bulk domain.BulkMove, username string, role string) (domain.Tasks, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.BulkMoveTasks")
+	defer span.End()
+
+	if len(bulk.Tasks) == 0 {
+		return nil, fmt.Errorf("%w: no tasks given", domain.ErrInvalidMove())
+	}
+
+	for attempt := 1; ; attempt++ {
+		tasks, err := s.bulkMoveTasks(ctx, bulk, username, role)
+		if errors.Is(err, domain.ErrVersionConflict()) && len(tasks) == 0 && len(bulk.Versions) == 0 && attempt < 3 {
+			continue
+		}
+		return tasks, err
+	}
+}
+
+// bulkMoveTasks returns the tasks that were moved, also when it fails
+// partway.
+func (s TaskService) bulkMoveTasks(ctx context.Context, bulk domain.BulkMove, username string, role string) (domain.Tasks, error) {
+	moving := map[string]bool{}
+	tasks := domain.Tasks{}
+	for _, taskId := range bulk.Tasks {
+		if moving[taskId] {
+			return nil, fmt.Errorf("%w: task %s is given twice", domain.ErrInvalidMove(), taskId)
+		}
+		moving[taskId] = true
+
+		task, err := s.findWorkTask(taskId, username, role)
+		if err != nil {
+			return nil, err
+		}
+		if version, ok := bulk.Versions[taskId]; ok && task.Version != version {
+			return nil, fmt.Errorf("%w: task %s is at version %d", domain.ErrVersionConflict(), task.Name, task.Version)
+		}
+		if len(tasks) > 0 && task.Project != tasks[0].Project {
+			return nil, fmt.Errorf("%w: tasks must belong to the same project", domain.ErrInvalidMove())
+		}
+		tasks = append(tasks, task)
+	}
+	projectId := tasks[0].Project
+	if err := s.CheckProjectAccess(ctx, projectId, username, role); err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.GetWorkflow(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	target, ok := workflow.State(bulk.State)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown state %s", domain.ErrInvalidMove(), bulk.State)
+	}
+
+	projectTasks, err := s.tasks.GetByProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	column, _ := buildBoard(projectId, workflow, projectTasks).Column(target.Name)
+	others := withoutTasks(column.Tasks, moving)
+	if err := s.rankColumn(ctx, others); err != nil {
+		return nil, err
+	}
+	last := ""
+	if len(others) > 0 {
+		last = others[len(others)-1].Rank
+	}
+
+	previous := make([]domain.Task, len(tasks))
+	transitions := make([]*domain.Transition, len(tasks))
+	for i, task := range tasks {
+		previous[i] = *task
+		if transitions[i], err = s.moveToState(ctx, workflow, task, target); err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.Name, err)
+		}
+		if task.Rank, err = domain.RankBetween(last, ""); err != nil {
+			return nil, err
+		}
+		last = task.Rank
+	}
+
+	moved, err := s.tasks.MoveTasks(ctx, tasks)
+	for i, task := range tasks[:moved] {
+		task.Version++
+		s.afterMove(ctx, previous[i], *task, transitions[i])
+	}
+	if err != nil && moved > 0 {
+		names := []string{}
+		for _, task := range tasks[:moved] {
+			names = append(names, task.Name)
+		}
+		err = fmt.Errorf("%w; only %s moved", err, strings.Join(names, ", "))
+	}
+	return tasks[:moved], err
+}
+
+// moveToState changes the state of a task in memory after checking the
+// workflow and the blockers of the task. It returns the transition taken,
+// nil when the task stays in its state.
+func (s TaskService) moveToState(ctx context.Context, workflow *domain.Workflow, task *domain.Task, target domain.WorkflowState) (*domain.Transition, error) {
+	from := task.CurrentState()
+	if target.Name == from {
+		return nil, nil
+	}
+
+	transition, err := checkTransition(ctx, workflow, from, target.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkBlockers(ctx, task, target); err != nil {
+		return nil, err
+	}
+
+	wasFinished := task.Status == domain.FINISHED
+	task.State = target.Name
+	task.Status = target.CategoryStatus()
+	if task.Status == domain.FINISHED && !wasFinished {
+		now := time.Now()
+		task.FinishedAt = &now
+	} else if task.Status != domain.FINISHED {
+		task.FinishedAt = nil
+	}
+	return &transition, nil
+}
+
+// afterMove records a state change made on the board and runs its hooks.
+// Moves within a column are not recorded.
+func (s TaskService) afterMove(ctx context.Context, previous domain.Task, task domain.Task, transition *domain.Transition) {
+	if transition == nil {
+		return
+	}
+	before, after := taskDiff(previous, task)
+	s.recordRevision(ctx, task, before, after)
+	s.recordStatusEvents(ctx, statusEvent(ctx, domain.EVENT_CHANGED, &previous, &task))
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_TASK_STATUS_CHANGED, task.Id.Hex(), before, after)
+	s.runTransitionHooks(task, *transition)
+	s.recurOnFinish(ctx, previous, task)
+}
+
+// rankColumn gives the tasks of a column evenly spaced ranks, keeping their
+// order, unless they already have distinct ranks.
+func (s TaskService) rankColumn(ctx context.Context, tasks domain.Tasks) error {
+	ranked := true
+	for i, task := range tasks {
+		if task.Rank == "" || (i > 0 && tasks[i-1].Rank >= task.Rank) {
+			ranked = false
+			break
+		}
+	}
+	if ranked {
+		return nil
+	}
+
+	ranks := map[primitive.ObjectID]string{}
+	for i, rank := range domain.SpreadRanks(len(tasks)) {
+		tasks[i].Rank = rank
+		ranks[tasks[i].Id] = rank
+	}
+	return s.tasks.SetRanks(ctx, ranks)
+}
+
+func buildBoard(projectId string, workflow *domain.Workflow, tasks domain.Tasks) *domain.Board {
+	board := &domain.Board{Project: projectId, Columns: []*domain.BoardColumn{}}
+	for _, state := range workflow.States {
+		board.Columns = append(board.Columns, &domain.BoardColumn{State: state.Name, Category: state.Category, Tasks: domain.Tasks{}})
+	}
+	for _, task := range tasks {
+		if column, ok := board.Column(boardState(workflow, task)); ok {
+			column.Tasks = append(column.Tasks, task)
+		}
+	}
+	for _, column := range board.Columns {
+		domain.SortByRank(column.Tasks)
+	}
+	return board
+}
+
+// boardState returns the column of a task. Tasks in a state the workflow no
+// longer has are shown in the first state of their status.
+func boardState(workflow *domain.Workflow, task *domain.Task) string {
+	if state, ok := workflow.State(task.CurrentState()); ok {
+		return state.Name
+	}
+	state, _ := workflow.StateForStatus(task.Status)
+	return state.Name
+}
+
+// movePosition returns the index in the column a task is moved to.
+func movePosition(column domain.Tasks, move domain.Move) (int, error) {
+	anchor, offset := move.After, 1
+	if anchor == "" {
+		anchor, offset = move.Before, 0
+	}
+	if anchor == "" {
+		return len(column), nil
+	}
+	for i, task := range column {
+		if task.Id.Hex() == anchor {
+			return i + offset, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: task %s is not in column %s", domain.ErrInvalidMove(), anchor, move.State)
+}
+
+func withoutTasks(tasks domain.Tasks, ids map[string]bool) domain.Tasks {
+	result := domain.Tasks{}
+	for _, task := range tasks {
+		if !ids[task.Id.Hex()] {
+			result = append(result, task)
+		}
+	}
+	return result
+}
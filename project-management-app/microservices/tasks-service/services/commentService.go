@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCommentPageSize = 20
+	maxCommentPageSize     = 100
+)
+
+// AddComment adds a comment to a task, or a reply when parent is set. Every
+// @mention must be a member of the project and gets a notification.
+func (s TaskService) AddComment(ctx context.Context, taskId string, parent string, author string, body string) (*domain.Comment, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.AddComment")
+	defer span.End()
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: comment body is required", domain.ErrInvalidInput())
+	}
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	if parent != "" {
+		thread, err := s.tasks.GetCommentById(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		if thread == nil || thread.Task != taskId {
+			return nil, errors.New("parent comment not found")
+		}
+		// replies always belong to the top-level comment of the thread
+		if thread.Parent != "" {
+			parent = thread.Parent
+		}
+	}
+
+	mentions, err := s.validateMentions(task.Project, body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &domain.Comment{
+		Task:      taskId,
+		Project:   task.Project,
+		Parent:    parent,
+		Author:    author,
+		Body:      body,
+		Mentions:  mentions,
+		CreatedAt: time.Now(),
+	}
+	if err := s.tasks.InsertComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(task, author, mentions)
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_COMMENT_ADDED, taskId, nil, map[string]interface{}{"comment": comment.Id.Hex(), "mentions": mentions})
+	return comment, nil
+}
+
+// EditComment changes the body of a comment. Only its author may edit it and
+// only newly mentioned users are notified.
+func (s TaskService) EditComment(ctx context.Context, id string, username string, body string) (*domain.Comment, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.EditComment")
+	defer span.End()
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: comment body is required", domain.ErrInvalidInput())
+	}
+
+	comment, err := s.findOwnComment(ctx, id, username)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := s.validateMentions(comment.Project, body)
+	if err != nil {
+		return nil, err
+	}
+	previous := map[string]bool{}
+	for _, mention := range comment.Mentions {
+		previous[mention] = true
+	}
+	added := []string{}
+	for _, mention := range mentions {
+		if !previous[mention] {
+			added = append(added, mention)
+		}
+	}
+
+	now := time.Now()
+	comment.Body = body
+	comment.Mentions = mentions
+	comment.EditedAt = &now
+	if err := s.tasks.UpdateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	if task, err := s.tasks.FindById(comment.Task); err == nil && task != nil {
+		s.notifyMentions(task, username, added)
+	}
+	return comment, nil
+}
+
+// DeleteComment removes the content of a comment. The comment itself stays so
+// that the replies of its thread keep their place.
+func (s TaskService) DeleteComment(ctx context.Context, id string, username string) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.DeleteComment")
+	defer span.End()
+
+	comment, err := s.findOwnComment(ctx, id, username)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	comment.Body = ""
+	comment.Mentions = nil
+	comment.Deleted = true
+	comment.EditedAt = &now
+	return s.tasks.UpdateComment(ctx, comment)
+}
+
+// GetComments returns a page of the comment threads of a task.
+func (s TaskService) GetComments(ctx context.Context, taskId string, page int, size int) (*domain.CommentPage, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetComments")
+	defer span.End()
+
+	if _, err := s.findTask(taskId); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultCommentPageSize
+	} else if size > maxCommentPageSize {
+		size = maxCommentPageSize
+	}
+
+	return s.tasks.GetCommentThreads(ctx, taskId, page, size)
+}
+
+func (s TaskService) findOwnComment(ctx context.Context, id string, username string) (*domain.Comment, error) {
+	comment, err := s.tasks.GetCommentById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.Deleted {
+		return nil, errors.New("comment not found")
+	}
+	if comment.Author != username {
+		return nil, domain.ErrUnauthorized()
+	}
+	return comment, nil
+}
+
+// validateMentions returns the users mentioned in a text and fails when any
+// of them is not a member of the project.
+func (s TaskService) validateMentions(projectId string, body string) ([]string, error) {
+	mentions := domain.ParseMentions(body)
+	if len(mentions) == 0 {
+		return mentions, nil
+	}
+
+	members, err := s.getProjectMembers(projectId)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, member := range members {
+		known[member.Username] = true
+	}
+
+	unknown := []string{}
+	for _, mention := range mentions {
+		if !known[mention] {
+			unknown = append(unknown, mention)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidMention(), strings.Join(unknown, ", "))
+	}
+	return mentions, nil
+}
+
+func (s TaskService) notifyMentions(task *domain.Task, author string, mentions []string) {
+	for _, username := range mentions {
+		if username == author {
+			continue
+		}
+		message := fmt.Sprintf("%s mentioned you on task %s", author, task.Name)
+		if err := s.sendNotification(username, message); err != nil {
+			log.Printf("Error sending mention notification to %s: %v\n", username, err)
+		}
+	}
+}
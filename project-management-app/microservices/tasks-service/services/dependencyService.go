@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+)
+
+// AddDependency marks a task as blocked by another task of the same project.
+// Links that would close a cycle are refused.
+func (s TaskService) AddDependency(ctx context.Context, taskId string, blockerId string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.AddDependency")
+	defer span.End()
+
+	if taskId == blockerId {
+		return nil, fmt.Errorf("%w: a task can't block itself", domain.ErrDependencyCycle())
+	}
+
+	task, err := s.tasks.FindById(taskId)
+	if err != nil {
+		return nil, err
+	}
+	blocker, err := s.tasks.FindById(blockerId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || blocker == nil {
+		return nil, errors.New("task not found")
+	}
+	if task.Project != blocker.Project {
+		return nil, fmt.Errorf("%w: dependent tasks must belong to the same project", domain.ErrInvalidInput())
+	}
+	for _, id := range task.BlockedBy {
+		if id == blockerId {
+			return task, nil
+		}
+	}
+
+	tasks, err := s.tasks.GetByProject(ctx, task.Project)
+	if err != nil {
+		return nil, err
+	}
+	if path := dependencyPath(tasks, blockerId, taskId); path != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrDependencyCycle(), strings.Join(append(path, blockerId), " -> "))
+	}
+
+	if err := s.tasks.AddDependency(ctx, task.Id, blockerId); err != nil {
+		return nil, err
+	}
+	task.BlockedBy = append(task.BlockedBy, blockerId)
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_DEPENDENCY_ADDED, task.Id.Hex(), nil, map[string]interface{}{"blocked_by": blockerId})
+	return task, nil
+}
+
+// RemoveDependency removes a blocker from a task.
+func (s TaskService) RemoveDependency(ctx context.Context, taskId string, blockerId string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.RemoveDependency")
+	defer span.End()
+
+	task, err := s.tasks.FindById(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, errors.New("task not found")
+	}
+
+	blockedBy := []string{}
+	for _, id := range task.BlockedBy {
+		if id != blockerId {
+			blockedBy = append(blockedBy, id)
+		}
+	}
+	if len(blockedBy) == len(task.BlockedBy) {
+		return nil, errors.New("dependency not found")
+	}
+
+	if err := s.tasks.RemoveDependency(ctx, task.Id, blockerId); err != nil {
+		return nil, err
+	}
+	task.BlockedBy = blockedBy
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_DEPENDENCY_REMOVED, task.Id.Hex(), map[string]interface{}{"blocked_by": blockerId}, nil)
+	return task, nil
+}
+
+// GetDependencies returns the tasks blocking a task and the tasks it blocks.
+func (s TaskService) GetDependencies(ctx context.Context, taskId string) (*domain.TaskDependencies, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetDependencies")
+	defer span.End()
+
+	task, err := s.tasks.FindById(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, errors.New("task not found")
+	}
+
+	tasks, err := s.tasks.GetByProject(ctx, task.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	blockers := map[string]bool{}
+	for _, id := range task.BlockedBy {
+		blockers[id] = true
+	}
+
+	dependencies := &domain.TaskDependencies{Task: taskId, BlockedBy: domain.Tasks{}, Blocks: domain.Tasks{}}
+	for _, other := range tasks {
+		if blockers[other.Id.Hex()] {
+			dependencies.BlockedBy = append(dependencies.BlockedBy, other)
+		}
+		for _, id := range other.BlockedBy {
+			if id == taskId {
+				dependencies.Blocks = append(dependencies.Blocks, other)
+				break
+			}
+		}
+	}
+	return dependencies, nil
+}
+
+// GetDependencyGraph returns all tasks of a project with the dependencies
+// between them.
+func (s TaskService) GetDependencyGraph(ctx context.Context, projectId string) (*domain.DependencyGraph, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetDependencyGraph")
+	defer span.End()
+
+	tasks, err := s.tasks.GetByProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := tasksById(tasks)
+	graph := &domain.DependencyGraph{Project: projectId, Nodes: []domain.DependencyNode{}, Edges: []domain.DependencyEdge{}}
+	for _, task := range tasks {
+		id := task.Id.Hex()
+		graph.Nodes = append(graph.Nodes, domain.DependencyNode{
+			Id:      id,
+			Name:    task.Name,
+			Status:  statusName(task.Status),
+			State:   task.CurrentState(),
+			Blocked: len(openBlockers(task, byId)) > 0,
+		})
+		for _, blockerId := range task.BlockedBy {
+			if _, ok := byId[blockerId]; ok {
+				graph.Edges = append(graph.Edges, domain.DependencyEdge{From: blockerId, To: id})
+			}
+		}
+	}
+	return graph, nil
+}
+
+// checkBlockers refuses to start or finish a task while any of its blockers
+// is still open.
+func (s TaskService) checkBlockers(ctx context.Context, task *domain.Task, target domain.WorkflowState) error {
+	category := target.CategoryStatus()
+	if len(task.BlockedBy) == 0 || (category != domain.IN_PROGRESS && category != domain.FINISHED) {
+		return nil
+	}
+
+	tasks, err := s.tasks.GetByProject(ctx, task.Project)
+	if err != nil {
+		return err
+	}
+	if open := openBlockers(task, tasksById(tasks)); len(open) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrTaskBlocked(), strings.Join(open, ", "))
+	}
+	return nil
+}
+
+// openBlockers returns the names of the unfinished tasks blocking a task.
+func openBlockers(task *domain.Task, byId map[string]*domain.Task) []string {
+	open := []string{}
+	for _, id := range task.BlockedBy {
+		if blocker, ok := byId[id]; ok && blocker.Status != domain.FINISHED {
+			open = append(open, blocker.Name)
+		}
+	}
+	return open
+}
+
+// dependencyPath returns the chain of task IDs through which from is
+// (transitively) blocked by to, nil when it isn't.
+func dependencyPath(tasks domain.Tasks, from string, to string) []string {
+	byId := tasksById(tasks)
+	visited := map[string]bool{}
+
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if id == to {
+			return []string{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+
+		task, ok := byId[id]
+		if !ok {
+			return nil
+		}
+		for _, blockerId := range task.BlockedBy {
+			if path := walk(blockerId); path != nil {
+				return append([]string{id}, path...)
+			}
+		}
+		return nil
+	}
+	return walk(from)
+}
+
+func tasksById(tasks domain.Tasks) map[string]*domain.Task {
+	byId := map[string]*domain.Task{}
+	for _, task := range tasks {
+		byId[task.Id.Hex()] = task
+	}
+	return byId
+}
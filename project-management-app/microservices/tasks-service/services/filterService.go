@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QueryTasks returns the tasks matching a query, newest first.
+func (s TaskService) QueryTasks(ctx context.Context, query domain.TaskQuery) (domain.Tasks, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.QueryTasks")
+	defer span.End()
+
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	return s.tasks.QueryTasks(ctx, query)
+}
+
+func (s TaskService) GetFilters(ctx context.Context, owner string) (domain.SavedFilters, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetFilters")
+	defer span.End()
+
+	return s.tasks.GetFilters(ctx, owner)
+}
+
+// SaveFilter stores a named query for a user. Filter names are unique per
+// user.
+func (s TaskService) SaveFilter(ctx context.Context, owner string, filter *domain.SavedFilter) (*domain.SavedFilter, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.SaveFilter")
+	defer span.End()
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := s.tasks.GetFilterByName(ctx, owner, filter.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrFilterExists(), filter.Name)
+	}
+
+	filter.Id = primitive.NewObjectID()
+	filter.Owner = owner
+	filter.CreatedAt = time.Now()
+	if err := s.tasks.InsertFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+func (s TaskService) DeleteFilter(ctx context.Context, owner string, id string) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.DeleteFilter")
+	defer span.End()
+
+	filter, err := s.findFilter(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+	return s.tasks.DeleteFilter(ctx, filter.Id)
+}
+
+// RunFilter returns the tasks matching a saved filter of the user.
+func (s TaskService) RunFilter(ctx context.Context, owner string, id string) (domain.Tasks, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.RunFilter")
+	defer span.End()
+
+	filter, err := s.findFilter(ctx, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.tasks.QueryTasks(ctx, filter.Query)
+}
+
+func (s TaskService) findFilter(ctx context.Context, owner string, id string) (*domain.SavedFilter, error) {
+	filter, err := s.tasks.GetFilter(ctx, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return nil, errors.New("filter not found")
+	}
+	return filter, nil
+}
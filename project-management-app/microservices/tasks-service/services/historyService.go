@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultHistoryPageSize = 20
+	maxHistoryPageSize     = 100
+)
+
+// revertKey marks the context of an update made by a revert with the version
+// the task is reverted to.
+type revertKey struct{}
+
+// GetHistory returns a page of the revisions of a task, newest first.
+func (s TaskService) GetHistory(ctx context.Context, taskId string, page int, size int) (*domain.RevisionPage, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetHistory")
+	defer span.End()
+
+	if _, err := s.findTask(taskId); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultHistoryPageSize
+	} else if size > maxHistoryPageSize {
+		size = maxHistoryPageSize
+	}
+
+	return s.tasks.GetRevisions(ctx, taskId, page, size)
+}
+
+// RevertTask sets the tracked fields of a task back to their values at an
+// earlier version. The revert is an update of its own and is recorded as a
+// new revision. Only versions with a revision in the history can be reverted
+// to: label, checklist, dependency, sprint and recurrence changes bump the
+// version without recording one, and are rejected with ErrInvalidRevision.
+func (s TaskService) RevertTask(ctx context.Context, taskId string, version int64) (domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.RevertTask")
+	defer span.End()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if version < 0 || version >= task.Version {
+		return domain.Task{}, fmt.Errorf("%w: version must be before the current version %d", domain.ErrInvalidRevision(), task.Version)
+	}
+
+	revisions, err := s.tasks.GetRevisionsSince(ctx, taskId, version)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if len(revisions) == 0 || revisions[len(revisions)-1].Version != version {
+		return domain.Task{}, fmt.Errorf("%w: version %d has no revision, only versions listed in the history can be reverted to", domain.ErrInvalidRevision(), version)
+	}
+
+	// Undo the later revisions from the newest, so the oldest old value of
+	// every field is the one left.
+	values := map[string]interface{}{}
+	for _, revision := range revisions {
+		if revision.Version == version {
+			continue
+		}
+		for _, change := range revision.Changes {
+			values[change.Field] = change.Old
+		}
+	}
+	if len(values) == 0 {
+		return *task, nil
+	}
+
+	patch, err := revertPatch(taskId, values)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return s.Update(context.WithValue(ctx, revertKey{}, version), patch, &task.Version)
+}
+
+// recordRevision stores the changed fields of a task as a revision. Failing
+// to record never fails the change itself.
+func (s TaskService) recordRevision(ctx context.Context, task domain.Task, before map[string]interface{}, after map[string]interface{}) {
+	revision := &domain.Revision{
+		Id:        primitive.NewObjectID(),
+		Task:      task.Id.Hex(),
+		Project:   task.Project,
+		Version:   task.Version,
+		Actor:     actorFromContext(ctx),
+		Changes:   []domain.FieldChange{},
+		CreatedAt: time.Now(),
+	}
+	if version, ok := ctx.Value(revertKey{}).(int64); ok {
+		revision.RevertedTo = &version
+	}
+	for field, value := range after {
+		revision.Changes = append(revision.Changes, domain.FieldChange{Field: field, Old: before[field], New: value})
+	}
+	sort.Slice(revision.Changes, func(i, j int) bool {
+		return revision.Changes[i].Field < revision.Changes[j].Field
+	})
+
+	if err := s.tasks.InsertRevision(ctx, revision); err != nil {
+		log.Printf("Error recording revision %d of task %s: %v\n", task.Version, task.Id.Hex(), err)
+	}
+}
+
+// revertPatch turns the stored values of fields back into a patch. The
+// state decides the status when both are reverted.
+func revertPatch(taskId string, values map[string]interface{}) (domain.TaskPatch, error) {
+	patch := domain.TaskPatch{Id: taskId}
+	for field, value := range values {
+		switch field {
+		case "name":
+			name := fmt.Sprint(value)
+			patch.Name = &name
+		case "description":
+			description := fmt.Sprint(value)
+			patch.Description = &description
+		case "state":
+			state := fmt.Sprint(value)
+			patch.State = &state
+		case "status":
+			if _, ok := values["state"]; ok {
+				continue
+			}
+			status, err := domain.StatusFromString(fmt.Sprint(value))
+			if err != nil {
+				return patch, fmt.Errorf("%w: %v", domain.ErrInvalidRevision(), err)
+			}
+			patch.Status = &status
+		case "due_date":
+			patch.DueDate = revisionTime(value)
+			patch.ClearDueDate = patch.DueDate == nil
+		case "priority":
+			if priority := domain.Priority(fmt.Sprint(value)); priority.Valid() {
+				patch.Priority = &priority
+			}
+		case "story_points":
+			points := revisionFloat(value)
+			patch.StoryPoints = &points
+		case "estimate_hours":
+			hours := revisionFloat(value)
+			patch.EstimateHours = &hours
+		}
+	}
+	return patch, nil
+}
+
+func revisionTime(value interface{}) *time.Time {
+	switch v := value.(type) {
+	case primitive.DateTime:
+		t := v.Time()
+		return &t
+	case time.Time:
+		return &v
+	case *time.Time:
+		return v
+	default:
+		return nil
+	}
+}
+
+func revisionFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+	"time"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// importProgressEvery is how many rows are imported between saves of the
+// job's progress.
+const importProgressEvery = 25
+
+// importLabelColor is the color of labels created by an import.
+const importLabelColor = "#6b778c"
+
+// StartImport reads an import file and imports its rows into a project in
+// the background. The file is read right away, so a malformed file fails
+// the request instead of the job.
+func (s TaskService) StartImport(ctx context.Context, projectId string, source string, fileName string, file io.Reader, mapping domain.ImportMapping, dryRun bool) (*domain.ImportJob, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.StartImport")
+	defer span.End()
+
+	rows, err := domain.ParseImport(source, file, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &domain.ImportJob{
+		Id:        primitive.NewObjectID(),
+		Project:   projectId,
+		Source:    source,
+		FileName:  fileName,
+		DryRun:    dryRun,
+		State:     domain.IMPORT_QUEUED,
+		Total:     len(rows),
+		Results:   []*domain.ImportRowResult{},
+		CreatedBy: actorFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := s.tasks.InsertImportJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// The job outlives the request, it only keeps the user who started it.
+	jobCtx := context.WithValue(context.Background(), authorizationlib.UsernameKey, job.CreatedBy)
+	queued := *job
+	go s.runImport(jobCtx, job, rows)
+	return &queued, nil
+}
+
+func (s TaskService) GetImportJob(ctx context.Context, id string) (*domain.ImportJob, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetImportJob")
+	defer span.End()
+
+	job, err := s.tasks.GetImportJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, errors.New("import not found")
+	}
+	return job, nil
+}
+
+func (s TaskService) GetImportJobs(ctx context.Context, projectId string) (domain.ImportJobs, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetImportJobs")
+	defer span.End()
+
+	return s.tasks.GetImportJobs(ctx, projectId)
+}
+
+// FailInterruptedImports marks the jobs that were queued or running when
+// the service stopped as failed. Their finished rows stay imported.
+func (s TaskService) FailInterruptedImports(ctx context.Context) (int64, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.FailInterruptedImports")
+	defer span.End()
+
+	return s.tasks.FailInterruptedImports(ctx, time.Now())
+}
+
+func (s TaskService) runImport(ctx context.Context, job *domain.ImportJob, rows []*domain.ImportRow) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.runImport")
+	defer span.End()
+
+	started := time.Now()
+	job.State = domain.IMPORT_RUNNING
+	job.StartedAt = &started
+	s.saveImportJob(ctx, job)
+
+	importer, err := s.newTaskImporter(ctx, job.Project)
+	if err != nil {
+		s.finishImport(ctx, job, err)
+		return
+	}
+	for i, row := range rows {
+		result := importer.importRow(ctx, row, job.DryRun)
+		job.Results = append(job.Results, result)
+		job.Processed++
+		switch result.Outcome {
+		case domain.ROW_IMPORTED:
+			job.Imported++
+		case domain.ROW_FAILED:
+			job.Failed++
+		}
+		if (i+1)%importProgressEvery == 0 {
+			s.saveImportJob(ctx, job)
+		}
+	}
+	s.finishImport(ctx, job, nil)
+}
+
+func (s TaskService) finishImport(ctx context.Context, job *domain.ImportJob, err error) {
+	finished := time.Now()
+	job.FinishedAt = &finished
+	job.State = domain.IMPORT_DONE
+	if err != nil {
+		job.State = domain.IMPORT_FAILED
+		job.Error = err.Error()
+	}
+	s.saveImportJob(ctx, job)
+
+	if job.DryRun {
+		return
+	}
+	if job.Imported > 0 {
+		s.recordTargetActivity(ctx, job.Project, domain.ACTIVITY_TASKS_IMPORTED, "import", job.Id.Hex(), nil, map[string]interface{}{
+			"source":   job.Source,
+			"file":     job.FileName,
+			"imported": job.Imported,
+			"failed":   job.Failed,
+		})
+	}
+	message := fmt.Sprintf("Import of %s finished: %d tasks imported, %d failed", job.FileName, job.Imported, job.Failed)
+	if err != nil {
+		message = fmt.Sprintf("Import of %s failed: %v", job.FileName, err)
+	}
+	if err := s.sendNotification(job.CreatedBy, message); err != nil {
+		log.Printf("Error sending notification: %v\n", err)
+	}
+}
+
+func (s TaskService) saveImportJob(ctx context.Context, job *domain.ImportJob) {
+	if err := s.tasks.UpdateImportJob(ctx, job); err != nil {
+		log.Printf("Error saving import job %s: %v\n", job.Id.Hex(), err)
+	}
+}
+
+// taskImporter checks the rows of an import against a project and creates
+// their tasks. It remembers the names of the rows it accepted, so a file
+// can't hold the same task twice.
+type taskImporter struct {
+	s        TaskService
+	project  string
+	workflow *domain.Workflow
+	members  map[string]*domain.User
+	labels   map[string]*domain.Label
+	names    map[string]bool
+}
+
+func (s TaskService) newTaskImporter(ctx context.Context, projectId string) (*taskImporter, error) {
+	workflow, err := s.GetWorkflow(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	projectMembers, err := s.getProjectMembers(projectId)
+	if err != nil {
+		return nil, fmt.Errorf("can't fetch project members: %w", err)
+	}
+	projectLabels, err := s.tasks.GetLabels(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	importer := &taskImporter{
+		s:        s,
+		project:  projectId,
+		workflow: workflow,
+		members:  map[string]*domain.User{},
+		labels:   map[string]*domain.Label{},
+		names:    map[string]bool{},
+	}
+	for _, member := range projectMembers {
+		importer.members[member.Username] = member
+	}
+	for _, label := range projectLabels {
+		importer.labels[strings.ToLower(label.Name)] = label
+	}
+	return importer, nil
+}
+
+// importRow checks a row and, unless it's a dry run, creates its task. Rows
+// with errors are skipped. Members that aren't on the project and labels
+// that can't be created are left out with a warning.
+//
+// The task is put straight into the state of its row. This is intended: the
+// row records where the task already is in the other tracker, so neither
+// workflow transitions nor blockers are checked, as they are for a task
+// being moved. Members are notified like on any other assignment.
+func (i *taskImporter) importRow(ctx context.Context, row *domain.ImportRow, dryRun bool) *domain.ImportRowResult {
+	result := &domain.ImportRowResult{Row: row.Row, Name: row.Name, Outcome: domain.ROW_FAILED}
+	result.Errors = append(result.Errors, row.Errors...)
+
+	if row.Name == "" {
+		result.Errors = append(result.Errors, "name is missing")
+	} else if i.names[row.Name] {
+		result.Errors = append(result.Errors, "task "+row.Name+" appears earlier in the file")
+	} else if existing, err := i.s.tasks.FindByName(i.project, row.Name); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	} else if existing != nil {
+		result.Errors = append(result.Errors, "task "+row.Name+" already exists")
+	}
+
+	state, err := i.resolveState(row)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	schedule := domain.Schedule{
+		DueDate:       row.DueDate,
+		Priority:      row.Priority,
+		StoryPoints:   row.StoryPoints,
+		EstimateHours: row.EstimateHours,
+	}
+	if err := schedule.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	members := domain.Users{}
+	assigned := map[string]bool{}
+	for _, username := range row.Members {
+		member, ok := i.members[username]
+		if !ok {
+			result.Warnings = append(result.Warnings, username+" is not a project member and won't be assigned")
+			continue
+		}
+		if !assigned[username] {
+			assigned[username] = true
+			members = append(members, member)
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		return result
+	}
+	i.names[row.Name] = true
+
+	labels := i.resolveLabels(ctx, row.Labels, dryRun, result)
+	if dryRun {
+		result.Outcome = domain.ROW_VALID
+		return result
+	}
+
+	created, err := i.s.create(ctx, i.project, "", row.Name, row.Description, schedule)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	result.Outcome = domain.ROW_IMPORTED
+	result.Task = created.Id.Hex()
+	result.Key = created.Key
+
+	if state.Name == created.State && len(members) == 0 && len(labels) == 0 {
+		return result
+	}
+	task := created
+	task.Status = state.CategoryStatus()
+	task.State = state.Name
+	if task.Status == domain.FINISHED {
+		now := time.Now()
+		task.FinishedAt = &now
+	}
+	task.Members = members
+	task.Labels = labels
+	if err := i.s.tasks.SetImported(ctx, task); err != nil {
+		result.Warnings = append(result.Warnings, "state, members and labels couldn't be set: "+err.Error())
+		return result
+	}
+	for _, member := range members {
+		if err := i.s.sendNotification(member.Username, "You are added to task "+task.Name); err != nil {
+			log.Printf("Error sending notification: %v\n", err)
+		}
+	}
+	if task.State != created.State {
+		i.s.recordStatusEvents(ctx, statusEvent(ctx, domain.EVENT_CHANGED, &created, &task))
+	}
+	return result
+}
+
+// resolveState finds the workflow state of a row by name, ignoring case.
+// States of other trackers that aren't in the workflow go to the first state
+// of the status they stand for, as the tracker or their name tell. Rows without a state start in the initial
+// state.
+func (i *taskImporter) resolveState(row *domain.ImportRow) (domain.WorkflowState, error) {
+	if row.State == "" {
+		initial, _ := i.workflow.State(i.workflow.InitialState)
+		return initial, nil
+	}
+	for _, state := range i.workflow.States {
+		if strings.EqualFold(state.Name, row.State) {
+			return state, nil
+		}
+	}
+
+	status := row.Status
+	if status == 0 {
+		status = domain.ImportStatus(row.State)
+	}
+	if status != 0 {
+		if state, ok := i.workflow.StateForStatus(status); ok {
+			return state, nil
+		}
+	}
+	return domain.WorkflowState{}, fmt.Errorf("unknown state %s", row.State)
+}
+
+// resolveLabels returns the ids of the labels of a row, creating the labels
+// the project doesn't have yet.
+func (i *taskImporter) resolveLabels(ctx context.Context, names []string, dryRun bool, result *domain.ImportRowResult) []string {
+	ids := []string{}
+	added := map[string]bool{}
+	for _, name := range names {
+		label, ok := i.labels[strings.ToLower(name)]
+		if !ok && dryRun {
+			result.Warnings = append(result.Warnings, "label "+name+" will be created")
+			continue
+		}
+		if !ok {
+			created, err := i.s.CreateLabel(ctx, i.project, &domain.Label{Name: name, Color: importLabelColor})
+			if err != nil {
+				result.Warnings = append(result.Warnings, "label "+name+" couldn't be created: "+err.Error())
+				continue
+			}
+			result.Warnings = append(result.Warnings, "label "+name+" was created")
+			i.labels[strings.ToLower(name)] = created
+			label = created
+		}
+		if id := label.Id.Hex(); !added[id] {
+			added[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s TaskService) GetLabels(ctx context.Context, projectId string) (domain.Labels, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetLabels")
+	defer span.End()
+
+	return s.tasks.GetLabels(ctx, projectId)
+}
+
+// CreateLabel adds a label to a project. Label names are unique within a
+// project, ignoring case.
+func (s TaskService) CreateLabel(ctx context.Context, projectId string, label *domain.Label) (*domain.Label, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.CreateLabel")
+	defer span.End()
+
+	if err := label.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkLabelName(ctx, projectId, label); err != nil {
+		return nil, err
+	}
+
+	label.Id = primitive.NewObjectID()
+	label.Project = projectId
+	label.CreatedAt = time.Now()
+	if err := s.tasks.InsertLabel(ctx, label); err != nil {
+		return nil, err
+	}
+
+	s.recordTargetActivity(ctx, projectId, domain.ACTIVITY_LABEL_CREATED, "label", label.Id.Hex(), nil, labelActivity(label))
+	return label, nil
+}
+
+// UpdateLabel renames or recolors a label.
+func (s TaskService) UpdateLabel(ctx context.Context, id string, changes *domain.Label) (*domain.Label, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.UpdateLabel")
+	defer span.End()
+
+	label, err := s.findLabel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := changes.Validate(); err != nil {
+		return nil, err
+	}
+	changes.Id = label.Id
+	if err := s.checkLabelName(ctx, label.Project, changes); err != nil {
+		return nil, err
+	}
+
+	previous := *label
+	label.Name = changes.Name
+	label.Color = changes.Color
+	if err := s.tasks.UpdateLabel(ctx, label); err != nil {
+		return nil, err
+	}
+
+	s.recordTargetActivity(ctx, label.Project, domain.ACTIVITY_LABEL_UPDATED, "label", id, labelActivity(&previous), labelActivity(label))
+	return label, nil
+}
+
+// DeleteLabel deletes a label and unassigns it from all tasks.
+func (s TaskService) DeleteLabel(ctx context.Context, id string) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.DeleteLabel")
+	defer span.End()
+
+	label, err := s.findLabel(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.tasks.DeleteLabel(ctx, label); err != nil {
+		return err
+	}
+
+	s.recordTargetActivity(ctx, label.Project, domain.ACTIVITY_LABEL_DELETED, "label", id, labelActivity(label), nil)
+	return nil
+}
+
+// AddTaskLabel assigns a label of the task's project to a task.
+func (s TaskService) AddTaskLabel(ctx context.Context, taskId string, labelId string, username string, role string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.AddTaskLabel")
+	defer span.End()
+
+	task, err := s.findWorkTask(taskId, username, role)
+	if err != nil {
+		return nil, err
+	}
+	label, err := s.findLabel(ctx, labelId)
+	if err != nil {
+		return nil, err
+	}
+	if label.Project != task.Project {
+		return nil, fmt.Errorf("%w: label belongs to another project", domain.ErrInvalidLabel())
+	}
+
+	for _, assigned := range task.Labels {
+		if assigned == labelId {
+			return task, nil
+		}
+	}
+	if err := s.tasks.AddTaskLabel(ctx, task.Id, labelId); err != nil {
+		return nil, err
+	}
+	task.Labels = append(task.Labels, labelId)
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_LABEL_ADDED, taskId, nil, map[string]interface{}{"label": label.Name})
+	return task, nil
+}
+
+// RemoveTaskLabel unassigns a label from a task.
+func (s TaskService) RemoveTaskLabel(ctx context.Context, taskId string, labelId string, username string, role string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.RemoveTaskLabel")
+	defer span.End()
+
+	task, err := s.findWorkTask(taskId, username, role)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := []string{}
+	for _, assigned := range task.Labels {
+		if assigned != labelId {
+			labels = append(labels, assigned)
+		}
+	}
+	if len(labels) == len(task.Labels) {
+		return nil, errors.New("label not found on task")
+	}
+	if err := s.tasks.RemoveTaskLabel(ctx, task.Id, labelId); err != nil {
+		return nil, err
+	}
+	task.Labels = labels
+
+	name := labelId
+	if label, err := s.tasks.GetLabelById(ctx, labelId); err == nil && label != nil {
+		name = label.Name
+	}
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_LABEL_REMOVED, taskId, map[string]interface{}{"label": name}, nil)
+	return task, nil
+}
+
+func (s TaskService) findLabel(ctx context.Context, id string) (*domain.Label, error) {
+	label, err := s.tasks.GetLabelById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if label == nil {
+		return nil, errors.New("label not found")
+	}
+	return label, nil
+}
+
+func (s TaskService) checkLabelName(ctx context.Context, projectId string, label *domain.Label) error {
+	existing, err := s.tasks.GetLabelByName(ctx, projectId, label.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Id != label.Id {
+		return fmt.Errorf("%w: %s", domain.ErrLabelExists(), existing.Name)
+	}
+	return nil
+}
+
+func labelActivity(label *domain.Label) map[string]interface{} {
+	return map[string]interface{}{"name": label.Name, "color": label.Color}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UnassignProjectMembers takes users who were removed from a project off
+// all of its open tasks. Finished tasks keep them, so it stays visible who
+// did the work. Each user gets one notification listing the tasks.
+func (s TaskService) UnassignProjectMembers(ctx context.Context, projectId string, usernames []string) (*domain.Unassignment, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.UnassignProjectMembers")
+	defer span.End()
+
+	s.access.invalidate(projectId)
+
+	unassignment := &domain.Unassignment{Project: projectId, Members: []*domain.MemberUnassignment{}}
+	for _, username := range usernames {
+		tasks, err := s.tasks.QueryTasks(ctx, domain.TaskQuery{
+			Project:  projectId,
+			Assignee: username,
+			Statuses: []domain.Status{domain.PENDING, domain.IN_PROGRESS},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		member := &domain.MemberUnassignment{Username: username, Tasks: []domain.UnassignedTask{}}
+		unassignment.Members = append(unassignment.Members, member)
+		if len(tasks) == 0 {
+			continue
+		}
+
+		ids := make([]primitive.ObjectID, 0, len(tasks))
+		names := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.Id)
+			names = append(names, task.Name)
+			member.Tasks = append(member.Tasks, domain.UnassignedTask{Id: task.Id.Hex(), Name: task.Name})
+		}
+		if err := s.tasks.UnassignMember(ctx, ids, username); err != nil {
+			return nil, err
+		}
+
+		for _, task := range tasks {
+			s.recordActivity(ctx, projectId, domain.ACTIVITY_TASK_MEMBER_REMOVED, task.Id.Hex(), map[string]interface{}{"username": username, "reason": "removed from project"}, nil)
+		}
+
+		message := fmt.Sprintf("You were removed from the project and unassigned from %d open tasks: %s", len(names), strings.Join(names, ", "))
+		if err := s.sendNotification(username, message); err != nil {
+			log.Printf("Error sending notification to %s: %v\n", username, err)
+		} else {
+			member.Notified = true
+		}
+	}
+	return unassignment, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// inbox stands in for notifications-service, it keeps the messages per user
+// and accepts activity posts. When down it answers 503 to everything.
+type inbox struct {
+	mu       sync.Mutex
+	down     bool
+	messages map[string][]string
+	activity int
+}
+
+func (i *inbox) RoundTrip(r *http.Request) (*http.Response, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	status := http.StatusCreated
+	switch {
+	case i.down:
+		status = http.StatusServiceUnavailable
+	case r.URL.Host == "notifications-service:8000":
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			return nil, err
+		}
+		i.messages[payload["user_id"]] = append(i.messages[payload["user_id"]], payload["message"])
+	case strings.HasSuffix(r.URL.Path, "/activity"):
+		i.activity++
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("")), Request: r}, nil
+}
+
+func TestUnassignProjectMembers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	login := &domain.Task{Id: primitive.NewObjectID(), Project: "p1", Name: "Login", Status: domain.PENDING, Members: domain.Users{{Username: "ana"}}}
+	logout := &domain.Task{Id: primitive.NewObjectID(), Project: "p1", Name: "Logout", Status: domain.IN_PROGRESS, Members: domain.Users{{Username: "ana"}, {Username: "ivan"}}}
+
+	mt.Run("takes the members off their open tasks", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		notifications := &inbox{messages: map[string][]string{}}
+		s.client = &http.Client{Transport: notifications}
+		mt.AddMockResponses(cursor(mt, "tasks", login, logout), updated(2), cursor(mt, "tasks"))
+
+		unassignment, err := s.UnassignProjectMembers(context.Background(), "p1", []string{"ana", "zoe"})
+		if err != nil {
+			mt.Fatalf("UnassignProjectMembers: %v", err)
+		}
+
+		find := mt.GetStartedEvent()
+		statuses, _ := find.Command.Lookup("filter", "status", "$in").Array().Values()
+		if len(statuses) != 2 || statuses[0].AsInt64() != int64(domain.PENDING) || statuses[1].AsInt64() != int64(domain.IN_PROGRESS) {
+			mt.Errorf("looked for tasks in %v, finished tasks should keep their members", statuses)
+		}
+		if assignee := find.Command.Lookup("filter", "members.username").StringValue(); assignee != "ana" {
+			mt.Errorf("looked for the tasks of %q", assignee)
+		}
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		if pulled := update.Lookup("u", "$pull", "members", "username").StringValue(); pulled != "ana" {
+			mt.Errorf("pulled %q off the tasks", pulled)
+		}
+		if ids, _ := update.Lookup("q", "_id", "$in").Array().Values(); len(ids) != 2 {
+			mt.Errorf("unassigned %d tasks, want both open ones", len(ids))
+		}
+
+		ana, zoe := unassignment.Members[0], unassignment.Members[1]
+		if len(ana.Tasks) != 2 || ana.Tasks[0].Name != "Login" || ana.Tasks[1].Id != logout.Id.Hex() || !ana.Notified {
+			mt.Errorf("ana was unassigned from %+v, notified %v", ana.Tasks, ana.Notified)
+		}
+		if len(zoe.Tasks) != 0 || zoe.Notified {
+			mt.Errorf("zoe had no tasks but was unassigned from %+v, notified %v", zoe.Tasks, zoe.Notified)
+		}
+
+		if want := "You were removed from the project and unassigned from 2 open tasks: Login, Logout"; len(notifications.messages["ana"]) != 1 || notifications.messages["ana"][0] != want {
+			mt.Errorf("ana was sent %q", notifications.messages["ana"])
+		}
+		if len(notifications.messages["zoe"]) != 0 {
+			mt.Errorf("zoe was sent %q", notifications.messages["zoe"])
+		}
+		if notifications.activity != 2 {
+			mt.Errorf("recorded %d activities, want one per task", notifications.activity)
+		}
+	})
+
+	mt.Run("unassigns when the notification fails", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		s.client = &http.Client{Transport: &inbox{down: true}}
+		mt.AddMockResponses(cursor(mt, "tasks", login), updated(1))
+
+		unassignment, err := s.UnassignProjectMembers(context.Background(), "p1", []string{"ana"})
+		if err != nil {
+			mt.Fatalf("UnassignProjectMembers: %v", err)
+		}
+		if ana := unassignment.Members[0]; len(ana.Tasks) != 1 || ana.Notified {
+			mt.Errorf("ana was unassigned from %+v, notified %v", ana.Tasks, ana.Notified)
+		}
+	})
+
+	mt.Run("stops on a failed update", func(mt *mtest.T) {
+		s, others := newTestService(mt)
+		mt.AddMockResponses(cursor(mt, "tasks", login), bson.D{{Key: "ok", Value: 0}, {Key: "errmsg", Value: "not primary"}})
+
+		if _, err := s.UnassignProjectMembers(context.Background(), "p1", []string{"ana", "zoe"}); err == nil {
+			mt.Fatal("UnassignProjectMembers reported a failed update as done")
+		}
+		if len(others.paths) > 0 {
+			mt.Errorf("told other services about an unassignment that failed: %v", others.paths)
+		}
+	})
+}
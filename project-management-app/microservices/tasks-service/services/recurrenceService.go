@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+)
+
+// recurrenceAttempts is how many times linking a new instance to its series
+// is tried before the instance is left for the next run.
+const recurrenceAttempts = 3
+
+// SetRecurrence makes a task recur by an RRULE-style rule, or changes the
+// rule of a recurring task. Only the latest instance of a series can be
+// changed, older ones have already generated their successor.
+func (s TaskService) SetRecurrence(ctx context.Context, taskId string, rule string, carryAssignees bool) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.SetRecurrence")
+	defer span.End()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+	recurrence, err := domain.ParseRRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	recurrence.CarryAssignees = carryAssignees
+
+	at := occurrenceTime(task)
+	before := map[string]interface{}{}
+	if previous := task.Recurrence; previous != nil {
+		if previous.Spawned {
+			return nil, fmt.Errorf("%w: the next instance was already generated, change the rule on the latest instance", domain.ErrInvalidRecurrence())
+		}
+		recurrence.Series = previous.Series
+		recurrence.SeriesName = previous.SeriesName
+		recurrence.Start = previous.Start
+		recurrence.Occurrence = previous.Occurrence
+		before["rule"] = previous.RRule()
+		before["carry_assignees"] = previous.CarryAssignees
+	} else {
+		recurrence.Series = task.Id.Hex()
+		recurrence.SeriesName = task.Name
+		recurrence.Start = at
+		recurrence.Occurrence = 1
+	}
+	next := recurrence.Next(at)
+	if next.IsZero() {
+		return nil, fmt.Errorf("%w: BYMONTHDAY=%d never falls in the months of the rule", domain.ErrInvalidRecurrence(), recurrence.ByMonthDay)
+	}
+	if !recurrence.Ended(recurrence.Occurrence+1, next) {
+		recurrence.NextAt = &next
+	}
+
+	if err := s.tasks.SetRecurrence(ctx, task.Id, recurrence); err != nil {
+		return nil, err
+	}
+	task.Recurrence = recurrence
+	task.Version++
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_RECURRENCE_UPDATED, taskId, before, map[string]interface{}{
+		"rule":            recurrence.RRule(),
+		"carry_assignees": recurrence.CarryAssignees,
+	})
+	return task, nil
+}
+
+// ClearRecurrence stops a task from recurring. Instances generated so far
+// are kept.
+func (s TaskService) ClearRecurrence(ctx context.Context, taskId string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.ClearRecurrence")
+	defer span.End()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task.Recurrence == nil {
+		return task, nil
+	}
+
+	if err := s.tasks.SetRecurrence(ctx, task.Id, nil); err != nil {
+		return nil, err
+	}
+	before := map[string]interface{}{"rule": task.Recurrence.RRule(), "carry_assignees": task.Recurrence.CarryAssignees}
+	task.Recurrence = nil
+	task.Version++
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_RECURRENCE_UPDATED, taskId, before, nil)
+	return task, nil
+}
+
+// GenerateDueRecurrences generates the next instance of every recurring task
+// whose period has elapsed and returns how many were generated.
+func (s TaskService) GenerateDueRecurrences(ctx context.Context) (int, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GenerateDueRecurrences")
+	defer span.End()
+
+	due, err := s.tasks.GetDueRecurrences(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	var errs []error
+	for _, task := range due {
+		instance, err := s.nextInstance(ctx, *task)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("task %s: %w", task.Id.Hex(), err))
+			continue
+		}
+		if instance != nil {
+			generated++
+		}
+	}
+	return generated, errors.Join(errs...)
+}
+
+// recurOnFinish generates the next instance of a recurring task right away
+// when the task is finished instead of waiting for its period to elapse.
+func (s TaskService) recurOnFinish(ctx context.Context, previous domain.Task, task domain.Task) {
+	if task.Recurrence == nil || task.Status != domain.FINISHED || previous.Status == domain.FINISHED {
+		return
+	}
+	if _, err := s.nextInstance(ctx, task); err != nil {
+		log.Printf("Error generating next instance of task %s: %v\n", task.Id.Hex(), err)
+	}
+}
+
+// nextInstance creates the instance of a series following the given one,
+// due at the next occurrence of the rule. It returns nil when the series has
+// ended or the instance was already generated. When the instance can't be
+// linked to the series the claim is released, and the next attempt links the
+// task that was already created instead of creating another one.
+func (s TaskService) nextInstance(ctx context.Context, task domain.Task) (*domain.Task, error) {
+	current := task.Recurrence
+	if current == nil || current.NextAt == nil {
+		return nil, nil
+	}
+	claimed, err := s.tasks.ClaimRecurrence(ctx, task.Id, true)
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	recurrence := *current
+	recurrence.Occurrence++
+	recurrence.Spawned = false
+	recurrence.NextAt = nil
+	if next := recurrence.Next(*current.NextAt); !recurrence.Ended(recurrence.Occurrence+1, next) {
+		recurrence.NextAt = &next
+	}
+
+	due := *current.NextAt
+	name := fmt.Sprintf("%s #%d", recurrence.SeriesName, recurrence.Occurrence)
+	instance, err := s.unlinkedInstance(task, name)
+	if err == nil && instance == nil {
+		var created domain.Task
+		created, err = s.create(ctx, task.Project, task.Parent, name, task.Description, domain.Schedule{
+			DueDate:       &due,
+			Priority:      task.Priority,
+			StoryPoints:   task.StoryPoints,
+			EstimateHours: task.EstimateHours,
+		})
+		instance = &created
+	}
+	if err != nil {
+		s.releaseRecurrence(ctx, task)
+		return nil, err
+	}
+
+	var members domain.Users
+	if recurrence.CarryAssignees {
+		members = s.carriedMembers(task)
+	}
+	for attempt := 1; ; attempt++ {
+		err = s.tasks.SetInstance(ctx, instance.Id, &recurrence, members, task.Labels)
+		if err == nil || attempt == recurrenceAttempts {
+			break
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+	if err != nil {
+		s.releaseRecurrence(ctx, task)
+		return nil, fmt.Errorf("instance %s was created but not linked to its series: %w", instance.Id.Hex(), err)
+	}
+	instance.Recurrence = &recurrence
+	instance.Members = members
+	instance.Labels = task.Labels
+	instance.Version++
+
+	for _, member := range members {
+		if err := s.sendNotification(member.Username, "You are added to task "+instance.Name); err != nil {
+			log.Printf("Error sending notification: %v\n", err)
+		}
+	}
+	return instance, nil
+}
+
+// unlinkedInstance finds the instance of a series that an earlier attempt
+// created but couldn't link to the series.
+func (s TaskService) unlinkedInstance(task domain.Task, name string) (*domain.Task, error) {
+	existing, err := s.tasks.FindByName(task.Project, name)
+	if err != nil || existing == nil || existing.Recurrence != nil || existing.Parent != task.Parent {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// releaseRecurrence lets the next run generate the instance again.
+func (s TaskService) releaseRecurrence(ctx context.Context, task domain.Task) {
+	if _, err := s.tasks.ClaimRecurrence(ctx, task.Id, false); err != nil {
+		log.Println("Error releasing recurrence:", err)
+	}
+}
+
+// carriedMembers returns the members of a task that are still members of its
+// project. When the project can't be reached all of them are carried over.
+func (s TaskService) carriedMembers(task domain.Task) domain.Users {
+	projectMembers, err := s.getProjectMembers(task.Project)
+	if err != nil {
+		log.Println("Error getting project members:", err)
+		return task.Members
+	}
+
+	inProject := map[string]bool{}
+	for _, member := range projectMembers {
+		inProject[member.Username] = true
+	}
+	members := domain.Users{}
+	for _, member := range task.Members {
+		if inProject[member.Username] {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// occurrenceTime is the time an instance of a recurring task is scheduled
+// for, its due date or otherwise when it was created.
+func occurrenceTime(task *domain.Task) time.Time {
+	if task.DueDate != nil {
+		return *task.DueDate
+	}
+	return task.CreatedAt
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	reportDays     = 30
+	maxReportDays  = 366
+	defaultPeriods = 6
+	maxPeriods     = 52
+)
+
+// GetSnapshots rebuilds the daily counts of a project's tasks from the status
+// log. Without a range the last 30 days are reported.
+func (s TaskService) GetSnapshots(ctx context.Context, projectId string, from *time.Time, to *time.Time) (*domain.Snapshots, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetSnapshots")
+	defer span.End()
+
+	start, end, err := reportRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	timelines, err := s.projectTimelines(ctx, projectId, end)
+	if err != nil {
+		return nil, err
+	}
+	days := domain.BuildSnapshots(timelines, start, end)
+
+	workflow, err := s.GetWorkflow(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Snapshots{Project: projectId, From: start, To: end, States: reportStates(workflow, days), Days: days}, nil
+}
+
+// GetBurndown reports the work left each day of a range, or of a sprint
+// when one is given. A sprint burndown covers the tasks committed to the
+// sprint and those in it now.
+func (s TaskService) GetBurndown(ctx context.Context, projectId string, sprintId string, from *time.Time, to *time.Time) (*domain.Burndown, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetBurndown")
+	defer span.End()
+
+	var sprint *domain.Sprint
+	if sprintId != "" {
+		var err error
+		if sprint, err = s.findSprint(ctx, sprintId); err != nil {
+			return nil, err
+		}
+		if sprint.Project != projectId {
+			return nil, fmt.Errorf("%w: sprint belongs to another project", domain.ErrInvalidReport())
+		}
+		if from == nil {
+			from = &sprint.Start
+		}
+		if to == nil {
+			end := reportDay(sprint.End).AddDate(0, 0, 1)
+			to = &end
+		}
+	}
+
+	start, end, err := reportRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	timelines, err := s.projectTimelines(ctx, projectId, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if sprint != nil {
+		tasks, err := s.tasks.GetSprintTasks(ctx, projectId, sprintId)
+		if err != nil {
+			return nil, err
+		}
+		inSprint := map[string]bool{}
+		for _, id := range sprint.Committed {
+			inSprint[id] = true
+		}
+		for _, task := range tasks {
+			inSprint[task.Id.Hex()] = true
+		}
+		for id := range timelines {
+			if !inSprint[id] {
+				delete(timelines, id)
+			}
+		}
+	}
+
+	burndown := domain.NewBurndown(projectId, start, end, domain.BuildSnapshots(timelines, start, end))
+	burndown.Sprint = sprintId
+	return burndown, nil
+}
+
+// GetVelocity reports the work completed in the last periods, which are
+// closed sprints, weeks or months. Without a choice closed sprints are used
+// when the project has any, weeks otherwise. Weeks and months in progress
+// are not counted.
+func (s TaskService) GetVelocity(ctx context.Context, projectId string, by string, periods int) (*domain.Velocity, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetVelocity")
+	defer span.End()
+
+	if periods <= 0 {
+		periods = defaultPeriods
+	}
+	if periods > maxPeriods {
+		return nil, fmt.Errorf("%w: at most %d periods can be reported", domain.ErrInvalidReport(), maxPeriods)
+	}
+
+	sprints, err := s.tasks.GetSprints(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	closed := domain.Sprints{}
+	for _, sprint := range sprints {
+		if sprint.State == domain.SPRINT_CLOSED && sprint.Report != nil {
+			closed = append(closed, sprint)
+		}
+	}
+	if by == "" {
+		by = domain.PERIOD_WEEK
+		if len(closed) > 0 {
+			by = domain.PERIOD_SPRINT
+		}
+	}
+
+	velocity := &domain.Velocity{Project: projectId, By: by, Periods: []*domain.VelocityPeriod{}}
+	switch by {
+	case domain.PERIOD_SPRINT:
+		sort.Slice(closed, func(i, j int) bool { return closed[i].ClosedAt.Before(*closed[j].ClosedAt) })
+		if len(closed) > periods {
+			closed = closed[len(closed)-periods:]
+		}
+		for _, sprint := range closed {
+			start := sprint.Start
+			if sprint.StartedAt != nil {
+				start = *sprint.StartedAt
+			}
+			velocity.Periods = append(velocity.Periods, &domain.VelocityPeriod{
+				Name:        sprint.Name,
+				Start:       start,
+				End:         *sprint.ClosedAt,
+				Tasks:       sprint.Report.Completed.Tasks,
+				StoryPoints: sprint.Report.Completed.StoryPoints,
+			})
+		}
+	case domain.PERIOD_WEEK, domain.PERIOD_MONTH:
+		velocity.Periods = calendarPeriods(by, periods, time.Now())
+		timelines, err := s.projectTimelines(ctx, projectId, velocity.Periods[len(velocity.Periods)-1].End)
+		if err != nil {
+			return nil, err
+		}
+		for _, period := range velocity.Periods {
+			period.Completed(timelines)
+		}
+	default:
+		return nil, fmt.Errorf("%w: periods are sprint, week or month", domain.ErrInvalidReport())
+	}
+
+	velocity.Average()
+	return velocity, nil
+}
+
+// statusEvent describes the status of a task after a change, previous is
+// nil for new tasks.
+func statusEvent(ctx context.Context, kind string, previous *domain.Task, task *domain.Task) *domain.StatusEvent {
+	event := &domain.StatusEvent{
+		Id:          primitive.NewObjectID(),
+		Task:        task.Id.Hex(),
+		Project:     task.Project,
+		Kind:        kind,
+		Status:      task.Status,
+		State:       task.CurrentState(),
+		StoryPoints: task.StoryPoints,
+		Actor:       actorFromContext(ctx),
+		At:          time.Now(),
+	}
+	if previous != nil {
+		event.FromStatus = previous.Status
+		event.FromState = previous.CurrentState()
+	}
+	return event
+}
+
+// recordStatusEvents appends to the status log. Failing to record never
+// fails the change itself.
+func (s TaskService) recordStatusEvents(ctx context.Context, events ...*domain.StatusEvent) {
+	if len(events) == 0 {
+		return
+	}
+	if err := s.tasks.InsertStatusEvents(ctx, events); err != nil {
+		log.Printf("Error recording status events of project %s: %v\n", events[0].Project, err)
+	}
+}
+
+// projectTimelines returns the status log of a project's tasks up to a
+// moment, grouped by task.
+func (s TaskService) projectTimelines(ctx context.Context, projectId string, before time.Time) (map[string]domain.TaskTimeline, error) {
+	tasks, err := s.tasks.GetByProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.tasks.GetStatusEvents(ctx, projectId, before)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Timelines(tasks, events), nil
+}
+
+// reportRange turns an optional range into whole UTC days, the end
+// exclusive.
+func reportRange(from *time.Time, to *time.Time) (time.Time, time.Time, error) {
+	end := reportDay(time.Now()).AddDate(0, 0, 1)
+	if to != nil {
+		end = reportDay(to.Add(-time.Nanosecond)).AddDate(0, 0, 1)
+	}
+	start := end.AddDate(0, 0, -reportDays)
+	if from != nil {
+		start = reportDay(*from)
+	}
+
+	if !start.Before(end) {
+		return start, end, fmt.Errorf("%w: from must be before to", domain.ErrInvalidReport())
+	}
+	if end.Sub(start) > maxReportDays*24*time.Hour {
+		return start, end, fmt.Errorf("%w: at most %d days can be reported", domain.ErrInvalidReport(), maxReportDays)
+	}
+	return start, end, nil
+}
+
+func reportDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// calendarPeriods returns the last complete weeks, starting on Monday, or
+// months before now, oldest first.
+func calendarPeriods(by string, count int, now time.Time) []*domain.VelocityPeriod {
+	day := reportDay(now)
+	end := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	if by == domain.PERIOD_MONTH {
+		end = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	periods := make([]*domain.VelocityPeriod, count)
+	for i := count - 1; i >= 0; i-- {
+		start := end.AddDate(0, 0, -7)
+		year, week := start.ISOWeek()
+		name := fmt.Sprintf("%d-W%02d", year, week)
+		if by == domain.PERIOD_MONTH {
+			start = end.AddDate(0, -1, 0)
+			name = start.Format("2006-01")
+		}
+		periods[i] = &domain.VelocityPeriod{Name: name, Start: start, End: end}
+		end = start
+	}
+	return periods
+}
+
+// reportStates lists the states of a workflow in order, followed by states
+// that were used in the past and no longer exist.
+func reportStates(workflow *domain.Workflow, days []*domain.Snapshot) []string {
+	states := []string{}
+	known := map[string]bool{}
+	for _, state := range workflow.States {
+		states = append(states, state.Name)
+		known[state.Name] = true
+	}
+
+	removed := []string{}
+	for _, day := range days {
+		for state := range day.States {
+			if !known[state] {
+				known[state] = true
+				removed = append(removed, state)
+			}
+		}
+	}
+	sort.Strings(removed)
+	return append(states, removed...)
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s TaskService) GetSprints(ctx context.Context, projectId string) (domain.Sprints, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetSprints")
+	defer span.End()
+
+	return s.tasks.GetSprints(ctx, projectId)
+}
+
+func (s TaskService) GetSprint(ctx context.Context, id string) (*domain.Sprint, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetSprint")
+	defer span.End()
+
+	return s.findSprint(ctx, id)
+}
+
+// CreateSprint plans a new sprint of a project.
+func (s TaskService) CreateSprint(ctx context.Context, projectId string, sprint *domain.Sprint) (*domain.Sprint, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.CreateSprint")
+	defer span.End()
+
+	if err := sprint.Validate(); err != nil {
+		return nil, err
+	}
+
+	sprint.Id = primitive.NewObjectID()
+	sprint.Project = projectId
+	sprint.State = domain.SPRINT_PLANNED
+	sprint.Committed = nil
+	sprint.Planned = domain.SprintWork{}
+	sprint.Report = nil
+	sprint.StartedAt = nil
+	sprint.ClosedAt = nil
+	sprint.CreatedAt = time.Now()
+	if err := s.tasks.InsertSprint(ctx, sprint); err != nil {
+		return nil, err
+	}
+
+	s.recordTargetActivity(ctx, projectId, domain.ACTIVITY_SPRINT_CREATED, "sprint", sprint.Id.Hex(), nil, sprintActivity(sprint))
+	return sprint, nil
+}
+
+// DeleteSprint deletes a sprint that hasn't started, its tasks go back to
+// the backlog.
+func (s TaskService) DeleteSprint(ctx context.Context, id string) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.DeleteSprint")
+	defer span.End()
+
+	sprint, err := s.findSprint(ctx, id)
+	if err != nil {
+		return err
+	}
+	if sprint.State != domain.SPRINT_PLANNED {
+		return fmt.Errorf("%w: only planned sprints can be deleted", domain.ErrSprintState())
+	}
+
+	if err := s.tasks.DeleteSprint(ctx, sprint); err != nil {
+		return err
+	}
+
+	s.recordTargetActivity(ctx, sprint.Project, domain.ACTIVITY_SPRINT_DELETED, "sprint", id, sprintActivity(sprint), nil)
+	return nil
+}
+
+// StartSprint starts a planned sprint. The tasks in the sprint at that moment
+// are the work the team commits to. A project has one sprint in progress at
+// a time.
+func (s TaskService) StartSprint(ctx context.Context, id string) (*domain.Sprint, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.StartSprint")
+	defer span.End()
+
+	sprint, err := s.findSprint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.State != domain.SPRINT_PLANNED {
+		return nil, fmt.Errorf("%w: sprint is %s", domain.ErrSprintState(), sprint.State)
+	}
+	active, err := s.tasks.GetActiveSprint(ctx, sprint.Project)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, fmt.Errorf("%w: sprint %s is already in progress", domain.ErrSprintState(), active.Name)
+	}
+
+	tasks, err := s.tasks.GetSprintTasks(ctx, sprint.Project, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sprint.State = domain.SPRINT_ACTIVE
+	sprint.StartedAt = &now
+	sprint.Committed = []string{}
+	for _, task := range tasks {
+		sprint.Committed = append(sprint.Committed, task.Id.Hex())
+	}
+	sprint.Planned = domain.SprintWorkOf(tasks)
+
+	updated, err := s.tasks.UpdateSprintState(ctx, sprint, domain.SPRINT_PLANNED)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: sprint was changed by someone else", domain.ErrSprintState())
+	}
+
+	s.recordTargetActivity(ctx, sprint.Project, domain.ACTIVITY_SPRINT_STARTED, "sprint", id, nil, map[string]interface{}{
+		"tasks":        sprint.Planned.Tasks,
+		"story_points": sprint.Planned.StoryPoints,
+	})
+	return sprint, nil
+}
+
+// CloseSprint closes the sprint in progress. Its unfinished tasks move to
+// moveTo, which is "backlog" (the default), "next" for the planned sprint
+// that starts first, or the id of a planned sprint. The report of the sprint
+// is kept with it.
+func (s TaskService) CloseSprint(ctx context.Context, id string, moveTo string) (*domain.Sprint, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.CloseSprint")
+	defer span.End()
+
+	sprint, err := s.findSprint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.State != domain.SPRINT_ACTIVE {
+		return nil, fmt.Errorf("%w: sprint is %s", domain.ErrSprintState(), sprint.State)
+	}
+	target, err := s.closeTarget(ctx, sprint, moveTo)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.tasks.GetSprintTasks(ctx, sprint.Project, id)
+	if err != nil {
+		return nil, err
+	}
+	unfinished := []primitive.ObjectID{}
+	for _, task := range tasks {
+		if task.Status != domain.FINISHED {
+			unfinished = append(unfinished, task.Id)
+		}
+	}
+
+	now := time.Now()
+	sprint.Report = domain.NewSprintReport(sprint, tasks)
+	sprint.Report.State = domain.SPRINT_CLOSED
+	sprint.Report.CarriedTo = domain.SPRINT_BACKLOG
+	if target != nil {
+		sprint.Report.CarriedTo = target.Id.Hex()
+	}
+	sprint.State = domain.SPRINT_CLOSED
+	sprint.ClosedAt = &now
+
+	updated, err := s.tasks.UpdateSprintState(ctx, sprint, domain.SPRINT_ACTIVE)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: sprint was changed by someone else", domain.ErrSprintState())
+	}
+
+	if len(unfinished) > 0 {
+		targetId := ""
+		if target != nil {
+			targetId = target.Id.Hex()
+		}
+		if err := s.tasks.SetTasksSprint(ctx, unfinished, id, targetId); err != nil {
+			return nil, err
+		}
+	}
+
+	s.recordTargetActivity(ctx, sprint.Project, domain.ACTIVITY_SPRINT_CLOSED, "sprint", id, nil, map[string]interface{}{
+		"completed":  sprint.Report.Completed.Tasks,
+		"remaining":  sprint.Report.Remaining.Tasks,
+		"carried_to": sprint.Report.CarriedTo,
+	})
+	return sprint, nil
+}
+
+// GetSprintReport reports planned against completed work of a sprint. Open
+// sprints are reported on their current tasks.
+func (s TaskService) GetSprintReport(ctx context.Context, id string) (*domain.SprintReport, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetSprintReport")
+	defer span.End()
+
+	sprint, err := s.findSprint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Report != nil {
+		return sprint.Report, nil
+	}
+
+	tasks, err := s.tasks.GetSprintTasks(ctx, sprint.Project, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewSprintReport(sprint, tasks), nil
+}
+
+// SetTaskSprint puts a task into a sprint of its project, or back into the
+// backlog when sprintId is empty. With a version the task must still be at
+// it, like in Update.
+func (s TaskService) SetTaskSprint(ctx context.Context, taskId string, sprintId string, version *int64) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.SetTaskSprint")
+	defer span.End()
+
+	for attempt := 1; ; attempt++ {
+		task, err := s.setTaskSprint(ctx, taskId, sprintId, version)
+		if errors.Is(err, domain.ErrVersionConflict()) && version == nil && attempt < 3 {
+			continue
+		}
+		return task, err
+	}
+}
+
+func (s TaskService) setTaskSprint(ctx context.Context, taskId string, sprintId string, version *int64) (*domain.Task, error) {
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if version != nil && task.Version != *version {
+		return nil, fmt.Errorf("%w: current version is %d", domain.ErrVersionConflict(), task.Version)
+	}
+	if sprintId != "" {
+		sprint, err := s.findSprint(ctx, sprintId)
+		if err != nil {
+			return nil, err
+		}
+		if sprint.Project != task.Project {
+			return nil, fmt.Errorf("%w: sprint belongs to another project", domain.ErrInvalidSprint())
+		}
+		if sprint.State == domain.SPRINT_CLOSED {
+			return nil, fmt.Errorf("%w: sprint is closed", domain.ErrSprintState())
+		}
+	}
+	if task.Sprint == sprintId {
+		return task, nil
+	}
+
+	if err := s.tasks.SetTaskSprint(ctx, task, sprintId); err != nil {
+		return nil, err
+	}
+	previous := task.Sprint
+	task.Sprint = sprintId
+	task.Version++
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_TASK_SPRINT_CHANGED, taskId, map[string]interface{}{"sprint": previous}, map[string]interface{}{"sprint": sprintId})
+	return task, nil
+}
+
+// closeTarget resolves where the unfinished tasks of a closing sprint go,
+// nil meaning the backlog.
+func (s TaskService) closeTarget(ctx context.Context, sprint *domain.Sprint, moveTo string) (*domain.Sprint, error) {
+	switch moveTo {
+	case "", domain.SPRINT_BACKLOG:
+		return nil, nil
+	case domain.SPRINT_NEXT:
+		next, err := s.tasks.GetNextSprint(ctx, sprint.Project)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			return nil, fmt.Errorf("%w: there is no planned sprint to move unfinished tasks to", domain.ErrInvalidSprint())
+		}
+		return next, nil
+	}
+
+	target, err := s.findSprint(ctx, moveTo)
+	if err != nil {
+		return nil, err
+	}
+	if target.Project != sprint.Project || target.State != domain.SPRINT_PLANNED {
+		return nil, fmt.Errorf("%w: unfinished tasks can only move to a planned sprint of the same project", domain.ErrInvalidSprint())
+	}
+	return target, nil
+}
+
+func (s TaskService) findSprint(ctx context.Context, id string) (*domain.Sprint, error) {
+	sprint, err := s.tasks.GetSprintById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sprint == nil {
+		return nil, errors.New("sprint not found")
+	}
+	return sprint, nil
+}
+
+func sprintActivity(sprint *domain.Sprint) map[string]interface{} {
+	return map[string]interface{}{
+		"name":  sprint.Name,
+		"start": sprint.Start,
+		"end":   sprint.End,
+	}
+}
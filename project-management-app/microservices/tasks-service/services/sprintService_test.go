@@ -0,0 +1,245 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"project-management-app/microservices/projects-service/domain"
+	"project-management-app/microservices/projects-service/repositories"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// otherServices stands in for projects-service and notifications-service and
+// remembers what was sent to them.
+type otherServices struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (o *otherServices) RoundTrip(r *http.Request) (*http.Response, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.paths = append(o.paths, r.Method+" "+r.URL.Host+r.URL.Path)
+	return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(&bytes.Buffer{}), Request: r}, nil
+}
+
+func (o *otherServices) sent(path string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, sent := range o.paths {
+		if sent == path {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestService(mt *mtest.T) (*TaskService, *otherServices) {
+	tracer := noop.NewTracerProvider().Tracer("")
+	s := NewTaskService(repositories.NewTaskRepoWithClient(mt.Client, log.New(io.Discard, "", 0), tracer), tracer, nil, AttachmentLimits{}, 0)
+	others := &otherServices{}
+	s.client = &http.Client{Transport: others}
+	return s, others
+}
+
+func cursor(t testing.TB, collection string, values ...interface{}) bson.D {
+	t.Helper()
+	docs := make([]bson.D, 0, len(values))
+	for _, v := range values {
+		data, err := bson.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc bson.D
+		if err := bson.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, doc)
+	}
+	return mtest.CreateCursorResponse(0, "tasks."+collection, mtest.FirstBatch, docs...)
+}
+
+func updated(n int) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+}
+
+// lastUpdate returns the last update command sent for a collection, nil if
+// there was none.
+func lastUpdate(mt *mtest.T, collection string) bson.Raw {
+	var command bson.Raw
+	for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+		if event.CommandName == "update" && event.Command.Lookup("update").StringValue() == collection {
+			command = event.Command
+		}
+	}
+	return command
+}
+
+func plannedSprint() *domain.Sprint {
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	return &domain.Sprint{
+		Id: primitive.NewObjectID(), Project: "p1", Name: "Sprint 7",
+		Start: start, End: start.AddDate(0, 0, 14), State: domain.SPRINT_PLANNED,
+	}
+}
+
+func TestStartSprint(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("commits the tasks in the sprint", func(mt *mtest.T) {
+		s, others := newTestService(mt)
+		sprint := plannedSprint()
+		login := &domain.Task{Id: primitive.NewObjectID(), Project: "p1", Name: "Login", StoryPoints: 3, EstimateHours: 4}
+		logout := &domain.Task{Id: primitive.NewObjectID(), Project: "p1", Name: "Logout", StoryPoints: 2}
+		mt.AddMockResponses(
+			cursor(mt, "sprints", sprint),
+			cursor(mt, "sprints"),
+			cursor(mt, "tasks", login, logout),
+			updated(1),
+		)
+
+		started, err := s.StartSprint(context.Background(), sprint.Id.Hex())
+		if err != nil {
+			mt.Fatalf("StartSprint: %v", err)
+		}
+		if started.State != domain.SPRINT_ACTIVE || started.StartedAt == nil {
+			mt.Errorf("sprint is %s, started at %v", started.State, started.StartedAt)
+		}
+		if len(started.Committed) != 2 || started.Committed[0] != login.Id.Hex() || started.Committed[1] != logout.Id.Hex() {
+			mt.Errorf("committed %v, want both tasks", started.Committed)
+		}
+		if want := (domain.SprintWork{Tasks: 2, StoryPoints: 5, EstimateHours: 4}); started.Planned != want {
+			mt.Errorf("planned %+v, want %+v", started.Planned, want)
+		}
+		if !others.sent("POST projects-service:8000/projects/p1/activity") {
+			mt.Errorf("no activity recorded, sent %v", others.paths)
+		}
+	})
+
+	mt.Run("only starts planned sprints", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		sprint := plannedSprint()
+		sprint.State = domain.SPRINT_CLOSED
+		mt.AddMockResponses(cursor(mt, "sprints", sprint))
+
+		if _, err := s.StartSprint(context.Background(), sprint.Id.Hex()); !errors.Is(err, domain.ErrSprintState()) {
+			mt.Fatalf("StartSprint of a closed sprint = %v, want ErrSprintState", err)
+		}
+	})
+
+	mt.Run("waits for the sprint in progress", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		sprint := plannedSprint()
+		active := plannedSprint()
+		active.State = domain.SPRINT_ACTIVE
+		mt.AddMockResponses(cursor(mt, "sprints", sprint), cursor(mt, "sprints", active))
+
+		if _, err := s.StartSprint(context.Background(), sprint.Id.Hex()); !errors.Is(err, domain.ErrSprintState()) {
+			mt.Fatalf("StartSprint next to an active sprint = %v, want ErrSprintState", err)
+		}
+	})
+
+	mt.Run("loses to a sprint started at the same time", func(mt *mtest.T) {
+		s, others := newTestService(mt)
+		sprint := plannedSprint()
+		mt.AddMockResponses(
+			cursor(mt, "sprints", sprint),
+			cursor(mt, "sprints"),
+			cursor(mt, "tasks"),
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{
+				Code:    11000,
+				Message: "E11000 duplicate key error collection: tasks.sprints index: project_active dup key: { project: \"p1\" }",
+			}),
+		)
+
+		if _, err := s.StartSprint(context.Background(), sprint.Id.Hex()); !errors.Is(err, domain.ErrSprintState()) {
+			mt.Fatalf("StartSprint = %v, want ErrSprintState", err)
+		}
+		if len(others.paths) > 0 {
+			mt.Errorf("a sprint that didn't start was recorded: %v", others.paths)
+		}
+	})
+
+	mt.Run("loses to a change of the sprint", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		sprint := plannedSprint()
+		mt.AddMockResponses(cursor(mt, "sprints", sprint), cursor(mt, "sprints"), cursor(mt, "tasks"), updated(0))
+
+		if _, err := s.StartSprint(context.Background(), sprint.Id.Hex()); !errors.Is(err, domain.ErrSprintState()) {
+			mt.Fatalf("StartSprint = %v, want ErrSprintState", err)
+		}
+	})
+}
+
+func TestCloseSprint(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("carries unfinished tasks to the backlog", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		sprint := plannedSprint()
+		sprint.State = domain.SPRINT_ACTIVE
+		done := &domain.Task{Id: primitive.NewObjectID(), Project: "p1", Name: "Done", Status: domain.FINISHED, StoryPoints: 3}
+		open := &domain.Task{Id: primitive.NewObjectID(), Project: "p1", Name: "Open", Status: domain.IN_PROGRESS, StoryPoints: 1}
+		sprint.Committed = []string{done.Id.Hex(), open.Id.Hex()}
+		mt.AddMockResponses(cursor(mt, "sprints", sprint), cursor(mt, "tasks", done, open), updated(1), updated(1))
+
+		closed, err := s.CloseSprint(context.Background(), sprint.Id.Hex(), "")
+		if err != nil {
+			mt.Fatalf("CloseSprint: %v", err)
+		}
+		if closed.State != domain.SPRINT_CLOSED || closed.ClosedAt == nil {
+			mt.Errorf("sprint is %s, closed at %v", closed.State, closed.ClosedAt)
+		}
+		report := closed.Report
+		if report.State != domain.SPRINT_CLOSED || report.CarriedTo != domain.SPRINT_BACKLOG {
+			mt.Errorf("report is %s, carried to %s", report.State, report.CarriedTo)
+		}
+		if report.Completed.Tasks != 1 || report.Remaining.Tasks != 1 || report.Completion != 75 {
+			mt.Errorf("report completed %+v, remaining %+v, %.2f%% done", report.Completed, report.Remaining, report.Completion)
+		}
+
+		carry := lastUpdate(mt, "tasks")
+		if carry == nil {
+			mt.Fatal("unfinished tasks weren't moved")
+		}
+		filter := carry.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+		if filter.Lookup("sprint").StringValue() != sprint.Id.Hex() {
+			mt.Errorf("moved tasks regardless of their sprint: %s", filter)
+		}
+		ids, _ := filter.Lookup("_id", "$in").Array().Values()
+		if len(ids) != 1 || ids[0].ObjectID() != open.Id {
+			mt.Errorf("moved %v, want only the unfinished task", ids)
+		}
+	})
+
+	mt.Run("only closes the sprint in progress", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		sprint := plannedSprint()
+		mt.AddMockResponses(cursor(mt, "sprints", sprint))
+
+		if _, err := s.CloseSprint(context.Background(), sprint.Id.Hex(), ""); !errors.Is(err, domain.ErrSprintState()) {
+			mt.Fatalf("CloseSprint of a planned sprint = %v, want ErrSprintState", err)
+		}
+	})
+
+	mt.Run("needs a sprint to carry the tasks to", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		sprint := plannedSprint()
+		sprint.State = domain.SPRINT_ACTIVE
+		mt.AddMockResponses(cursor(mt, "sprints", sprint), cursor(mt, "sprints"))
+
+		if _, err := s.CloseSprint(context.Background(), sprint.Id.Hex(), domain.SPRINT_NEXT); !errors.Is(err, domain.ErrInvalidSprint()) {
+			mt.Fatalf("CloseSprint to the next sprint without a planned one = %v, want ErrInvalidSprint", err)
+		}
+	})
+}
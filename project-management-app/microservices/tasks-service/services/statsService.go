@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+)
+
+const (
+	defaultTrendDays = 14
+	maxTrendDays     = 90
+)
+
+// GetProjectStats returns the task statistics of a project with a daily
+// created/finished trend covering the last days days.
+func (s TaskService) GetProjectStats(ctx context.Context, projectId string, days int) (*domain.TaskStats, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetProjectStats")
+	defer span.End()
+
+	if days < 1 {
+		days = defaultTrendDays
+	} else if days > maxTrendDays {
+		days = maxTrendDays
+	}
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+
+	return s.tasks.GetProjectStats(ctx, projectId, since)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateSubtask creates a task as a child of another task, in the same
+// project.
+func (s TaskService) CreateSubtask(ctx context.Context, parentId string, name string, description string, schedule domain.Schedule) (domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.CreateSubtask")
+	defer span.End()
+
+	parent, err := s.tasks.FindById(parentId)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if parent == nil {
+		return domain.Task{}, errors.New("parent task not found")
+	}
+
+	return s.create(ctx, parent.Project, parentId, name, description, schedule)
+}
+
+// GetTaskTree returns a task with all of its subtasks and the completion
+// rolled up from them.
+func (s TaskService) GetTaskTree(ctx context.Context, taskId string) (*domain.TaskTree, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetTaskTree")
+	defer span.End()
+
+	task, err := s.tasks.FindById(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, errors.New("task not found")
+	}
+
+	tasks, err := s.tasks.GetByProject(ctx, task.Project)
+	if err != nil {
+		return nil, err
+	}
+	children := map[string]domain.Tasks{}
+	for _, t := range tasks {
+		if t.Parent != "" {
+			children[t.Parent] = append(children[t.Parent], t)
+		}
+	}
+
+	return buildTaskTree(task, children, map[string]bool{}), nil
+}
+
+// buildTaskTree builds the tree below a task. A task counts as complete when
+// it is finished, otherwise each subtask and checklist item counts as one
+// unit of work.
+func buildTaskTree(task *domain.Task, children map[string]domain.Tasks, visited map[string]bool) *domain.TaskTree {
+	visited[task.Id.Hex()] = true
+	tree := &domain.TaskTree{Task: task, Subtasks: []*domain.TaskTree{}}
+
+	done := 0.0
+	for _, child := range children[task.Id.Hex()] {
+		if visited[child.Id.Hex()] {
+			continue
+		}
+		subtree := buildTaskTree(child, children, visited)
+		tree.Subtasks = append(tree.Subtasks, subtree)
+
+		tree.Progress.Subtasks++
+		if child.Status == domain.FINISHED {
+			tree.Progress.SubtasksFinished++
+		}
+		done += subtree.Progress.Completion / 100
+	}
+	for _, item := range task.Checklist {
+		tree.Progress.ChecklistItems++
+		if item.Done {
+			tree.Progress.ChecklistDone++
+			done++
+		}
+	}
+
+	units := tree.Progress.Subtasks + tree.Progress.ChecklistItems
+	switch {
+	case task.Status == domain.FINISHED:
+		tree.Progress.Completion = 100
+	case units > 0:
+		tree.Progress.Completion = math.Round(done/float64(units)*10000) / 100
+	}
+	return tree
+}
+
+// AddChecklistItem appends an item to the checklist of a task.
+func (s TaskService) AddChecklistItem(ctx context.Context, taskId string, text string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.AddChecklistItem")
+	defer span.End()
+
+	if text == "" {
+		return nil, fmt.Errorf("%w: checklist item text is required", domain.ErrInvalidInput())
+	}
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	item := domain.ChecklistItem{Id: primitive.NewObjectID(), Text: text, CreatedAt: time.Now()}
+	if err := s.tasks.AddChecklistItem(ctx, task.Id, item); err != nil {
+		return nil, err
+	}
+	task.Checklist = append(task.Checklist, item)
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_CHECKLIST_UPDATED, taskId, nil, map[string]interface{}{"item": item.Text, "added": true})
+	return task, nil
+}
+
+// ToggleChecklistItem flips the done flag of a checklist item.
+func (s TaskService) ToggleChecklistItem(ctx context.Context, taskId string, itemId string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.ToggleChecklistItem")
+	defer span.End()
+
+	task, index, err := s.findChecklistItem(taskId, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &task.Checklist[index]
+	if err := s.tasks.SetChecklistItemDone(ctx, task.Id, item.Id, !item.Done); err != nil {
+		return nil, err
+	}
+	item.Done = !item.Done
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_CHECKLIST_UPDATED, taskId, map[string]interface{}{"item": item.Text, "done": !item.Done}, map[string]interface{}{"item": item.Text, "done": item.Done})
+	return task, nil
+}
+
+// RemoveChecklistItem deletes an item from the checklist of a task.
+func (s TaskService) RemoveChecklistItem(ctx context.Context, taskId string, itemId string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.RemoveChecklistItem")
+	defer span.End()
+
+	task, index, err := s.findChecklistItem(taskId, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	item := task.Checklist[index]
+	if err := s.tasks.RemoveChecklistItem(ctx, task.Id, item.Id); err != nil {
+		return nil, err
+	}
+	task.Checklist = append(task.Checklist[:index], task.Checklist[index+1:]...)
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_CHECKLIST_UPDATED, taskId, map[string]interface{}{"item": item.Text}, nil)
+	return task, nil
+}
+
+// ReorderChecklist puts the checklist items in the given order. The ids must
+// list every item of the checklist exactly once.
+func (s TaskService) ReorderChecklist(ctx context.Context, taskId string, ids []string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.ReorderChecklist")
+	defer span.End()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) != len(task.Checklist) {
+		return nil, fmt.Errorf("%w: expected %d checklist items, got %d", domain.ErrInvalidInput(), len(task.Checklist), len(ids))
+	}
+	if len(ids) == 0 {
+		return task, nil
+	}
+
+	byId := map[string]domain.ChecklistItem{}
+	for _, item := range task.Checklist {
+		byId[item.Id.Hex()] = item
+	}
+	items := []domain.ChecklistItem{}
+	for _, id := range ids {
+		item, ok := byId[id]
+		if !ok {
+			return nil, fmt.Errorf("checklist item %s not found", id)
+		}
+		delete(byId, id)
+		items = append(items, item)
+	}
+
+	if err := s.tasks.SetChecklist(ctx, task.Id, items); err != nil {
+		return nil, err
+	}
+	task.Checklist = items
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_CHECKLIST_UPDATED, taskId, nil, map[string]interface{}{"reordered": true})
+	return task, nil
+}
+
+// ConvertChecklistItem turns a checklist item into a subtask of its task and
+// removes it from the checklist.
+func (s TaskService) ConvertChecklistItem(ctx context.Context, taskId string, itemId string) (domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.ConvertChecklistItem")
+	defer span.End()
+
+	task, index, err := s.findChecklistItem(taskId, itemId)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	item := task.Checklist[index]
+
+	subtask, err := s.create(ctx, task.Project, taskId, item.Text, "", domain.Schedule{})
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if err := s.tasks.RemoveChecklistItem(ctx, task.Id, item.Id); err != nil {
+		return domain.Task{}, err
+	}
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_CHECKLIST_UPDATED, taskId, map[string]interface{}{"item": item.Text}, map[string]interface{}{"subtask": subtask.Id.Hex()})
+	return subtask, nil
+}
+
+func (s TaskService) findTask(taskId string) (*domain.Task, error) {
+	task, err := s.tasks.FindById(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, errors.New("task not found")
+	}
+	return task, nil
+}
+
+func (s TaskService) findChecklistItem(taskId string, itemId string) (*domain.Task, int, error) {
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, item := range task.Checklist {
+		if item.Id.Hex() == itemId {
+			return task, i, nil
+		}
+	}
+	return nil, 0, errors.New("checklist item not found")
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+)
+
+// GetTaskByKey finds a task of a project by its key, like PRJ-42. Only keys
+// with the current prefix of the project are found.
+func (s TaskService) GetTaskByKey(ctx context.Context, projectId string, key string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetTaskByKey")
+	defer span.End()
+
+	prefix, number, err := domain.ParseTaskKey(key)
+	if err != nil {
+		return nil, err
+	}
+	sequence, err := s.GetKeySequence(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != sequence.Prefix {
+		return nil, fmt.Errorf("task not found, keys of the project start with %s", sequence.Prefix)
+	}
+	task, err := s.tasks.FindByNumber(ctx, projectId, number)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, errors.New("task not found")
+	}
+	return task, nil
+}
+
+// GetKeySequence returns the key prefix of a project and the number of the
+// last task created in it.
+func (s TaskService) GetKeySequence(ctx context.Context, projectId string) (*domain.KeySequence, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetKeySequence")
+	defer span.End()
+
+	sequence, err := s.tasks.GetKeySequence(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	if sequence == nil {
+		sequence = &domain.KeySequence{Project: projectId, Prefix: domain.DEFAULT_KEY_PREFIX}
+	}
+	return sequence, nil
+}
+
+// SetKeyPrefix changes the prefix of the task keys of a project. Existing
+// tasks keep their numbers and get the new prefix. Tasks created at the same
+// time that were numbered under the old prefix are settled by settleKey.
+func (s TaskService) SetKeyPrefix(ctx context.Context, projectId string, prefix string) (*domain.KeySequence, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.SetKeyPrefix")
+	defer span.End()
+
+	prefix, err := domain.NormalizeKeyPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return s.tasks.SetKeyPrefix(ctx, projectId, prefix)
+}
+
+// assignKey gives a new task the next number of its project. A number
+// reserved for a task that then fails to be stored is not reused.
+func (s TaskService) assignKey(ctx context.Context, task *domain.Task) error {
+	sequence, err := s.tasks.NextTaskNumbers(ctx, task.Project, 1)
+	if err != nil {
+		return err
+	}
+	task.Number = sequence.Last
+	task.Key = domain.TaskKey(sequence.Prefix, sequence.Last)
+	return nil
+}
+
+// settleKey gives a stored task the current prefix of its project in case
+// the prefix was changed between numbering the task and storing it, after
+// SetKeyPrefix had already rewritten the keys.
+func (s TaskService) settleKey(ctx context.Context, task *domain.Task) {
+	sequence, err := s.GetKeySequence(ctx, task.Project)
+	if err != nil {
+		log.Println("Error checking the key prefix:", err)
+		return
+	}
+	key := domain.TaskKey(sequence.Prefix, task.Number)
+	if key == task.Key {
+		return
+	}
+	if err := s.tasks.ReplaceTaskKey(ctx, task.Id, task.Key, key); err != nil {
+		log.Println("Error settling the task key:", err)
+		return
+	}
+	task.Key = key
+}
+
+// PrepareTaskKeys numbers the tasks created before task keys existed, in the
+// order they were created, and then creates the unique indexes on task names
+// and numbers.
+func (s TaskService) PrepareTaskKeys(ctx context.Context) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.PrepareTaskKeys")
+	defer span.End()
+
+	tasks, err := s.tasks.GetTasksWithoutKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	byProject := map[string]domain.Tasks{}
+	projects := []string{}
+	for _, task := range tasks {
+		if _, ok := byProject[task.Project]; !ok {
+			projects = append(projects, task.Project)
+		}
+		byProject[task.Project] = append(byProject[task.Project], task)
+	}
+
+	for _, projectId := range projects {
+		projectTasks := byProject[projectId]
+		sequence, err := s.tasks.NextTaskNumbers(ctx, projectId, int64(len(projectTasks)))
+		if err != nil {
+			return err
+		}
+		number := sequence.Last - int64(len(projectTasks))
+		for _, task := range projectTasks {
+			number++
+			if err := s.tasks.SetTaskKey(ctx, task.Id, number, domain.TaskKey(sequence.Prefix, number)); err != nil {
+				return err
+			}
+		}
+		log.Printf("Assigned keys to %d tasks of project %s\n", len(projectTasks), projectId)
+	}
+
+	return s.tasks.CreateTaskIndexes(ctx)
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"project-management-app/microservices/projects-service/domain"
 	"project-management-app/microservices/projects-service/repositories"
+	"project-management-app/microservices/projects-service/storage"
 	"time"
 
 	"github.com/eapache/go-resiliency/retrier"
@@ -25,9 +26,13 @@ type TaskService struct {
 	
 	client *http.Client
 	tracer trace.Tracer
+	blobs  storage.BlobStore
+	limits AttachmentLimits
+	retention time.Duration
+	access    *accessCache
 }
 
-func NewTaskService(tasks *repositories.TaskRepo) *TaskService {
+func NewTaskService(tasks *repositories.TaskRepo, tracer trace.Tracer, blobs storage.BlobStore, limits AttachmentLimits, retention time.Duration) *TaskService {
 	cb := gobreaker.NewCircuitBreaker[interface{}](gobreaker.Settings{
 		Name:        "TaskServiceCB",
 		MaxRequests: 1,
@@ -44,14 +49,17 @@ func NewTaskService(tasks *repositories.TaskRepo) *TaskService {
 		Timeout: 5 * time.Second, // Globalni timeout
 	}
 
-	return &TaskService{tasks: tasks, cb: cb, client: client, tracer: tracer}
+	return &TaskService{tasks: tasks, cb: cb, client: client, tracer: tracer, blobs: blobs, limits: limits, retention: retention, access: newAccessCache()}
 }
 
-func (s TaskService) AddMember(taskId string, user domain.User) error {
+func (s TaskService) AddMember(ctx context.Context, taskId string, user domain.User) error {
 	task, err := s.tasks.FindById(taskId)
 	if err != nil {
 		return err
 	}
+	if task == nil {
+		return errors.New("task not found")
+	}
 
 	projectMembers, err := s.getProjectMembers(task.Project)
 	if err != nil {
@@ -76,7 +84,12 @@ func (s TaskService) AddMember(taskId string, user domain.User) error {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
-	return s.tasks.AddMember(task.Id, user)
+	if err := s.tasks.AddMember(task.Id, user); err != nil {
+		return err
+	}
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_TASK_MEMBER_ADDED, task.Id.Hex(), nil, map[string]interface{}{"username": user.Username})
+	return nil
 }
 
 // Funkcija za slanje notifikacije
@@ -128,42 +141,191 @@ func (s TaskService) sendNotification(username, message string) error {
 }
 
 // Create - Kreira novi zadatak sa prosleđenim parametrima
-func (s TaskService) Create(ctx context.Context,status domain.Status, name string, description string, projectID string) (domain.Task, error) {
+func (s TaskService) Create(ctx context.Context,status domain.Status, name string, description string, projectID string, schedule domain.Schedule) (domain.Task, error) {
 
 	ctx, span := s.tracer.Start(ctx, "TasksService.Create")
 	defer span.End()
-	
-	existingTask, err := s.tasks.FindByName(name)
+
+	return s.create(ctx, projectID, "", name, description, schedule)
+}
+
+// create stores a new task, or subtask when parent is set, in the initial
+// state of the project's workflow.
+func (s TaskService) create(ctx context.Context, projectID string, parent string, name string, description string, schedule domain.Schedule) (domain.Task, error) {
+	if err := schedule.Validate(); err != nil {
+		return domain.Task{}, err
+	}
+
+	existingTask, err := s.tasks.FindByName(projectID, name)
 	if err != nil {
 		return domain.Task{}, err
 	}
 	if existingTask != nil {
-		return domain.Task{}, errors.New("zadatak sa istim imenom već postoji")
+		return domain.Task{}, fmt.Errorf("%w: %s", domain.ErrTaskNameExists(), name)
+	}
+
+	workflow, err := s.GetWorkflow(ctx, projectID)
+	if err != nil {
+		return domain.Task{}, err
 	}
+	initial, _ := workflow.State(workflow.InitialState)
 
 	// Kreiraj novi zadatak
 	task := domain.Task{
 		Id:          primitive.NewObjectID(),
 		Project:     projectID,
+		Parent:      parent,
 		Name:        name,
 		Description: description,
-		Status:      1,
+		Status:      initial.CategoryStatus(),
+		State:       initial.Name,
+		CreatedAt:   time.Now(),
+	}
+	task.ApplySchedule(schedule)
+	if err := s.assignKey(ctx, &task); err != nil {
+		return domain.Task{}, err
+	}
+
+	created, err := s.tasks.Insert(ctx, task)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	s.settleKey(ctx, &created)
+
+	s.recordActivity(ctx, created.Project, domain.ACTIVITY_TASK_CREATED, created.Id.Hex(), nil, map[string]interface{}{
+		"name":        created.Name,
+		"description": created.Description,
+		"status":      created.Status.String(),
+		"state":       created.State,
+		"parent":      created.Parent,
+		"priority":    created.Priority,
+	})
+	s.recordStatusEvents(ctx, statusEvent(ctx, domain.EVENT_CREATED, nil, &created))
+	s.recordRevision(ctx, created, nil, map[string]interface{}{
+		"name":           created.Name,
+		"description":    created.Description,
+		"status":         created.Status.String(),
+		"state":          created.State,
+		"due_date":       created.DueDate,
+		"priority":       created.Priority,
+		"story_points":   created.StoryPoints,
+		"estimate_hours": created.EstimateHours,
+	})
+	return created, nil
+}
+
+// Update applies the fields of a patch to a task. With a version the update
+// only succeeds while the task is still at that version, without one it is
+// retried on top of concurrent changes.
+func (s TaskService) Update(ctx context.Context, patch domain.TaskPatch, version *int64) (domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TasksService.Update")
+	defer span.End()
+
+	if err := patch.Validate(); err != nil {
+		return domain.Task{}, err
 	}
 
-	return s.tasks.Insert(ctx, task)
+	for attempt := 1; ; attempt++ {
+		updatedTask, err := s.update(ctx, patch, version)
+		if errors.Is(err, domain.ErrVersionConflict()) && version == nil && attempt < 3 {
+			continue
+		}
+		return updatedTask, err
+	}
 }
 
-func (s TaskService) Update(id string, status domain.Status, name string, description string, projectID string) (domain.Task, error) {
-	existingTask, err := s.tasks.FindById(id)
-	if err != nil {
+func (s TaskService) update(ctx context.Context, patch domain.TaskPatch, version *int64) (domain.Task, error) {
+	existingTask, err := s.tasks.FindById(patch.Id)
+	if err != nil || existingTask == nil {
 		return domain.Task{}, errors.New("task doesn't exist")
 	}
+	if version != nil && existingTask.Version != *version {
+		return domain.Task{}, fmt.Errorf("%w: current version is %d", domain.ErrVersionConflict(), existingTask.Version)
+	}
 
-	existingTask.Name = name
-	existingTask.Description = description
-	existingTask.Status = status
+	workflow, err := s.GetWorkflow(ctx, existingTask.Project)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	state, status := "", domain.Status(0)
+	if patch.State != nil {
+		state = *patch.State
+	}
+	if patch.Status != nil {
+		status = *patch.Status
+	}
+	target, err := resolveState(workflow, existingTask, state, status)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	var transition *domain.Transition
+	if from := existingTask.CurrentState(); target.Name != from {
+		taken, err := checkTransition(ctx, workflow, from, target.Name)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		if err := s.checkBlockers(ctx, existingTask, target); err != nil {
+			return domain.Task{}, err
+		}
+		transition = &taken
+	}
+
+	previous := *existingTask
+	patch.Apply(existingTask)
+	if existingTask.Name != previous.Name {
+		sameName, err := s.tasks.FindByName(existingTask.Project, existingTask.Name)
+		if err != nil {
+			return domain.Task{}, err
+		}
+		if sameName != nil && sameName.Id != existingTask.Id {
+			return domain.Task{}, fmt.Errorf("%w: %s", domain.ErrTaskNameExists(), existingTask.Name)
+		}
+	}
+	existingTask.Status = target.CategoryStatus()
+	existingTask.State = target.Name
+	if existingTask.Status == domain.FINISHED && previous.Status != domain.FINISHED {
+		now := time.Now()
+		existingTask.FinishedAt = &now
+	} else if existingTask.Status != domain.FINISHED {
+		existingTask.FinishedAt = nil
+	}
+
+	before, after := taskDiff(previous, *existingTask)
+	if len(after) == 0 {
+		return previous, nil
+	}
+	fields := []string{"finished_at"}
+	for field := range after {
+		fields = append(fields, field)
+	}
+
+	updatedTask, err := s.tasks.Update(ctx, *existingTask, previous.Version, fields)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	s.recordRevision(ctx, updatedTask, before, after)
+	for _, field := range []string{"state", "status", "story_points"} {
+		if _, ok := after[field]; ok {
+			s.recordStatusEvents(ctx, statusEvent(ctx, domain.EVENT_CHANGED, &previous, &updatedTask))
+			break
+		}
+	}
+
+	action := domain.ACTIVITY_TASK_UPDATED
+	if _, ok := ctx.Value(revertKey{}).(int64); ok {
+		action = domain.ACTIVITY_TASK_REVERTED
+	} else if _, ok := after["state"]; ok {
+		action = domain.ACTIVITY_TASK_STATUS_CHANGED
+	}
+	s.recordActivity(ctx, updatedTask.Project, action, updatedTask.Id.Hex(), before, after)
+
+	if transition != nil {
+		s.runTransitionHooks(updatedTask, *transition)
+	}
+	s.recurOnFinish(ctx, previous, updatedTask)
 
-	updatedTask, err := s.tasks.Update(*existingTask)
 	for _, member := range updatedTask.Members {
 		if err := s.sendNotification(member.Username, "Task "+updatedTask.Name+" updated"); err != nil {
 			fmt.Printf("Error sending notification: %v\n", err)
@@ -173,6 +335,13 @@ func (s TaskService) Update(id string, status domain.Status, name string, descri
 	return updatedTask, err
 }
 
+func (s TaskService) GetTask(ctx context.Context, taskId string) (*domain.Task, error) {
+	_, span := s.tracer.Start(ctx, "TaskService.GetTask")
+	defer span.End()
+
+	return s.findTask(taskId)
+}
+
 func (s TaskService) FilterMembersNotOnTask(projectId, taskId string) (domain.Users, error) {
 	// Dobavi članove sa projekta
 	projectMembers, err := s.getProjectMembers(projectId)
@@ -246,17 +415,25 @@ func (s TaskService) getProjectMembers(projectId string) (domain.Users, error) {
 	return members, nil
 }
 
-func (s TaskService) RemoveMember(taskId string, user domain.User) error {
+func (s TaskService) RemoveMember(ctx context.Context, taskId string, user domain.User) error {
 	objID, err := primitive.ObjectIDFromHex(taskId)
 	task, err := s.tasks.FindById(taskId)
 	if err != nil {
 		return fmt.Errorf("invalid task ID: %v", err)
 	}
+	if task == nil {
+		return errors.New("task not found")
+	}
 
 	if err := s.sendNotification(user.Username, "You are deleted from task "+task.Name); err != nil {
 		fmt.Printf("Error sending notification: %v\n", err) // Dodato logovanje greške
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
-	return s.tasks.RemoveMember(objID, user)
+	if err := s.tasks.RemoveMember(objID, user); err != nil {
+		return err
+	}
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_TASK_MEMBER_REMOVED, task.Id.Hex(), map[string]interface{}{"username": user.Username}, nil)
+	return nil
 }
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+)
+
+// StartTimer starts tracking time on a task. A user can run only one timer
+// at a time.
+func (s TaskService) StartTimer(ctx context.Context, taskId string, username string, role string) (*domain.Worklog, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.StartTimer")
+	defer span.End()
+
+	task, err := s.findWorkTask(taskId, username, role)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := s.tasks.GetRunningWorklog(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return nil, fmt.Errorf("%w on task %s", domain.ErrTimerRunning(), running.Task)
+	}
+
+	now := time.Now()
+	worklog := &domain.Worklog{
+		Task:      taskId,
+		Project:   task.Project,
+		Username:  username,
+		Start:     now,
+		CreatedAt: now,
+	}
+	if err := s.tasks.InsertWorklog(ctx, worklog); err != nil {
+		return nil, err
+	}
+	return worklog, nil
+}
+
+// StopTimer stops the running timer of a user on a task and stores the time
+// spent, at least one minute.
+func (s TaskService) StopTimer(ctx context.Context, taskId string, username string) (*domain.Worklog, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.StopTimer")
+	defer span.End()
+
+	running, err := s.tasks.GetRunningWorklog(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if running == nil || running.Task != taskId {
+		return nil, errors.New("running timer not found")
+	}
+
+	end := time.Now()
+	minutes := int(math.Max(1, math.Round(end.Sub(running.Start).Minutes())))
+	stopped, err := s.tasks.StopWorklog(ctx, running.Id, end, minutes)
+	if err != nil {
+		return nil, err
+	}
+	if !stopped {
+		return nil, errors.New("running timer not found")
+	}
+	running.End = &end
+	running.Minutes = minutes
+
+	s.recordActivity(ctx, running.Project, domain.ACTIVITY_TIME_LOGGED, taskId, nil, map[string]interface{}{"username": username, "minutes": minutes})
+	return running, nil
+}
+
+// GetRunningTimer returns the running timer of a user, nil if there is none.
+func (s TaskService) GetRunningTimer(ctx context.Context, username string) (*domain.Worklog, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetRunningTimer")
+	defer span.End()
+
+	return s.tasks.GetRunningWorklog(ctx, username)
+}
+
+// LogWork stores time spent on a task that wasn't tracked with a timer.
+func (s TaskService) LogWork(ctx context.Context, taskId string, username string, role string, start time.Time, minutes int, note string) (*domain.Worklog, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.LogWork")
+	defer span.End()
+
+	if minutes <= 0 {
+		return nil, fmt.Errorf("%w: minutes must be positive", domain.ErrInvalidInput())
+	}
+	if start.IsZero() {
+		start = time.Now().Add(-time.Duration(minutes) * time.Minute)
+	}
+	end := start.Add(time.Duration(minutes) * time.Minute)
+	if end.After(time.Now()) {
+		return nil, fmt.Errorf("%w: worklog can't end in the future", domain.ErrInvalidInput())
+	}
+
+	task, err := s.findWorkTask(taskId, username, role)
+	if err != nil {
+		return nil, err
+	}
+
+	worklog := &domain.Worklog{
+		Task:      taskId,
+		Project:   task.Project,
+		Username:  username,
+		Start:     start,
+		End:       &end,
+		Minutes:   minutes,
+		Note:      note,
+		Manual:    true,
+		CreatedAt: time.Now(),
+	}
+	if err := s.tasks.InsertWorklog(ctx, worklog); err != nil {
+		return nil, err
+	}
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_TIME_LOGGED, taskId, nil, map[string]interface{}{"username": username, "minutes": minutes})
+	return worklog, nil
+}
+
+// DeleteWorklog removes a worklog. Members can only remove their own.
+func (s TaskService) DeleteWorklog(ctx context.Context, id string, username string, role string) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.DeleteWorklog")
+	defer span.End()
+
+	worklog, err := s.tasks.GetWorklogById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if worklog == nil {
+		return errors.New("worklog not found")
+	}
+	if worklog.Username != username && role != "PROJECT_MANAGER" {
+		return domain.ErrUnauthorized()
+	}
+
+	return s.tasks.DeleteWorklog(ctx, worklog.Id)
+}
+
+// GetTaskTime returns the worklogs of a task with the time logged per member.
+func (s TaskService) GetTaskTime(ctx context.Context, taskId string) (*domain.TimeSummary, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetTaskTime")
+	defer span.End()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.WorklogFilter{Task: taskId}
+	worklogs, err := s.tasks.GetWorklogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	byMember, err := s.tasks.SumWorklogs(ctx, filter, "username")
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TimeSummary{
+		Target:        taskId,
+		TotalMinutes:  totalMinutes(byMember),
+		EstimateHours: task.EstimateHours,
+		ByMember:      byMember,
+		Worklogs:      worklogs,
+	}, nil
+}
+
+// GetProjectTime returns the time logged on a project per member and task.
+func (s TaskService) GetProjectTime(ctx context.Context, projectId string) (*domain.TimeSummary, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetProjectTime")
+	defer span.End()
+
+	filter := domain.WorklogFilter{Project: projectId}
+	byMember, err := s.tasks.SumWorklogs(ctx, filter, "username")
+	if err != nil {
+		return nil, err
+	}
+	byTask, err := s.tasks.SumWorklogs(ctx, filter, "task")
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.tasks.GetByProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	estimate := 0.0
+	for _, task := range tasks {
+		estimate += task.EstimateHours
+	}
+
+	return &domain.TimeSummary{
+		Target:        projectId,
+		TotalMinutes:  totalMinutes(byMember),
+		EstimateHours: estimate,
+		ByMember:      byMember,
+		ByTask:        byTask,
+	}, nil
+}
+
+// ExportWorklogs returns the worklogs of a project started in [from, to).
+func (s TaskService) ExportWorklogs(ctx context.Context, projectId string, from *time.Time, to *time.Time) (domain.Worklogs, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.ExportWorklogs")
+	defer span.End()
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, fmt.Errorf("%w: from must be before to", domain.ErrInvalidInput())
+	}
+	return s.tasks.GetWorklogs(ctx, domain.WorklogFilter{Project: projectId, From: from, To: to})
+}
+
+// findWorkTask returns a task the user may log time on: managers on any task,
+// members on the tasks they are assigned to.
+func (s TaskService) findWorkTask(taskId string, username string, role string) (*domain.Task, error) {
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if role == "PROJECT_MANAGER" {
+		return task, nil
+	}
+	for _, member := range task.Members {
+		if member.Username == username {
+			return task, nil
+		}
+	}
+	return nil, domain.ErrUnauthorized()
+}
+
+func totalMinutes(totals []domain.TimeTotal) int {
+	total := 0
+	for _, t := range totals {
+		total += t.Minutes
+	}
+	return total
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"project-management-app/microservices/projects-service/domain"
+	"time"
+)
+
+// calendarFeedDays is how far back the calendar feed of a user goes.
+const calendarFeedDays = 90
+
+// GetTimeline schedules the tasks of a project up to the end date of the
+// project, for Gantt charts.
+func (s TaskService) GetTimeline(ctx context.Context, projectId string) (*domain.Timeline, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetTimeline")
+	defer span.End()
+
+	project, err := s.projectAccess(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.tasks.GetByProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewTimeline(projectId, project.Name, project.EndDate, tasks, time.Now()), nil
+}
+
+// GetCalendarFeed returns the calendar subscription of a user, creating it
+// the first time.
+func (s TaskService) GetCalendarFeed(ctx context.Context, username string) (*domain.CalendarFeed, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetCalendarFeed")
+	defer span.End()
+
+	feed, err := s.tasks.GetCalendarFeed(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if feed != nil {
+		return feed, nil
+	}
+	return s.newCalendarFeed(ctx, username)
+}
+
+// ResetCalendarFeed gives a user a new calendar subscription URL, the old one
+// stops working.
+func (s TaskService) ResetCalendarFeed(ctx context.Context, username string) (*domain.CalendarFeed, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.ResetCalendarFeed")
+	defer span.End()
+
+	return s.newCalendarFeed(ctx, username)
+}
+
+// GetCalendar returns the tasks assigned to the owner of a calendar feed,
+// due from calendarFeedDays ago on.
+func (s TaskService) GetCalendar(ctx context.Context, token string) (*domain.Timeline, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetCalendar")
+	defer span.End()
+
+	feed, err := s.tasks.GetCalendarFeedByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, errors.New("calendar not found")
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -calendarFeedDays)
+	tasks, err := s.tasks.QueryTasks(ctx, domain.TaskQuery{Assignee: feed.Username, DueFrom: &since})
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewDueCalendar(feed.Username, tasks, now), nil
+}
+
+func (s TaskService) newCalendarFeed(ctx context.Context, username string) (*domain.CalendarFeed, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	feed := &domain.CalendarFeed{Username: username, Token: hex.EncodeToString(secret), CreatedAt: time.Now()}
+	if err := s.tasks.SaveCalendarFeed(ctx, feed); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeleteTask moves a task and all of its subtasks to the trash. Running
+// timers on them are stopped and their members notified.
+func (s TaskService) DeleteTask(ctx context.Context, taskId string, username string) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.DeleteTask")
+	defer span.End()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := s.tasks.GetByProject(ctx, task.Project)
+	if err != nil {
+		return err
+	}
+	deleted := subtree(task, tasks)
+
+	ids := make([]primitive.ObjectID, 0, len(deleted))
+	taskIds := make([]string, 0, len(deleted))
+	for _, t := range deleted {
+		ids = append(ids, t.Id)
+		taskIds = append(taskIds, t.Id.Hex())
+	}
+
+	now := time.Now()
+	if err := s.tasks.TrashTasks(ctx, ids, taskId, username, now); err != nil {
+		return err
+	}
+	if err := s.tasks.StopTaskWorklogs(ctx, taskIds, now); err != nil {
+		return err
+	}
+
+	events := domain.StatusEvents{}
+	for _, t := range deleted {
+		events = append(events, statusEvent(ctx, domain.EVENT_DELETED, t, t))
+	}
+	s.recordStatusEvents(ctx, events...)
+
+	for _, t := range deleted {
+		for _, member := range t.Members {
+			if err := s.sendNotification(member.Username, "Task "+t.Name+" was deleted"); err != nil {
+				log.Printf("Error sending notification: %v\n", err)
+			}
+		}
+	}
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_TASK_DELETED, taskId, map[string]interface{}{"name": task.Name, "subtasks": len(deleted) - 1}, nil)
+	return nil
+}
+
+// GetTrash returns the deleted tasks of a project.
+func (s TaskService) GetTrash(ctx context.Context, projectId string) (domain.Trash, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetTrash")
+	defer span.End()
+
+	tasks, err := s.tasks.GetTrash(ctx, projectId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	trash := domain.Trash{}
+	for _, task := range tasks {
+		trash = append(trash, &domain.TrashedTask{Task: task, PurgeAt: task.DeletedAt.Add(s.retention)})
+	}
+	return trash, nil
+}
+
+// RestoreTask takes a deleted task and the subtasks deleted with it out of
+// the trash. Nothing is restored while any of their names is taken by an
+// active task. Tasks in states that were removed from the workflow since go
+// back to the initial state.
+func (s TaskService) RestoreTask(ctx context.Context, taskId string) (*domain.Task, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.RestoreTask")
+	defer span.End()
+
+	task, err := s.findTrashedRoot(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Parent != "" {
+		parent, err := s.tasks.FindById(task.Parent)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, fmt.Errorf("%w: parent task is deleted, restore it first", domain.ErrRestoreConflict())
+		}
+	}
+
+	trash, err := s.tasks.GetTrash(ctx, task.Project, nil)
+	if err != nil {
+		return nil, err
+	}
+	restored := domain.Tasks{}
+	ids := []primitive.ObjectID{}
+	for _, t := range trash {
+		if t.DeletedWith == taskId {
+			restored = append(restored, t)
+			ids = append(ids, t.Id)
+		}
+	}
+
+	taken := []string{}
+	for _, t := range restored {
+		existing, err := s.tasks.FindByName(t.Project, t.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			taken = append(taken, strconv.Quote(t.Name))
+		}
+	}
+	if len(taken) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrTaskNameExists(), strings.Join(taken, ", "))
+	}
+
+	workflow, err := s.GetWorkflow(ctx, task.Project)
+	if err != nil {
+		return nil, err
+	}
+	initial, _ := workflow.State(workflow.InitialState)
+	moved := []primitive.ObjectID{}
+	for _, t := range restored {
+		if _, ok := workflow.State(t.CurrentState()); ok {
+			continue
+		}
+		moved = append(moved, t.Id)
+		t.State = initial.Name
+		t.Status = initial.CategoryStatus()
+		if t.Status != domain.FINISHED {
+			t.FinishedAt = nil
+		}
+	}
+	if len(moved) > 0 {
+		if err := s.tasks.SetTrashedState(ctx, moved, initial); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.tasks.RestoreTasks(ctx, task, ids); err != nil {
+		return nil, err
+	}
+
+	events := domain.StatusEvents{}
+	for _, t := range restored {
+		events = append(events, statusEvent(ctx, domain.EVENT_RESTORED, t, t))
+	}
+	s.recordStatusEvents(ctx, events...)
+
+	s.recordActivity(ctx, task.Project, domain.ACTIVITY_TASK_RESTORED, taskId, nil, map[string]interface{}{"name": task.Name})
+	return s.findTask(taskId)
+}
+
+// PurgeTask permanently deletes a task from the trash before its retention
+// window ends.
+func (s TaskService) PurgeTask(ctx context.Context, taskId string) error {
+	ctx, span := s.tracer.Start(ctx, "TaskService.PurgeTask")
+	defer span.End()
+
+	task, err := s.findTrashedRoot(ctx, taskId)
+	if err != nil {
+		return err
+	}
+
+	trash, err := s.tasks.GetTrash(ctx, task.Project, nil)
+	if err != nil {
+		return err
+	}
+	deleted := domain.Tasks{}
+	for _, t := range trash {
+		if t.DeletedWith == taskId {
+			deleted = append(deleted, t)
+		}
+	}
+	return s.purge(ctx, deleted)
+}
+
+// PurgeExpiredTasks permanently deletes the tasks that have been in the
+// trash longer than the retention window and returns how many were deleted.
+func (s TaskService) PurgeExpiredTasks(ctx context.Context) (int, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.PurgeExpiredTasks")
+	defer span.End()
+
+	before := time.Now().Add(-s.retention)
+	expired, err := s.tasks.GetTrash(ctx, "", &before)
+	if err != nil {
+		return 0, err
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	if err := s.purge(ctx, expired); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
+
+// purge deletes trashed tasks with everything attached to them.
+func (s TaskService) purge(ctx context.Context, tasks domain.Tasks) error {
+	ids := make([]primitive.ObjectID, 0, len(tasks))
+	for _, task := range tasks {
+		if err := s.deleteTaskAttachments(ctx, task.Id.Hex()); err != nil {
+			return err
+		}
+		ids = append(ids, task.Id)
+	}
+
+	if err := s.tasks.PurgeTasks(ctx, ids); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if task.DeletedWith == task.Id.Hex() {
+			s.recordActivity(ctx, task.Project, domain.ACTIVITY_TASK_PURGED, task.Id.Hex(), map[string]interface{}{"name": task.Name}, nil)
+		}
+	}
+	return nil
+}
+
+// findTrashedRoot returns a task the user deleted. Subtasks deleted together
+// with their parent can only be restored or purged through it.
+func (s TaskService) findTrashedRoot(ctx context.Context, taskId string) (*domain.Task, error) {
+	task, err := s.tasks.FindTrashedById(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, errors.New("task not found in trash")
+	}
+	if task.DeletedWith != taskId {
+		return nil, fmt.Errorf("%w: it was deleted together with task %s", domain.ErrRestoreConflict(), task.DeletedWith)
+	}
+	return task, nil
+}
+
+// subtree returns a task followed by all of its subtasks.
+func subtree(root *domain.Task, tasks domain.Tasks) domain.Tasks {
+	children := map[string]domain.Tasks{}
+	for _, t := range tasks {
+		if t.Parent != "" {
+			children[t.Parent] = append(children[t.Parent], t)
+		}
+	}
+
+	result := domain.Tasks{root}
+	visited := map[string]bool{root.Id.Hex(): true}
+	for i := 0; i < len(result); i++ {
+		for _, child := range children[result[i].Id.Hex()] {
+			if !visited[child.Id.Hex()] {
+				visited[child.Id.Hex()] = true
+				result = append(result, child)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"project-management-app/microservices/projects-service/domain"
+	"strings"
+	"time"
+
+	authorizationlib "github.com/Bijelic03/authorizationlibGo"
+)
+
+// GetWorkflow returns the workflow of a project, the default one when the
+// project has not configured its own.
+func (s TaskService) GetWorkflow(ctx context.Context, projectId string) (*domain.Workflow, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetWorkflow")
+	defer span.End()
+
+	workflow, err := s.tasks.GetWorkflow(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	if workflow == nil {
+		return domain.DefaultWorkflow(projectId), nil
+	}
+	return workflow, nil
+}
+
+// SaveWorkflow replaces the workflow of a project. States that still have
+// tasks in them can't be removed.
+func (s TaskService) SaveWorkflow(ctx context.Context, projectId string, workflow *domain.Workflow) (*domain.Workflow, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.SaveWorkflow")
+	defer span.End()
+
+	workflow.Project = projectId
+	if err := workflow.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWorkflow(), err)
+	}
+
+	previous, err := s.GetWorkflow(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	statuses := []domain.Status{}
+	for _, state := range previous.States {
+		if _, ok := workflow.State(state.Name); ok {
+			continue
+		}
+		removed = append(removed, state.Name)
+		if status, err := domain.StatusFromString(state.Name); err == nil {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(removed) > 0 {
+		count, err := s.tasks.CountTasksInStates(ctx, projectId, removed, statuses)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("%w: %d tasks, including trashed ones, are still in states %s", domain.ErrInvalidWorkflow(), count, strings.Join(removed, ", "))
+		}
+	}
+
+	workflow.UpdatedAt = time.Now()
+	if err := s.tasks.SaveWorkflow(ctx, workflow); err != nil {
+		return nil, err
+	}
+
+	s.recordTargetActivity(ctx, projectId, domain.ACTIVITY_WORKFLOW_UPDATED, "workflow", projectId, workflowActivity(previous), workflowActivity(workflow))
+	return workflow, nil
+}
+
+// resolveState returns the workflow state a task should move to. An explicit
+// state wins, otherwise the first state of the requested status is used. A
+// task whose state was removed from the workflow moves to the first state of
+// its status.
+func resolveState(workflow *domain.Workflow, task *domain.Task, state string, status domain.Status) (domain.WorkflowState, error) {
+	if state != "" {
+		target, ok := workflow.State(state)
+		if !ok {
+			return domain.WorkflowState{}, fmt.Errorf("%w: unknown state %s", domain.ErrTransitionNotAllowed(), state)
+		}
+		return target, nil
+	}
+
+	if current, ok := workflow.State(task.CurrentState()); ok && (status == 0 || current.CategoryStatus() == status) {
+		return current, nil
+	}
+	if status == 0 {
+		status = task.Status
+	}
+	target, ok := workflow.StateForStatus(status)
+	if !ok {
+		return domain.WorkflowState{}, fmt.Errorf("%w: no state for status %s", domain.ErrTransitionNotAllowed(), statusName(status))
+	}
+	return target, nil
+}
+
+// checkTransition validates moving a task to another state for the user in
+// the context and returns the transition that is taken.
+func checkTransition(ctx context.Context, workflow *domain.Workflow, from string, to string) (domain.Transition, error) {
+	transition, ok := workflow.Transition(from, to)
+	if !ok {
+		return domain.Transition{}, fmt.Errorf("%w: %s -> %s", domain.ErrTransitionNotAllowed(), from, to)
+	}
+
+	role, _ := ctx.Value(authorizationlib.RoleKey).(string)
+	if !transition.Allows(role) {
+		return domain.Transition{}, fmt.Errorf("%w: %s -> %s", domain.ErrTransitionForbidden(), from, to)
+	}
+	return transition, nil
+}
+
+// runTransitionHooks sends the notifications configured on a transition.
+// Every user is notified at most once.
+func (s TaskService) runTransitionHooks(task domain.Task, transition domain.Transition) {
+	notified := map[string]bool{}
+	for _, hook := range transition.Hooks {
+		message := hook.Message
+		if message == "" {
+			message = fmt.Sprintf("Task %s moved from %s to %s", task.Name, transition.From, transition.To)
+		}
+
+		for _, username := range hookRecipients(task, hook) {
+			if notified[username] {
+				continue
+			}
+			notified[username] = true
+			if err := s.sendNotification(username, message); err != nil {
+				log.Printf("Error sending transition notification to %s: %v\n", username, err)
+			}
+		}
+	}
+}
+
+func hookRecipients(task domain.Task, hook domain.TransitionHook) []string {
+	recipients := []string{}
+	for _, target := range hook.Notify {
+		if target != domain.NOTIFY_MEMBERS {
+			recipients = append(recipients, target)
+			continue
+		}
+		for _, member := range task.Members {
+			recipients = append(recipients, member.Username)
+		}
+	}
+	return recipients
+}
+
+func workflowActivity(workflow *domain.Workflow) map[string]interface{} {
+	states := []string{}
+	for _, state := range workflow.States {
+		states = append(states, state.Name)
+	}
+	return map[string]interface{}{
+		"initial_state": workflow.InitialState,
+		"states":        states,
+		"transitions":   len(workflow.Transitions),
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"math"
+	"project-management-app/microservices/projects-service/domain"
+)
+
+// GetWorkload returns the open work of users across all projects, least
+// loaded first. Requested users without open tasks are included with no
+// work, without users every assigned user is reported.
+func (s TaskService) GetWorkload(ctx context.Context, usernames []string) (domain.Workloads, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetWorkload")
+	defer span.End()
+
+	workloads, err := s.tasks.GetWorkload(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for _, workload := range workloads {
+		known[workload.Username] = true
+		workload.EstimateHours = math.Round(workload.EstimateHours*100) / 100
+		workload.StoryPoints = math.Round(workload.StoryPoints*100) / 100
+		for i := range workload.Projects {
+			workload.Projects[i].EstimateHours = math.Round(workload.Projects[i].EstimateHours*100) / 100
+			workload.Projects[i].StoryPoints = math.Round(workload.Projects[i].StoryPoints*100) / 100
+		}
+	}
+	for _, username := range usernames {
+		if !known[username] {
+			known[username] = true
+			workloads = append(workloads, &domain.UserWorkload{Username: username, Projects: []domain.ProjectLoad{}})
+		}
+	}
+
+	workloads.SortByLoad()
+	return workloads, nil
+}
+
+// SortByWorkload orders users from the least to the most loaded across all
+// projects.
+func (s TaskService) SortByWorkload(ctx context.Context, users domain.Users) (domain.Users, error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.SortByWorkload")
+	defer span.End()
+
+	usernames := make([]string, 0, len(users))
+	byName := map[string]*domain.User{}
+	for _, user := range users {
+		usernames = append(usernames, user.Username)
+		byName[user.Username] = user
+	}
+	if len(usernames) == 0 {
+		return users, nil
+	}
+
+	workloads, err := s.GetWorkload(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	sorted := make(domain.Users, 0, len(users))
+	for _, workload := range workloads {
+		sorted = append(sorted, byName[workload.Username])
+	}
+	return sorted, nil
+}
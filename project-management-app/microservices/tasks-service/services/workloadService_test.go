@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"project-management-app/microservices/projects-service/domain"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// aggregated is the workload the database sums up for the users, with
+// estimates split three ways between the members of a task.
+func aggregated() []interface{} {
+	return []interface{}{
+		&domain.UserWorkload{Username: "ana", Open: 3, InProgress: 1, EstimateHours: 6.666666, StoryPoints: 2.333333, Projects: []domain.ProjectLoad{
+			{Project: "p1", Open: 2, EstimateHours: 3.333333, StoryPoints: 1.666666},
+			{Project: "p2", Open: 1, EstimateHours: 3.333333, StoryPoints: 0.666667},
+		}},
+		&domain.UserWorkload{Username: "ivan", Open: 1, EstimateHours: 1.5, Projects: []domain.ProjectLoad{{Project: "p1", Open: 1, EstimateHours: 1.5}}},
+		&domain.UserWorkload{Username: "mila", Open: 4, EstimateHours: 1.5, Projects: []domain.ProjectLoad{{Project: "p2", Open: 4, EstimateHours: 1.5}}},
+	}
+}
+
+// sentMatch returns the first stage of the aggregation sent to the tasks.
+func sentMatch(mt *mtest.T) bson.Raw {
+	for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+		if event.CommandName == "aggregate" {
+			return event.Command.Lookup("pipeline").Array().Index(0).Value().Document().Lookup("$match").Document()
+		}
+	}
+	mt.Fatal("the workload wasn't aggregated")
+	return nil
+}
+
+func TestGetWorkload(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("of the requested users", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		mt.AddMockResponses(cursor(mt, "tasks", aggregated()...))
+
+		workloads, err := s.GetWorkload(context.Background(), []string{"mila", "ana", "zoe", "ivan"})
+		if err != nil {
+			mt.Fatalf("GetWorkload: %v", err)
+		}
+
+		usernames, _ := sentMatch(mt).Lookup("members.username", "$in").Array().Values()
+		if len(usernames) != 4 {
+			mt.Errorf("aggregated the work of %v, want the four requested users", usernames)
+		}
+
+		// zoe has no open tasks and comes first, ivan and mila have as many
+		// hours and ivan fewer tasks.
+		var order []string
+		for _, workload := range workloads {
+			order = append(order, workload.Username)
+		}
+		if len(order) != 4 || order[0] != "zoe" || order[1] != "ivan" || order[2] != "mila" || order[3] != "ana" {
+			mt.Fatalf("workloads ordered %v, want zoe, ivan, mila, ana", order)
+		}
+		if zoe := workloads[0]; zoe.Open != 0 || zoe.Projects == nil {
+			mt.Errorf("zoe has %+v, want no work and an empty list of projects", zoe)
+		}
+
+		ana := workloads[3]
+		if ana.EstimateHours != 6.67 || ana.StoryPoints != 2.33 {
+			mt.Errorf("ana has %v hours, %v points, want them rounded to 6.67 and 2.33", ana.EstimateHours, ana.StoryPoints)
+		}
+		if p1 := ana.Projects[0]; p1.EstimateHours != 3.33 || p1.StoryPoints != 1.67 {
+			mt.Errorf("ana has %v hours, %v points in p1, want 3.33 and 1.67", p1.EstimateHours, p1.StoryPoints)
+		}
+	})
+
+	mt.Run("of every assigned user", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		mt.AddMockResponses(cursor(mt, "tasks", aggregated()...))
+
+		workloads, err := s.GetWorkload(context.Background(), nil)
+		if err != nil {
+			mt.Fatalf("GetWorkload: %v", err)
+		}
+		if _, err := sentMatch(mt).LookupErr("members.username"); err == nil {
+			mt.Error("aggregated only some of the users")
+		}
+		if len(workloads) != 3 {
+			mt.Errorf("got %d workloads, want one for each assigned user", len(workloads))
+		}
+	})
+}
+
+func TestSortByWorkload(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	users := domain.Users{
+		{Username: "ana", Name: "Ana"},
+		{Username: "ivan", Name: "Ivan"},
+		{Username: "zoe", Name: "Zoe"},
+	}
+
+	mt.Run("least loaded first", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+		mt.AddMockResponses(cursor(mt, "tasks", aggregated()[:2]...))
+
+		sorted, err := s.SortByWorkload(context.Background(), users)
+		if err != nil {
+			mt.Fatalf("SortByWorkload: %v", err)
+		}
+		if len(sorted) != 3 || sorted[0] != users[2] || sorted[1] != users[1] || sorted[2] != users[0] {
+			mt.Fatalf("sorted %v, want zoe, ivan, ana", sorted)
+		}
+	})
+
+	mt.Run("no users", func(mt *mtest.T) {
+		s, _ := newTestService(mt)
+
+		sorted, err := s.SortByWorkload(context.Background(), domain.Users{})
+		if err != nil || len(sorted) != 0 {
+			mt.Fatalf("SortByWorkload of no users = %v, %v", sorted, err)
+		}
+		if event := mt.GetStartedEvent(); event != nil {
+			mt.Errorf("sent %s for no users", event.CommandName)
+		}
+	})
+}
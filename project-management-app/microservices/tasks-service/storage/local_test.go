@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		invalid bool
+	}{
+		{"nested key", "tasks/1/report.pdf", "tasks/1/report.pdf", false},
+		{"dot segments inside root", "tasks/../other/a.txt", "other/a.txt", false},
+		{"absolute key stays below root", "/etc/passwd", "etc/passwd", false},
+		{"parent directory", "../escape.txt", "", true},
+		{"climbing out through a subdirectory", "tasks/../../escape.txt", "", true},
+		{"sibling sharing the root prefix", "../root-sibling/a.txt", "", true},
+		{"root itself", "", "", true},
+		{"dot", ".", "", true},
+	}
+
+	root := filepath.Join(t.TempDir(), "root")
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := store.path(tt.key)
+			if tt.invalid {
+				if err == nil {
+					t.Fatalf("path(%q) = %q, want an error", tt.key, path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("path(%q): %v", tt.key, err)
+			}
+			if want := filepath.Join(root, filepath.FromSlash(tt.want)); path != want {
+				t.Errorf("path(%q) = %q, want %q", tt.key, path, want)
+			}
+		})
+	}
+}
+
+func TestLocalStoreRejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(dir, "root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "../escape.txt", strings.NewReader("x"), 1, "text/plain", ""); err == nil {
+		t.Error("Put outside the root succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file outside the root was written: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "../secret.txt"); err == nil {
+		t.Error("Get outside the root succeeded")
+	}
+	if err := store.Delete(ctx, "../secret.txt"); err == nil {
+		t.Error("Delete outside the root succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "secret.txt")); err != nil {
+		t.Errorf("file outside the root was deleted: %v", err)
+	}
+}
+
+func TestLocalStorePutGetDelete(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	key := "tasks/1/report.txt"
+
+	if err := store.Put(ctx, key, strings.NewReader("report"), 6, "text/plain", ""); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	blob, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	content, err := io.ReadAll(blob)
+	blob.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "report" {
+		t.Errorf("Get = %q, want %q", content, "report")
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrBlobNotFound", err)
+	}
+
+	// A short upload leaves nothing behind.
+	if err := store.Put(ctx, key, strings.NewReader("short"), 10, "text/plain", ""); err == nil {
+		t.Error("Put with a wrong size succeeded")
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get after a short Put = %v, want ErrBlobNotFound", err)
+	}
+}
@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "minio"
+	testSecretKey = "minio-secret"
+	testRegion    = "us-east-1"
+	testBucket    = "attachments"
+)
+
+// fakeS3 is a stand-in for MinIO. It checks the Signature Version 4 of every
+// request on its own and keeps objects in memory.
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]bool
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	fake := &fakeS3{buckets: map[string]bool{}, objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := verifySignature(r, body); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, key, _ := strings.Cut(path, "/")
+	if key == "" {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if f.buckets[bucket] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.buckets[bucket] = true
+		return
+	}
+	if !f.buckets[bucket] {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[path] = body
+		f.types[path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		object, ok := f.objects[path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(object)
+	case http.MethodDelete:
+		delete(f.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySignature recomputes the signature of a request from what the server
+// received, the way S3 does.
+func verifySignature(r *http.Request, body []byte) error {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") {
+		return errors.New("missing signature")
+	}
+	fields := map[string]string{}
+	for _, field := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ", ") {
+		name, value, _ := strings.Cut(field, "=")
+		fields[name] = value
+	}
+
+	credential := strings.Split(fields["Credential"], "/")
+	if len(credential) != 5 || credential[0] != testAccessKey || credential[2] != testRegion || credential[3] != "s3" || credential[4] != "aws4_request" {
+		return errors.New("invalid credential " + fields["Credential"])
+	}
+	date := credential[1]
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	signedAt, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil || !strings.HasPrefix(amzDate, date) || time.Since(signedAt).Abs() > 15*time.Minute {
+		return errors.New("invalid request date " + amzDate)
+	}
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash != "UNSIGNED-PAYLOAD" {
+		sum := sha256.Sum256(body)
+		if payloadHash != hex.EncodeToString(sum[:]) {
+			return errors.New("XAmzContentSHA256Mismatch")
+		}
+	}
+
+	signedHeaders := strings.Split(fields["SignedHeaders"], ";")
+	canonicalHeaders := ""
+	for _, name := range signedHeaders {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders += name + ":" + strings.TrimSpace(value) + "\n"
+	}
+	for _, required := range []string{"host", "x-amz-content-sha256", "x-amz-date"} {
+		if !strings.Contains(";"+fields["SignedHeaders"]+";", ";"+required+";") {
+			return errors.New(required + " is not signed")
+		}
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method, r.URL.EscapedPath(), r.URL.RawQuery, canonicalHeaders, fields["SignedHeaders"], payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := strings.Join(credential[1:], "/")
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{date, testRegion, "s3", "aws4_request"} {
+		key = hmacSign(key, part)
+	}
+	if expected := hex.EncodeToString(hmacSign(key, stringToSign)); !hmac.Equal([]byte(expected), []byte(fields["Signature"])) {
+		return errors.New("SignatureDoesNotMatch")
+	}
+	return nil
+}
+
+func hmacSign(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func checksumOf(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestNewS3StoreCreatesBucket(t *testing.T) {
+	fake, server := newFakeS3(t)
+
+	if _, err := NewS3Store(server.URL, testRegion, testBucket, testAccessKey, testSecretKey); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.buckets[testBucket] {
+		t.Fatal("bucket was not created")
+	}
+	// A second store finds the bucket already there.
+	if _, err := NewS3Store(server.URL, testRegion, testBucket, testAccessKey, testSecretKey); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestS3StorePutGetDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		content     string
+		contentType string
+		checksum    string
+	}{
+		{"signed payload", "tasks/1/report.pdf", "%PDF-1.7", "application/pdf", checksumOf("%PDF-1.7")},
+		{"unsigned payload", "tasks/1/notes.txt", "notes", "text/plain", ""},
+		{"no content type", "tasks/2/blob", "blob", "", checksumOf("blob")},
+		{"key to encode", "tasks/3/plan v2 (final)+ž.txt", "plan", "text/plain", checksumOf("plan")},
+		{"empty content", "tasks/4/empty", "", "text/plain", checksumOf("")},
+	}
+
+	fake, server := newFakeS3(t)
+	store, err := NewS3Store(server.URL, testRegion, testBucket, testAccessKey, testSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.Put(ctx, tt.key, strings.NewReader(tt.content), int64(len(tt.content)), tt.contentType, tt.checksum)
+			if err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			if got := fake.types[testBucket+"/"+tt.key]; got != tt.contentType {
+				t.Errorf("content type = %q, want %q", got, tt.contentType)
+			}
+
+			blob, err := store.Get(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			content, err := io.ReadAll(blob)
+			blob.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.content {
+				t.Errorf("Get = %q, want %q", content, tt.content)
+			}
+
+			if err := store.Delete(ctx, tt.key); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := store.Get(ctx, tt.key); !errors.Is(err, ErrBlobNotFound) {
+				t.Errorf("Get after Delete = %v, want ErrBlobNotFound", err)
+			}
+			// Deleting a missing blob is not an error.
+			if err := store.Delete(ctx, tt.key); err != nil {
+				t.Errorf("second Delete: %v", err)
+			}
+		})
+	}
+}
+
+func TestS3StoreRejectedRequests(t *testing.T) {
+	_, server := newFakeS3(t)
+	if _, err := NewS3Store(server.URL, testRegion, testBucket, testAccessKey, testSecretKey); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	wrongSecret, err := NewS3Store(server.URL, testRegion, testBucket, testAccessKey, "wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wrongSecret.Put(ctx, "a", strings.NewReader("a"), 1, "text/plain", checksumOf("a")); err == nil {
+		t.Error("Put with a wrong secret succeeded")
+	}
+	if _, err := wrongSecret.Get(ctx, "a"); err == nil || errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get with a wrong secret = %v, want a signature error", err)
+	}
+
+	store, err := NewS3Store(server.URL, testRegion, testBucket, testAccessKey, testSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, "b", bytes.NewReader([]byte("tampered")), 8, "text/plain", checksumOf("original")); err == nil {
+		t.Error("Put with a wrong checksum succeeded")
+	}
+}
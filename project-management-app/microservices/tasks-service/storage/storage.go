@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"project-management-app/microservices/projects-service/config"
+)
+
+var ErrBlobNotFound = errors.New("attachment content not found")
+
+// BlobStore keeps the content of task attachments. Keys are slash separated
+// paths chosen by the caller.
+type BlobStore interface {
+	// Put stores size bytes read from r. checksum is the hex encoded SHA-256
+	// of the content, drivers that support it let the server verify it.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string, checksum string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the blob store selected by the configuration.
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStore(cfg.Path)
+	case "s3":
+		return NewS3Store(cfg.Endpoint, cfg.Region, cfg.Bucket, cfg.AccessKey, cfg.SecretKey)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %s", cfg.Driver)
+	}
+}
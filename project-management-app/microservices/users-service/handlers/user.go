@@ -152,6 +152,10 @@ func (h UserHandler) GetAvailableMembers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if r.URL.Query().Get("sort") == "workload" {
+		users = h.users.SortByWorkload(r.Context(), users)
+	}
+
 	// Create a response with only username, name, and surname
 	type UserResponse struct {
 		Username string `json:"username"`
@@ -525,7 +529,7 @@ func (h *UserHandler) SendRecoveryLink(rw http.ResponseWriter, r *http.Request)
 	recoveryCode := uuid.New().String()
 
 	req := &struct {
-		Username string `json: username`
+		Username string `json:"username"`
 		Email    string `json:"email"`
 	}{}
 
@@ -614,7 +618,10 @@ func (u *UserHandler) DeleteUser(rw http.ResponseWriter, h *http.Request) {
 	}
 
 	// Perform the user deletion
-	err = u.users.Delete(ctx,user)
+	// Managers who still own projects can hand them over in the same request
+	reassignTo := h.URL.Query().Get("reassignTo")
+
+	err = u.users.Delete(ctx, user, reassignTo)
 	if err != nil {
 		if errors.Is(err, domain.ErrCodeExpired()) {
 			span.SetStatus(codes.Error, err.Error())
@@ -1,9 +1,12 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"project-management-app/microservices/users-service/domain"
@@ -28,7 +31,7 @@ type UserService struct {
 	projectServiceAddress string
 }
 
-func NewUserService(r *repositories.UserRepo) *UserService {
+func NewUserService(r *repositories.UserRepo, tracer trace.Tracer, projectServiceAddress string) *UserService {
 	cb := gobreaker.NewCircuitBreaker[interface{}](gobreaker.Settings{
 		Name:        "UserServiceCB",
 		MaxRequests: 1,
@@ -45,7 +48,7 @@ func NewUserService(r *repositories.UserRepo) *UserService {
 		Timeout: 5 * time.Second, // Globalni timeout
 	}
 
-	return &UserService{users: r, cb: cb, client: client, tracer :tracer}
+	return &UserService{users: r, cb: cb, client: client, tracer: tracer, projectServiceAddress: projectServiceAddress}
 }
 
 func (s UserService) Create(ctx context.Context, username, password, name, surname, email, roleString, activationCode string) (domain.User, error) {
@@ -138,13 +141,23 @@ func (s *UserService) RecoveryPassword(uuid string, password string, user *domai
 	return s.users.RecoveryPassword(uuid, hashedOldPassword, user)
 }
 
-func (us *UserService) Delete(ctx context.Context, user *domain.User) error {
+// Delete removes the user. A manager who still owns projects can only be
+// deleted when reassignTo names another manager to take those projects over.
+func (us *UserService) Delete(ctx context.Context, user *domain.User, reassignTo string) error {
     ctx, span := us.tracer.Start(ctx, "UserService.Delete")
     defer span.End()
 
     userID := user.Username
 
     if user.Role == domain.PROJECT_MANAGER {
+        if reassignTo != "" {
+            if err := us.reassignProjects(ctx, userID, reassignTo); err != nil {
+                span.RecordError(err)
+                span.SetStatus(codes.Error, err.Error())
+                return err
+            }
+        }
+
         url := fmt.Sprintf("http://projects-service:8000/projects/manager/%s", userID)
         req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
         if err != nil {
@@ -213,3 +226,35 @@ func (us *UserService) Delete(ctx context.Context, user *domain.User) error {
     return nil
 }
 
+// reassignProjects asks projects-service to hand every project of the manager
+// over to another manager.
+func (us *UserService) reassignProjects(ctx context.Context, from string, to string) error {
+	url := fmt.Sprintf("http://projects-service:8000/projects/manager/%s/reassign", from)
+
+	reqBody, err := json.Marshal(map[string]string{"username": to})
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Inject tracing headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := us.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error contacting projects-service: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("cannot reassign projects to %s: %s", to, string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"project-management-app/microservices/users-service/domain"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// SortByWorkload orders users from the least to the most loaded, by the open
+// estimated work tasks-service reports for them. The order is left unchanged
+// when tasks-service can't be reached.
+func (us *UserService) SortByWorkload(ctx context.Context, users []domain.User) []domain.User {
+	ctx, span := us.tracer.Start(ctx, "UserService.SortByWorkload")
+	defer span.End()
+
+	if len(users) < 2 {
+		return users
+	}
+
+	usernames := make([]string, 0, len(users))
+	for _, user := range users {
+		usernames = append(usernames, user.Username)
+	}
+
+	position, err := us.getWorkloadOrder(ctx, usernames)
+	if err != nil {
+		log.Println("Unable to sort users by workload:", err)
+		return users
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return position[users[i].Username] < position[users[j].Username]
+	})
+	return users
+}
+
+// getWorkloadOrder returns the position of each user in the workload list of
+// tasks-service, which is ordered from the least loaded user.
+func (us *UserService) getWorkloadOrder(ctx context.Context, usernames []string) (map[string]int, error) {
+	url := "http://tasks-service:8000/workload?users=" + url.QueryEscape(strings.Join(usernames, ","))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	// Inject tracing headers
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := us.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error contacting tasks-service: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from tasks-service: %v", resp.Status)
+	}
+
+	var workload []struct {
+		Username string `json:"username"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&workload); err != nil {
+		return nil, fmt.Errorf("error decoding workload: %v", err)
+	}
+
+	position := make(map[string]int, len(workload))
+	for i, user := range workload {
+		position[user.Username] = i
+	}
+	return position, nil
+}
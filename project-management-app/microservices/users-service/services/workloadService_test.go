@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"project-management-app/microservices/users-service/domain"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tasksService answers the workload request with a fixed status and body and
+// remembers the users it was asked about.
+type tasksService struct {
+	status int
+	body   string
+	asked  string
+}
+
+func (ts *tasksService) RoundTrip(r *http.Request) (*http.Response, error) {
+	ts.asked = r.URL.Query().Get("users")
+	return &http.Response{StatusCode: ts.status, Body: io.NopCloser(strings.NewReader(ts.body)), Request: r}, nil
+}
+
+func sortByWorkload(ts *tasksService, usernames ...string) []string {
+	us := &UserService{client: &http.Client{Transport: ts}, tracer: noop.NewTracerProvider().Tracer("")}
+	users := make([]domain.User, 0, len(usernames))
+	for _, username := range usernames {
+		users = append(users, domain.User{Username: username})
+	}
+
+	sorted := make([]string, 0, len(users))
+	for _, user := range us.SortByWorkload(context.Background(), users) {
+		sorted = append(sorted, user.Username)
+	}
+	return sorted
+}
+
+func TestSortByWorkload(t *testing.T) {
+	ts := &tasksService{status: http.StatusOK, body: `[{"username":"zoe"},{"username":"ivan"},{"username":"ana"}]`}
+	if got := strings.Join(sortByWorkload(ts, "ana", "ivan", "zoe"), ","); got != "zoe,ivan,ana" {
+		t.Errorf("sorted %s, want the order of tasks-service", got)
+	}
+	if ts.asked != "ana,ivan,zoe" {
+		t.Errorf("asked tasks-service about %q", ts.asked)
+	}
+}
+
+func TestSortByWorkloadKeepsTheOrderWithoutTasksService(t *testing.T) {
+	for status, body := range map[int]string{
+		http.StatusServiceUnavailable: `[{"username":"zoe"},{"username":"ana"}]`,
+		http.StatusOK:                 `not a workload`,
+	} {
+		ts := &tasksService{status: status, body: body}
+		if got := strings.Join(sortByWorkload(ts, "ana", "ivan", "zoe"), ","); got != "ana,ivan,zoe" {
+			t.Errorf("sorted %s when tasks-service answered %d %s", got, status, body)
+		}
+	}
+
+	ts := &tasksService{status: http.StatusOK, body: `[]`}
+	if got := sortByWorkload(ts, "ana"); len(got) != 1 || ts.asked != "" {
+		t.Errorf("asked tasks-service to sort %v", got)
+	}
+}